@@ -0,0 +1,246 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"strings"
+)
+
+// A Cloud Optimized GeoTIFF (COG) is a tiled TIFF file laid out so that
+// clients can read parts of it with few HTTP range requests:
+//
+//   1. Header (8 bytes, or 16 bytes for BigTIFF).
+//   2. Ghost area describing the layout, as written by GDAL.
+//   3. IFD of the full resolution image, then the IFDs of the overviews
+//      from the largest to the smallest, each with its pointer area.
+//   4. Tile data, from the smallest overview to the full resolution image,
+//      the tiles of each image in row-major order.
+//
+// See https://gdal.org/drivers/raster/cog.html
+
+// cogStructuralMetadata is the content of the ghost area. The trailing
+// space keeps the area at an even size.
+const cogStructuralMetadata = "LAYOUT=IFDS_BEFORE_DATA\n" +
+	"BLOCK_ORDER=ROW_MAJOR\n" +
+	"KNOWN_INCOMPATIBLE_EDITION=NO\n "
+
+const cogGhostAreaPrefix = "GDAL_STRUCTURAL_METADATA_SIZE="
+
+// cogGhostArea returns the ghost area written after the header.
+func cogGhostArea() []byte {
+	return []byte(fmt.Sprintf("%s%06d bytes\n%s", cogGhostAreaPrefix, len(cogStructuralMetadata), cogStructuralMetadata))
+}
+
+// EncodeCOG writes the image m to w as a Cloud Optimized GeoTIFF.
+//
+// The image is tiled with the tile size of opt, or 512x512 if it has none,
+// and reduced by halves into overviews with the options popt, as by
+// EncodePyramid, down to a tile by default. If popt is nil, the overviews
+// are reduced with ResamplingType_Average; SubIFDs are not supported.
+// Overviews are marked with NewSubfileType=Reduced. Georeferencing tags,
+// GDAL metadata, XMP, IPTC and Photoshop resources are only written to the
+// full resolution image, the GDAL nodata value and the ICC profile to all
+// images.
+func EncodeCOG(w io.Writer, m image.Image, opt *Options, popt *PyramidOptions) error {
+	var o Options
+	if opt != nil {
+		o = *opt
+	}
+	if o.TileWidth == 0 && o.TileLength == 0 {
+		o.TileWidth, o.TileLength = 512, 512
+	}
+	o.RowsPerStrip = 0
+	po := PyramidOptions{Resampling: ResamplingType_Average}
+	if popt != nil {
+		po = *popt
+	}
+	if po.SubIFDs {
+		return fmt.Errorf("tiff: EncodeCOG, overviews cannot be SubIFDs")
+	}
+	if po.MinSize == 0 {
+		po.MinSize = maxInt(o.TileWidth, o.TileLength)
+	}
+
+	images := append([]image.Image{m}, Overviews(m, po.Resampling, po.MinSize)...)
+	levels, blocks, counts, err := encodeLevels(images, &o)
+	if err != nil {
+		return err
+	}
+
+	offsets, ifdOffsets, end := cogLayout(levels, counts)
+	if !levels[0].bigTiff && end > math.MaxUint32 {
+		for _, e := range levels {
+			e.bigTiff = true
+		}
+		offsets, ifdOffsets, end = cogLayout(levels, counts)
+	}
+
+	h := levels[0].header(ifdOffsets[0])
+	buf := bytes.NewBuffer(h.Bytes())
+	buf.Write(cogGhostArea())
+	for k, e := range levels {
+		var next int64
+		if k+1 < len(levels) {
+			next = ifdOffsets[k+1]
+		}
+		buf.Write(make([]byte, ifdOffsets[k]-int64(buf.Len())))
+		buf.Write(ifdBytes(h, ifdOffsets[k], next, e.ifd(offsets[k], counts[k])))
+	}
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	for k := len(levels) - 1; k >= 0; k-- {
+		for _, b := range blocks[k] {
+			if _, err := w.Write(b); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cogLayout returns the block offsets and the IFD offsets of the levels of
+// a COG whose blocks have the given sizes, and the size of the file.
+func cogLayout(levels []*imageEncoder, counts [][]uint64) (offsets [][]uint64, ifdOffsets []int64, end int64) {
+	h := levels[0].header(0)
+	off := int64(h.HeadSize()) + int64(len(cogGhostArea()))
+	offsets = make([][]uint64, len(levels))
+	ifdOffsets = make([]int64, len(levels))
+	for k, e := range levels {
+		// The IFD size does not depend on the values of the offsets.
+		offsets[k] = make([]uint64, len(counts[k]))
+		off += off % 2
+		ifdOffsets[k] = off
+		off += int64(len(ifdBytes(h, off, 0, e.ifd(offsets[k], counts[k]))))
+	}
+	for k := len(levels) - 1; k >= 0; k-- {
+		for i, n := range counts[k] {
+			offsets[k][i] = uint64(off)
+			off += int64(n)
+		}
+	}
+	return offsets, ifdOffsets, off
+}
+
+// A COGError lists the ways in which a file does not follow the layout of
+// a Cloud Optimized GeoTIFF.
+type COGError struct {
+	Problems []string
+}
+
+func (e *COGError) Error() string {
+	return "tiff: not a cloud optimized GeoTIFF: " + strings.Join(e.Problems, "; ")
+}
+
+// ValidateCOG checks the file read from r against the layout rules of a
+// Cloud Optimized GeoTIFF. It returns a *COGError listing the violations,
+// or another error if the file cannot be read.
+//
+// The rules are those of GDAL's validate_cloud_optimized_geotiff.py:
+// images larger than 512 pixels are tiled, overviews are marked as
+// reduced and ordered from the largest to the smallest, all IFDs come
+// before the image data, the data of smaller overviews comes first, and
+// the tiles of each image are stored in row-major order. The ghost area
+// written by GDAL must be present and announce that layout.
+func ValidateCOG(r io.ReadSeeker) error {
+	// p is not closed, as that would close r.
+	p, err := OpenReader(r)
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	fail := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	ghost := make([]byte, 512)
+	if _, err = r.Seek(int64(p.Header.HeadSize()), 0); err != nil {
+		return err
+	}
+	n, _ := io.ReadFull(r, ghost)
+	ghost = ghost[:n]
+	if !bytes.HasPrefix(ghost, []byte(cogGhostAreaPrefix)) {
+		fail("no ghost area after the header")
+	} else {
+		for _, s := range []string{"LAYOUT=IFDS_BEFORE_DATA\n", "BLOCK_ORDER=ROW_MAJOR\n"} {
+			if !bytes.Contains(ghost, []byte(s)) {
+				fail("ghost area lacks %q", strings.TrimSpace(s))
+			}
+		}
+	}
+
+	// The full resolution image and its overviews, skipping masks.
+	var images []*IFD
+	for i, list := range p.Ifd {
+		ifd := list[0]
+		subfile, _ := ifd.TagGetter().GetNewSubfileType()
+		if TagValue_NewSubfileType(subfile)&TagValue_NewSubfileType_Mask != 0 {
+			continue
+		}
+		if i > 0 && TagValue_NewSubfileType(subfile)&TagValue_NewSubfileType_Reduced == 0 {
+			fail("IFD %d is not a reduced resolution image", i)
+		}
+		images = append(images, ifd)
+	}
+
+	var firstData int64 = math.MaxInt64
+	var dataStarts []int64
+	for k, ifd := range images {
+		size := ifd.Bounds().Size()
+		tags := ifd.TagGetter()
+		offsets, tiled := tags.GetTileOffsets()
+		if !tiled {
+			if size.X > 512 || size.Y > 512 {
+				fail("image %d is not tiled", k)
+			}
+			offsets, _ = tags.GetStripOffsets()
+		}
+		if k > 0 {
+			prev := images[k-1].Bounds().Size()
+			if size.X > prev.X || size.Y > prev.Y {
+				fail("overview %d is larger than the image before it", k)
+			}
+			if ifd.ThisIFD < images[k-1].ThisIFD {
+				fail("IFD of image %d comes before the IFD of image %d", k, k-1)
+			}
+		}
+		start := int64(math.MaxInt64)
+		for i, off := range offsets {
+			if off == 0 {
+				continue // Sparse block.
+			}
+			if i > 0 && off < offsets[i-1] {
+				fail("blocks of image %d are not in row-major order", k)
+				break
+			}
+			if off < start {
+				start = off
+			}
+		}
+		if start < firstData {
+			firstData = start
+		}
+		dataStarts = append(dataStarts, start)
+	}
+	for k, ifd := range images {
+		if ifd.ThisIFD > firstData {
+			fail("IFD of image %d comes after the image data", k)
+		}
+		if k > 0 && dataStarts[k] != math.MaxInt64 && dataStarts[k-1] < dataStarts[k] {
+			fail("data of image %d comes before the data of the smaller image %d", k-1, k)
+		}
+	}
+
+	if problems != nil {
+		return &COGError{Problems: problems}
+	}
+	return nil
+}
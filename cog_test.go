@@ -0,0 +1,98 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"image"
+	"io/ioutil"
+	"testing"
+)
+
+func TestEncodeCOG(t *testing.T) {
+	m := image.NewRGBA(image.Rect(0, 0, 1100, 700))
+	for i := range m.Pix {
+		m.Pix[i] = uint8(i * 7 / 5)
+	}
+	for _, tt := range []struct {
+		bigTiff    bool
+		popt       *PyramidOptions
+		resampling ResamplingType
+	}{
+		{false, nil, ResamplingType_Average},
+		{true, &PyramidOptions{Resampling: ResamplingType_Gaussian}, ResamplingType_Gaussian},
+	} {
+		bigTiff := tt.bigTiff
+		opt := &Options{
+			TileWidth:    256,
+			TileLength:   256,
+			Compression:  TagValue_CompressionType_Deflate,
+			Predictor:    true,
+			BigTiff:      bigTiff,
+			GeoKeys:      epsgKeys(t, GeoModelType_Projected, 3857),
+			GeoTransform: &GeoTransform{0, 10, 0, 0, 0, -10},
+		}
+		var out bytes.Buffer
+		if err := EncodeCOG(&out, m, opt, tt.popt); err != nil {
+			t.Fatal(err)
+		}
+		if err := ValidateCOG(bytes.NewReader(out.Bytes())); err != nil {
+			t.Fatal(err)
+		}
+
+		p, err := OpenReader(bytes.NewReader(out.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Header.IsBigTiff() != bigTiff {
+			t.Fatalf("BigTIFF = %v, want %v", p.Header.IsBigTiff(), bigTiff)
+		}
+		wantSizes := []image.Point{{1100, 700}, {550, 350}, {275, 175}, {138, 88}}
+		if len(p.Ifd) != len(wantSizes) {
+			t.Fatalf("got %d IFDs, want %d", len(p.Ifd), len(wantSizes))
+		}
+		var want image.Image = m
+		for i, size := range wantSizes {
+			ifd := p.Ifd[i][0]
+			if got := ifd.Bounds().Size(); got != size {
+				t.Errorf("IFD %d: size = %v, want %v", i, got, size)
+			}
+			subfile, _ := ifd.TagGetter().GetNewSubfileType()
+			if reduced := subfile == int64(TagValue_NewSubfileType_Reduced); reduced != (i > 0) {
+				t.Errorf("IFD %d: NewSubfileType = %d", i, subfile)
+			}
+			if _, ok := ifd.TagGetter().GetGeoKeyDirectoryTag(); ok != (i == 0) {
+				t.Errorf("IFD %d: GeoKeyDirectoryTag present = %v", i, ok)
+			}
+			got, err := p.DecodeImage(i, 0)
+			if err != nil {
+				t.Fatalf("IFD %d: %v", i, err)
+			}
+			compare(t, want, got)
+			want = ReduceImage(want, tt.resampling)
+		}
+		p.Close()
+	}
+	if err := EncodeCOG(ioutil.Discard, m, nil, &PyramidOptions{SubIFDs: true}); err == nil {
+		t.Errorf("SubIFDs: no error")
+	}
+}
+
+func TestValidateCOG_bad(t *testing.T) {
+	m := image.NewGray(image.Rect(0, 0, 600, 600))
+	for _, opt := range []*Options{
+		{TileWidth: 256, TileLength: 256},
+		{RowsPerStrip: 16},
+	} {
+		var out bytes.Buffer
+		if err := Encode(&out, m, opt); err != nil {
+			t.Fatal(err)
+		}
+		err := ValidateCOG(bytes.NewReader(out.Bytes()))
+		if _, ok := err.(*COGError); !ok {
+			t.Fatalf("%+v: err = %v, want *COGError", opt, err)
+		}
+	}
+}
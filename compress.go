@@ -6,6 +6,7 @@ package tiff
 
 import (
 	"bufio"
+	"bytes"
 	"compress/zlib"
 	"fmt"
 	"io"
@@ -14,34 +15,40 @@ import (
 )
 
 func (p TagValue_CompressionType) Decode(r io.Reader, width, height int) (data []byte, err error) {
+	return p.decode(r, width, height, 0)
+}
+
+// decode is like Decode, but fails with a *LimitError if the decompressed
+// data is larger than max bytes, if max is positive.
+func (p TagValue_CompressionType) decode(r io.Reader, width, height int, max int64) (data []byte, err error) {
 	switch p {
 	case TagValue_CompressionType_None, TagValue_CompressionType_Nil:
-		return p.decode_None(r)
+		return p.decode_None(r, max)
 	case TagValue_CompressionType_CCITT:
 		return p.decode_CCITT(r)
 	case TagValue_CompressionType_G3:
-		return p.decode_G3(r, width, height)
+		return p.decode_G3(r, width, height, max)
 	case TagValue_CompressionType_G4:
-		return p.decode_G4(r, width, height)
+		return p.decode_G4(r, width, height, max)
 	case TagValue_CompressionType_LZW:
-		return p.decode_LZW(r)
+		return p.decode_LZW(r, max)
 	case TagValue_CompressionType_JPEGOld:
 		return p.decode_JPEGOld(r)
 	case TagValue_CompressionType_JPEG:
 		return p.decode_JPEG(r)
 	case TagValue_CompressionType_Deflate:
-		return p.decode_Deflate(r)
+		return p.decode_Deflate(r, max)
 	case TagValue_CompressionType_PackBits:
-		return p.decode_PackBits(r)
+		return p.decode_PackBits(r, max)
 	case TagValue_CompressionType_DeflateOld:
-		return p.decode_DeflateOld(r)
+		return p.decode_DeflateOld(r, max)
 	}
 	err = fmt.Errorf("tiff: unsupport %v compression type", int(p))
 	return
 }
 
-func (p TagValue_CompressionType) decode_None(r io.Reader) (data []byte, err error) {
-	data, err = io.ReadAll(r)
+func (p TagValue_CompressionType) decode_None(r io.Reader, max int64) (data []byte, err error) {
+	data, err = readAll(r, max)
 	return
 }
 
@@ -50,11 +57,15 @@ func (p TagValue_CompressionType) decode_CCITT(r io.Reader) (data []byte, err er
 	return
 }
 
-func (p TagValue_CompressionType) decode_G3(r io.Reader, width, height int) (data []byte, err error) {
-	return p.decode_G4(r, width, height)
+func (p TagValue_CompressionType) decode_G3(r io.Reader, width, height int, max int64) (data []byte, err error) {
+	return p.decode_G4(r, width, height, max)
 }
 
-func (p TagValue_CompressionType) decode_G4(r io.Reader, width, height int) (data []byte, err error) {
+func (p TagValue_CompressionType) decode_G4(r io.Reader, width, height int, max int64) (data []byte, err error) {
+	// The pixels are decoded into one byte each.
+	if err = checkBlock(int64(width)*int64(height), max); err != nil {
+		return
+	}
 	br, ok := r.(io.ByteReader)
 	if !ok {
 		br = bufio.NewReader(r)
@@ -63,9 +74,9 @@ func (p TagValue_CompressionType) decode_G4(r io.Reader, width, height int) (dat
 	return fax.DecodeG4Pixels(br, width, height)
 }
 
-func (p TagValue_CompressionType) decode_LZW(r io.Reader) (data []byte, err error) {
+func (p TagValue_CompressionType) decode_LZW(r io.Reader, max int64) (data []byte, err error) {
 	lzwReader := newLzwReader(r, lzwMSB, 8)
-	data, err = io.ReadAll(lzwReader)
+	data, err = readAll(lzwReader, max)
 	lzwReader.Close()
 	return
 }
@@ -81,27 +92,27 @@ func (p TagValue_CompressionType) decode_JPEG(r io.Reader) (data []byte, err err
 	return
 }
 
-func (p TagValue_CompressionType) decode_Deflate(r io.Reader) (data []byte, err error) {
+func (p TagValue_CompressionType) decode_Deflate(r io.Reader, max int64) (data []byte, err error) {
 	zlibReader, err := zlib.NewReader(r)
 	if err != nil {
 		return nil, err
 	}
-	data, err = io.ReadAll(zlibReader)
+	data, err = readAll(zlibReader, max)
 	zlibReader.Close()
 	return
 }
 
-func (p TagValue_CompressionType) decode_DeflateOld(r io.Reader) (data []byte, err error) {
+func (p TagValue_CompressionType) decode_DeflateOld(r io.Reader, max int64) (data []byte, err error) {
 	zlibReader, err := zlib.NewReader(r)
 	if err != nil {
 		return nil, err
 	}
-	data, err = io.ReadAll(zlibReader)
+	data, err = readAll(zlibReader, max)
 	zlibReader.Close()
 	return
 }
 
-func (p TagValue_CompressionType) decode_PackBits(r io.Reader) (data []byte, err error) {
+func (p TagValue_CompressionType) decode_PackBits(r io.Reader, max int64) (data []byte, err error) {
 	type byteReader interface {
 		io.Reader
 		io.ByteReader
@@ -141,5 +152,40 @@ func (p TagValue_CompressionType) decode_PackBits(r io.Reader) (data []byte, err
 			}
 			dst = append(dst, buf[:1-code]...)
 		}
+		if max > 0 && int64(len(dst)) > max {
+			return nil, &LimitError{Limit: "MaxBlockBytes", Max: max}
+		}
+	}
+}
+
+// Encode compresses the uncompressed samples of a width x height block.
+func (p TagValue_CompressionType) Encode(data []byte, width, height int) (out []byte, err error) {
+	switch p {
+	case TagValue_CompressionType_None, TagValue_CompressionType_Nil:
+		return p.encode_None(data)
+	case TagValue_CompressionType_Deflate:
+		return p.encode_Deflate(data)
+	case TagValue_CompressionType_LZW:
+		return lzwEncode(data), nil
 	}
+	err = fmt.Errorf("tiff: unsupport %v compression type", int(p))
+	return
+}
+
+func (p TagValue_CompressionType) encode_None(data []byte) (out []byte, err error) {
+	out = data
+	return
+}
+
+func (p TagValue_CompressionType) encode_Deflate(data []byte) (out []byte, err error) {
+	var buf bytes.Buffer
+	zlibWriter := zlib.NewWriter(&buf)
+	if _, err = zlibWriter.Write(data); err != nil {
+		return
+	}
+	if err = zlibWriter.Close(); err != nil {
+		return
+	}
+	out = buf.Bytes()
+	return
 }
@@ -14,6 +14,7 @@ import (
 func DecodeConfig(r io.Reader) (cfg image.Config, err error) {
 	var p *Reader
 	if p, err = OpenReader(r); err != nil {
+		p.Close()
 		return
 	}
 	defer p.Close()
@@ -24,6 +25,7 @@ func DecodeConfig(r io.Reader) (cfg image.Config, err error) {
 func DecodeConfigAll(r io.Reader) (cfg [][]image.Config, errors [][]error, err error) {
 	var p *Reader
 	if p, err = OpenReader(r); err != nil {
+		p.Close()
 		return
 	}
 	defer p.Close()
@@ -57,6 +59,7 @@ Loop:
 func Decode(r io.Reader) (m image.Image, err error) {
 	var p *Reader
 	if p, err = OpenReader(r); err != nil {
+		p.Close()
 		return
 	}
 	defer p.Close()
@@ -68,6 +71,7 @@ func Decode(r io.Reader) (m image.Image, err error) {
 func DecodeAll(r io.Reader) (m [][]image.Image, errors [][]error, err error) {
 	var p *Reader
 	if p, err = OpenReader(r); err != nil {
+		p.Close()
 		return
 	}
 	defer p.Close()
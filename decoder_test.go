@@ -6,10 +6,13 @@ package tiff
 
 import (
 	"bytes"
+	"encoding/binary"
 	"image"
 	_ "image/png"
+	"io"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -227,3 +230,293 @@ func benchmarkDecode(b *testing.B, filename string) {
 
 func BenchmarkDecodeCompressed(b *testing.B)   { benchmarkDecode(b, "video-001.tiff") }
 func BenchmarkDecodeUncompressed(b *testing.B) { benchmarkDecode(b, "video-001-uncompressed.tiff") }
+
+func TestDecodeRegion(t *testing.T) {
+	for _, name := range []string{
+		"video-001-tile-64x64.tiff",
+		"video-001-strip-64.tiff",
+		"video-001-paletted.tiff",
+		"video-001-gray-16bit.tiff",
+		"bw-deflate.tiff",
+	} {
+		data, err := ioutil.ReadFile(testdataDir + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := OpenReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		full, err := p.DecodeImage(0, 0)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		b := full.Bounds()
+		for _, r := range []image.Rectangle{
+			b,
+			image.Rect(0, 0, 1, 1),
+			image.Rect(10, 20, 90, 100),
+			image.Rect(64, 64, 128, 128),
+			image.Rect(b.Dx()-5, b.Dy()-7, b.Dx()+100, b.Dy()+100),
+		} {
+			if r.Intersect(b).Empty() {
+				continue
+			}
+			m, err := p.DecodeRegion(0, 0, r)
+			if err != nil {
+				t.Fatalf("%s, %v: %v", name, r, err)
+			}
+			want := full.(interface {
+				SubImage(image.Rectangle) image.Image
+			}).SubImage(r)
+			if m.Bounds() != want.Bounds() || !imageEqual(m, want) {
+				t.Errorf("%s, %v: region differs, bounds %v", name, r, m.Bounds())
+			}
+		}
+		if _, err := p.DecodeRegion(0, 0, image.Rect(-10, -10, 0, 0)); err == nil {
+			t.Errorf("%s: empty region, no error", name)
+		}
+		p.Close()
+	}
+}
+
+func TestDecodeWorkers(t *testing.T) {
+	m := image.NewRGBA(image.Rect(0, 0, 300, 200))
+	for i := range m.Pix {
+		m.Pix[i] = uint8(i * 7 / 5)
+	}
+	var buf bytes.Buffer
+	if err := Encode(&buf, m, &Options{Compression: TagValue_CompressionType_Deflate, Predictor: true, TileWidth: 32, TileLength: 48}); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{"tiled deflate": buf.Bytes()}
+	for _, name := range []string{"video-001-tile-64x64.tiff", "video-001-strip-64.tiff", "blue-purple-pink.lzwcompressed.tiff"} {
+		data, err := ioutil.ReadFile(testdataDir + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[name] = data
+	}
+	for name, data := range files {
+		var want image.Image
+		for _, workers := range []int{0, 1, 3, 16, -1} {
+			p, err := OpenReader(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			p.Workers = workers
+			got, err := p.DecodeImage(0, 0)
+			if err != nil {
+				t.Fatalf("%s, %d workers: %v", name, workers, err)
+			}
+			if want == nil {
+				want = got
+			} else if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s, %d workers: image differs", name, workers)
+			}
+			region, err := p.DecodeRegion(0, 0, image.Rect(33, 17, 95, 130))
+			if err != nil || !imageEqual(region, want.(interface {
+				SubImage(image.Rectangle) image.Image
+			}).SubImage(region.Bounds())) {
+				t.Errorf("%s, %d workers: region differs, %v", name, workers, err)
+			}
+			p.Close()
+		}
+	}
+
+	// The error of the first bad block is returned.
+	p, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	offsets, _ := p.Ifd[0][0].TagGetter().GetTileOffsets()
+	bad := append([]byte{}, buf.Bytes()...)
+	for _, k := range []int{5, 20} {
+		copy(bad[offsets[k]:], "garbage!")
+	}
+	var errs []string
+	for _, workers := range []int{0, 8} {
+		p, err := OpenReader(bytes.NewReader(bad))
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.Workers = workers
+		if _, err = p.DecodeImage(0, 0); err == nil {
+			t.Fatalf("%d workers: no error", workers)
+		}
+		errs = append(errs, err.Error())
+	}
+	if errs[0] != errs[1] {
+		t.Errorf("errors differ: %q", errs)
+	}
+}
+
+func TestScanlineReader(t *testing.T) {
+	for _, name := range []string{
+		"video-001-tile-64x64.tiff",
+		"video-001-strip-64.tiff",
+		"video-001-paletted.tiff",
+		"video-001-gray-16bit.tiff",
+		"bw-deflate.tiff",
+	} {
+		data, err := ioutil.ReadFile(testdataDir + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := OpenReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		full, err := p.DecodeImage(0, 0)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		sub := full.(interface {
+			SubImage(image.Rectangle) image.Image
+		})
+
+		s, err := p.ScanlineReader(0, 0)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if s.Bounds() != full.Bounds() {
+			t.Fatalf("%s: Bounds = %v", name, s.Bounds())
+		}
+		for y := 0; ; y++ {
+			row, err := s.ReadRow()
+			if err == io.EOF {
+				if y != full.Bounds().Dy() {
+					t.Errorf("%s: %d rows, want %d", name, y, full.Bounds().Dy())
+				}
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s, row %d: %v", name, y, err)
+			}
+			want := sub.SubImage(image.Rect(0, y, full.Bounds().Dx(), y+1))
+			if row.Bounds() != want.Bounds() || !imageEqual(row, want) {
+				t.Fatalf("%s, row %d: differs, bounds %v", name, y, row.Bounds())
+			}
+		}
+
+		// Bands follow the rows already read.
+		s, _ = p.ScanlineReader(0, 0)
+		s.ReadRow()
+		y := 1
+		for {
+			band, err := s.ReadBand()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			b := band.Bounds()
+			if b.Min.Y != y || !imageEqual(band, sub.SubImage(b)) {
+				t.Fatalf("%s: band %v differs", name, b)
+			}
+			y = b.Max.Y
+		}
+		if y != full.Bounds().Dy() {
+			t.Errorf("%s: bands end at %d", name, y)
+		}
+		p.Close()
+	}
+}
+
+// TestOpenReaderBrokenIFDs tests that links to IFDs already read or
+// outside the file stop the chain with an IFDError, and that the IFDs
+// read before are kept.
+func TestOpenReaderBrokenIFDs(t *testing.T) {
+	m := image.NewGray(image.Rect(0, 0, 64, 64))
+	for _, subIFDs := range []bool{false, true} {
+		var buf bytes.Buffer
+		if err := EncodePyramid(&buf, m, nil, &PyramidOptions{MinSize: 16, SubIFDs: subIFDs}); err != nil {
+			t.Fatal(err)
+		}
+		p, err := OpenReader(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var offsets []int64 // The IFDs of the chain, main or SubIFDs.
+		for i := range p.Ifd {
+			for j := range p.Ifd[i] {
+				offsets = append(offsets, p.Ifd[i][j].ThisIFD)
+			}
+		}
+		if len(offsets) != 3 {
+			t.Fatalf("SubIFDs %v: %d IFDs", subIFDs, len(offsets))
+		}
+		// The link to the last IFD: the NextIFD of the one before, or the
+		// last value of the SubIFD tag.
+		var link, parent int64
+		if subIFDs {
+			link = p.Ifd[0][0].EntryMap[TagType_SubIFD].Offset + 4
+			parent = offsets[0]
+		} else {
+			link = offsets[1] + 2 + 12*int64(len(p.Ifd[1][0].EntryMap))
+			parent = offsets[1]
+		}
+		p.Close()
+
+		for _, tt := range []struct {
+			target int64
+			cycle  bool
+		}{
+			{offsets[0], true},
+			{offsets[1], true},
+			{int64(buf.Len()), false},
+			{3, false},
+		} {
+			data := append([]byte(nil), buf.Bytes()...)
+			binary.LittleEndian.PutUint32(data[link:], uint32(tt.target))
+			p, err := OpenReader(bytes.NewReader(data))
+			want := &IFDError{Offset: tt.target, Parent: parent, SubIFD: subIFDs, Cycle: tt.cycle}
+			if e, ok := err.(*IFDError); !ok || *e != *want {
+				t.Fatalf("SubIFDs %v, link to %d: err = %v, want %v", subIFDs, tt.target, err, want)
+			}
+			n := 0
+			for i := range p.Ifd {
+				n += len(p.Ifd[i])
+			}
+			if n != 2 {
+				t.Errorf("SubIFDs %v, link to %d: %d IFDs read", subIFDs, tt.target, n)
+			}
+			if _, err := p.DecodeImage(0, 0); err != nil {
+				t.Errorf("SubIFDs %v, link to %d: %v", subIFDs, tt.target, err)
+			}
+			p.Close()
+		}
+	}
+}
+
+func TestOpenReaderBrokenSubIFD(t *testing.T) {
+	var buf bytes.Buffer
+	m := image.NewGray(image.Rect(0, 0, 64, 64))
+	if err := EncodePyramid(&buf, m, nil, &PyramidOptions{MinSize: 16, SubIFDs: true}); err != nil {
+		t.Fatal(err)
+	}
+	p, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ifd0, last := p.Ifd[0][0], p.Ifd[0][2].ThisIFD
+	p.Close()
+
+	// The last SubIFD of IFD 0 is out of range, and becomes IFD 1 instead.
+	data := append([]byte(nil), buf.Bytes()...)
+	binary.LittleEndian.PutUint32(data[ifd0.EntryMap[TagType_SubIFD].Offset+4:], uint32(len(data)))
+	binary.LittleEndian.PutUint32(data[ifd0.ThisIFD+2+12*int64(len(ifd0.EntryMap)):], uint32(last))
+	p, err = OpenReader(bytes.NewReader(data))
+	want := &IFDError{Offset: int64(len(data)), Parent: ifd0.ThisIFD, SubIFD: true}
+	if e, ok := err.(*IFDError); !ok || *e != *want {
+		t.Fatalf("err = %v, want %v", err, want)
+	}
+	defer p.Close()
+	if len(p.Ifd) != 2 || len(p.Ifd[0]) != 2 || len(p.Ifd[1]) != 1 || p.Ifd[1][0].ThisIFD != last {
+		t.Fatalf("IFDs = %v", p.Ifd)
+	}
+	if _, err := p.DecodeImage(1, 0); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,567 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/dhushon/tiff/internal/ljpeg"
+)
+
+// DNG is a raw image of the Adobe Digital Negative format, version 1.4.
+//
+// A DNG file is a TIFF file whose IFD 0 holds the camera and color tags
+// and usually a preview. The sensor data is in the raw IFD, which is IFD 0
+// itself or one of its SubIFDs: either a color filter array (CFA) mosaic
+// with one sample per pixel, or LinearRaw data with a sample per color.
+type DNG struct {
+	Main *IFD // IFD 0.
+	Raw  *IFD // The full resolution raw IFD.
+
+	r io.ReadSeeker
+}
+
+// DNG returns the DNG image of the file. It fails if the file has no
+// DNGVersion tag or no raw IFD.
+func (p *Reader) DNG() (*DNG, error) {
+	if len(p.Ifd) == 0 || p.Ifd[0][0] == nil {
+		return nil, fmt.Errorf("tiff: Reader.DNG, no image")
+	}
+	main := p.Ifd[0][0]
+	if _, ok := main.TagGetter().GetDNGVersion(); !ok {
+		return nil, fmt.Errorf("tiff: Reader.DNG, not a DNG file")
+	}
+	for _, ifd := range p.Ifd[0] {
+		if ifd == nil {
+			continue
+		}
+		subfileType, _ := ifd.TagGetter().GetNewSubfileType()
+		photometric, _ := ifd.TagGetter().GetPhotometricInterpretation()
+		if subfileType == 0 && (photometric == TagValue_PhotometricType_CFA || photometric == TagValue_PhotometricType_LinearRaw) {
+			return &DNG{Main: main, Raw: ifd, r: p.rs}, nil
+		}
+	}
+	return nil, fmt.Errorf("tiff: Reader.DNG, no raw IFD")
+}
+
+// entry returns a tag of the raw IFD, or else of IFD 0. Raw tags are in
+// the raw IFD and color tags in IFD 0, but writers are not always strict.
+func (p *DNG) entry(tag TagType) *IFDEntry {
+	if e, ok := p.Raw.EntryMap[tag]; ok {
+		return e
+	}
+	return p.Main.EntryMap[tag]
+}
+
+// Version returns the DNGVersion, such as "1.4.0.0".
+func (p *DNG) Version() string {
+	v, _ := p.Main.TagGetter().GetDNGVersion()
+	s := make([]string, len(v))
+	for i := range v {
+		s[i] = fmt.Sprint(v[i])
+	}
+	return strings.Join(s, ".")
+}
+
+// A DNGCFA is the color filter array of a CFA raw image. Its pattern is
+// repeated from the top-left corner of the active area.
+type DNGCFA struct {
+	Width, Height int   // The size of the repeated pattern.
+	Pattern       []int // The plane of each site of the pattern, row by row.
+
+	// PlaneColors are the colors of the planes: 0 red, 1 green, 2 blue,
+	// 3 cyan, 4 magenta, 5 yellow and 6 white.
+	PlaneColors []int
+}
+
+// Plane returns the plane of the pixel x, y of the active area.
+func (p *DNGCFA) Plane(x, y int) int {
+	return p.Pattern[(y%p.Height)*p.Width+x%p.Width]
+}
+
+// CFA returns the color filter array of a CFA raw image.
+func (p *DNG) CFA() (*DNGCFA, error) {
+	g := p.Raw.TagGetter()
+	if photometric, _ := g.GetPhotometricInterpretation(); photometric != TagValue_PhotometricType_CFA {
+		return nil, fmt.Errorf("tiff: DNG.CFA, not a CFA image")
+	}
+	if layout, _ := g.GetCFALayout(); layout != 1 {
+		return nil, fmt.Errorf("tiff: DNG.CFA, unsupported CFA layout %d", layout)
+	}
+	dim, _ := g.GetCFARepeatPatternDim()
+	pattern, _ := g.GetCFAPattern()
+	if len(dim) != 2 || dim[0] <= 0 || dim[1] <= 0 || int64(len(pattern)) != dim[0]*dim[1] {
+		return nil, fmt.Errorf("tiff: DNG.CFA, bad CFA pattern %v of %v", pattern, dim)
+	}
+	cfa := &DNGCFA{Width: int(dim[1]), Height: int(dim[0]), PlaneColors: []int{0, 1, 2}}
+	if colors, ok := g.GetCFAPlaneColor(); ok {
+		cfa.PlaneColors = make([]int, len(colors))
+		for i, c := range colors {
+			cfa.PlaneColors[i] = int(c)
+		}
+	}
+	for _, c := range pattern {
+		plane := -1
+		for i, pc := range cfa.PlaneColors {
+			if int64(pc) == c {
+				plane = i
+			}
+		}
+		if plane < 0 {
+			return nil, fmt.Errorf("tiff: DNG.CFA, color %d is not a plane color", c)
+		}
+		cfa.Pattern = append(cfa.Pattern, plane)
+	}
+	return cfa, nil
+}
+
+// ActiveArea returns the area of the raw image that holds image data,
+// without masked pixels. By default it is the whole image.
+func (p *DNG) ActiveArea() image.Rectangle {
+	width, _ := p.Raw.TagGetter().GetImageWidth()
+	height, _ := p.Raw.TagGetter().GetImageLength()
+	r := image.Rect(0, 0, int(width), int(height))
+	if v, ok := p.Raw.TagGetter().GetActiveArea(); ok && len(v) == 4 {
+		// The tag is top, left, bottom, right.
+		r = image.Rect(int(v[1]), int(v[0]), int(v[3]), int(v[2])).Intersect(r)
+	}
+	return r
+}
+
+// DefaultCrop returns the area of the active area that is rendered, in
+// coordinates relative to the top-left corner of the active area.
+func (p *DNG) DefaultCrop() image.Rectangle {
+	active := p.ActiveArea()
+	r := image.Rect(0, 0, active.Dx(), active.Dy())
+	origin, ok1 := p.Raw.TagGetter().GetDefaultCropOrigin()
+	size, ok2 := p.Raw.TagGetter().GetDefaultCropSize()
+	if ok1 && ok2 && len(origin) == 2 && len(size) == 2 {
+		x, y := int(math.Round(origin[0])), int(math.Round(origin[1]))
+		r = image.Rect(x, y, x+int(math.Round(size[0])), y+int(math.Round(size[1]))).Intersect(r)
+	}
+	return r
+}
+
+// matrix returns the values of a matrix tag, or nil.
+func (p *DNG) matrix(tag TagType) []float64 {
+	v, _ := entryFloats(p.entry(tag))
+	return v
+}
+
+// ColorMatrix returns the ColorMatrix1 or ColorMatrix2 tag, for i = 1 or
+// 2: the matrix from XYZ to reference camera colors under the calibration
+// illuminant i, of one row of 3 values per color plane.
+func (p *DNG) ColorMatrix(i int) []float64 {
+	return p.matrix(dngTags(i, TagType_ColorMatrix1, TagType_ColorMatrix2))
+}
+
+// CameraCalibration returns the CameraCalibration1 or CameraCalibration2
+// tag, the square matrix from reference to individual camera colors.
+func (p *DNG) CameraCalibration(i int) []float64 {
+	return p.matrix(dngTags(i, TagType_CameraCalibration1, TagType_CameraCalibration2))
+}
+
+// ForwardMatrix returns the ForwardMatrix1 or ForwardMatrix2 tag, the
+// matrix from white balanced camera colors to XYZ D50, of 3 rows.
+func (p *DNG) ForwardMatrix(i int) []float64 {
+	return p.matrix(dngTags(i, TagType_ForwardMatrix1, TagType_ForwardMatrix2))
+}
+
+// CalibrationIlluminant returns the CalibrationIlluminant1 or
+// CalibrationIlluminant2 tag, an EXIF LightSource value.
+func (p *DNG) CalibrationIlluminant(i int) (int, bool) {
+	v, ok := entryInt(p.entry(dngTags(i, TagType_CalibrationIlluminant1, TagType_CalibrationIlluminant2)))
+	return int(v), ok
+}
+
+// AsShotNeutral returns the camera colors of a neutral in the scene.
+func (p *DNG) AsShotNeutral() []float64 {
+	return p.matrix(TagType_AsShotNeutral)
+}
+
+// AnalogBalance returns the gain of each color plane applied to the raw
+// values, or nil for a gain of 1.
+func (p *DNG) AnalogBalance() []float64 {
+	return p.matrix(TagType_AnalogBalance)
+}
+
+// BaselineExposure returns the exposure compensation, in EV units, that
+// the raw values need to be rendered.
+func (p *DNG) BaselineExposure() float64 {
+	v, _ := entryFloat(p.entry(TagType_BaselineExposure))
+	return v
+}
+
+func dngTags(i int, tag1, tag2 TagType) TagType {
+	if i == 2 {
+		return tag2
+	}
+	return tag1
+}
+
+// DecodeRaw returns the raw values of the sensor, as stored: an
+// *image.Gray16 for CFA images and an *image.RGBA64 for LinearRaw images
+// of 3 samples.
+func (p *DNG) DecodeRaw() (image.Image, error) {
+	pix, spp, r, err := p.decodeSamples()
+	if err != nil {
+		return nil, err
+	}
+	return dngImage(pix, spp, r)
+}
+
+// DecodeLinear returns the linear values of the active area: the raw
+// values are mapped through the LinearizationTable, and BlackLevel and
+// WhiteLevel are scaled to 0 and 0xffff.
+// The result is an *image.Gray16 for CFA images and an *image.RGBA64 for
+// LinearRaw images, with its origin at the top-left of the active area.
+func (p *DNG) DecodeLinear() (image.Image, error) {
+	pix, spp, r, err := p.decodeLinear()
+	if err != nil {
+		return nil, err
+	}
+	return dngImage(pix, spp, r)
+}
+
+// DecodeRGB returns the default crop of the image in camera colors,
+// white balanced with AsShotNeutral. CFA images are demosaiced by
+// bilinear interpolation. No color matrix is applied.
+func (p *DNG) DecodeRGB() (*image.RGBA64, error) {
+	pix, spp, r, err := p.decodeLinear()
+	if err != nil {
+		return nil, err
+	}
+	var cfa *DNGCFA
+	if spp == 1 {
+		if cfa, err = p.CFA(); err != nil {
+			return nil, err
+		}
+	}
+	return renderDNG(pix, spp, r, cfa, p.AsShotNeutral(), p.DefaultCrop())
+}
+
+// renderDNG returns the crop of the linear samples pix of the rectangle r
+// in camera colors, demosaiced with cfa if it is not nil, and white
+// balanced with neutral if it has 3 values.
+func renderDNG(pix []uint16, spp int, r image.Rectangle, cfa *DNGCFA, neutral []float64, crop image.Rectangle) (*image.RGBA64, error) {
+	colors := []int{0, 1, 2}
+	if cfa != nil {
+		var err error
+		if pix, err = demosaic(pix, r, cfa); err != nil {
+			return nil, err
+		}
+		spp, colors = 3, cfa.PlaneColors
+	}
+	if spp != 3 {
+		return nil, fmt.Errorf("tiff: DNG.DecodeRGB, unsupported %d samples per pixel", spp)
+	}
+
+	// Planes are scaled so that the neutral is white, the least scaled
+	// plane keeping its values.
+	scale := []float64{1, 1, 1}
+	if len(neutral) == 3 {
+		max := 0.0
+		for _, v := range neutral {
+			max = math.Max(max, v)
+		}
+		for i, v := range neutral {
+			if v > 0 {
+				scale[i] = max / v
+			}
+		}
+	}
+
+	m := image.NewRGBA64(image.Rect(0, 0, crop.Dx(), crop.Dy()))
+	for y := crop.Min.Y; y < crop.Max.Y; y++ {
+		for x := crop.Min.X; x < crop.Max.X; x++ {
+			var c [3]uint16
+			for s := 0; s < 3; s++ {
+				v := float64(pix[(y*r.Dx()+x)*3+s]) * scale[s]
+				c[colors[s]] = uint16(math.Min(v+0.5, 0xffff))
+			}
+			m.SetRGBA64(x-crop.Min.X, y-crop.Min.Y, color.RGBA64{c[0], c[1], c[2], 0xffff})
+		}
+	}
+	return m, nil
+}
+
+// dngImage returns the samples pix as an image of bounds b.
+func dngImage(pix []uint16, spp int, b image.Rectangle) (image.Image, error) {
+	switch spp {
+	case 1:
+		m := image.NewGray16(b)
+		for i, v := range pix {
+			m.Pix[2*i], m.Pix[2*i+1] = uint8(v>>8), uint8(v)
+		}
+		return m, nil
+	case 3:
+		m := image.NewRGBA64(b)
+		for i := 0; i < len(pix)/3; i++ {
+			j := 8 * i
+			for s := 0; s < 3; s++ {
+				m.Pix[j+2*s], m.Pix[j+2*s+1] = uint8(pix[3*i+s]>>8), uint8(pix[3*i+s])
+			}
+			m.Pix[j+6], m.Pix[j+7] = 0xff, 0xff
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("tiff: DNG, unsupported %d samples per pixel", spp)
+}
+
+// decodeSamples returns the raw samples of the whole raw IFD.
+func (p *DNG) decodeSamples() (pix []uint16, spp int, r image.Rectangle, err error) {
+	ifd := p.Raw
+	width, _ := ifd.TagGetter().GetImageWidth()
+	height, _ := ifd.TagGetter().GetImageLength()
+	bitsPerSample, _ := ifd.TagGetter().GetBitsPerSample()
+	r = image.Rect(0, 0, int(width), int(height))
+	spp = len(bitsPerSample)
+	if spp == 0 || r.Empty() {
+		err = fmt.Errorf("tiff: DNG.Decode, bad raw IFD")
+		return
+	}
+	bps := int(bitsPerSample[0])
+	if bps <= 0 || bps > 16 {
+		err = fmt.Errorf("tiff: DNG.Decode, unsupported %d bits per sample", bps)
+		return
+	}
+	if planar, _ := ifd.TagGetter().GetPlanarConfiguration(); planar != 1 && spp > 1 {
+		err = fmt.Errorf("tiff: DNG.Decode, unsupported planar configuration")
+		return
+	}
+
+	if err = ifd.opt.checkPixels(r); err != nil {
+		return
+	}
+	pix = make([]uint16, r.Dx()*r.Dy()*spp)
+	for row := 0; row < ifd.BlocksDown(); row++ {
+		for col := 0; col < ifd.BlocksAcross(); col++ {
+			b := ifd.BlockBounds(col, row)
+			var samples []uint16
+			if samples, err = p.decodeBlock(col, row, b, spp, bps); err != nil {
+				return
+			}
+			for y := b.Min.Y; y < b.Max.Y && y < r.Max.Y; y++ {
+				for x := b.Min.X; x < b.Max.X && x < r.Max.X; x++ {
+					i := ((y-b.Min.Y)*b.Dx() + x - b.Min.X) * spp
+					if i+spp > len(samples) {
+						err = fmt.Errorf("tiff: DNG.Decode, not enough pixel data")
+						return
+					}
+					copy(pix[(y*r.Dx()+x)*spp:], samples[i:i+spp])
+				}
+			}
+		}
+	}
+	return
+}
+
+// decodeBlock returns the samples of a block, of bounds b. Lossless JPEG
+// blocks may be encoded with any width and number of components whose
+// samples follow each other in the order of the block.
+func (p *DNG) decodeBlock(col, row int, b image.Rectangle, spp, bps int) ([]uint16, error) {
+	ifd := p.Raw
+	data, err := ifd.readBlock(p.r, col, row)
+	if err != nil {
+		return nil, err
+	}
+	if ifd.Compression() == TagValue_CompressionType_JPEG {
+		// The frame is checked against the limits before its samples are
+		// allocated.
+		cfg, err := ljpeg.DecodeConfig(data)
+		if err != nil {
+			return nil, err
+		}
+		if err = ifd.opt.checkPixels(image.Rect(0, 0, cfg.Width, cfg.Height)); err != nil {
+			return nil, err
+		}
+		if err = checkBlock(int64(cfg.Width)*int64(cfg.Height)*int64(cfg.Components)*2, ifd.opt.maxBlockBytes()); err != nil {
+			return nil, err
+		}
+		m, err := ljpeg.Decode(data)
+		if err != nil {
+			return nil, err
+		}
+		return m.Pix, nil
+	}
+
+	if data, err = ifd.Compression().decode(bytes.NewReader(data), b.Dx(), b.Dy(), ifd.opt.maxBlockBytes()); err != nil {
+		return nil, err
+	}
+	if predictor, _ := ifd.TagGetter().GetPredictor(); predictor == TagValue_PredictorType_Horizontal {
+		if data, err = ifd.decodePredictor(data, b); err != nil {
+			return nil, err
+		}
+	}
+	samples := make([]uint16, 0, b.Dx()*b.Dy()*spp)
+	rowSize := (b.Dx()*spp*bps + 7) / 8
+	for y := 0; y < b.Dy() && (y+1)*rowSize <= len(data); y++ {
+		line := data[y*rowSize : (y+1)*rowSize]
+		switch bps {
+		case 8:
+			for _, v := range line {
+				samples = append(samples, uint16(v))
+			}
+		case 16:
+			for i := 0; i+1 < len(line); i += 2 {
+				samples = append(samples, ifd.Header.ByteOrder.Uint16(line[i:]))
+			}
+		default:
+			br := newBitsReader(line)
+			for i := 0; i < b.Dx()*spp; i++ {
+				v, _ := br.ReadBits(uint(bps))
+				samples = append(samples, uint16(v))
+			}
+		}
+	}
+	return samples, nil
+}
+
+// decodeLinear returns the linear samples of the active area.
+func (p *DNG) decodeLinear() (pix []uint16, spp int, r image.Rectangle, err error) {
+	raw, spp, full, err := p.decodeSamples()
+	if err != nil {
+		return
+	}
+	active := p.ActiveArea()
+	l := p.levels(spp, active.Size())
+	return l.linearize(raw, spp, full, active), spp, image.Rect(0, 0, active.Dx(), active.Dy()), nil
+}
+
+// dngLevels maps raw values to linear values: through the linearization
+// table, from the black level to the white level of each sample.
+type dngLevels struct {
+	table                []int64
+	black                []float64 // Of blackRows x blackCols pixels.
+	blackRows, blackCols int
+	deltaH, deltaV       []float64 // By column and row of the active area.
+	white                []float64
+}
+
+// levels returns the levels of the raw IFD, for an active area of the
+// given size.
+func (p *DNG) levels(spp int, size image.Point) *dngLevels {
+	g := p.Raw.TagGetter()
+	l := &dngLevels{blackRows: 1, blackCols: 1}
+	l.table, _ = g.GetLinearizationTable()
+	bitsPerSample, _ := g.GetBitsPerSample()
+	levels, _ := g.GetWhiteLevel()
+	l.white = make([]float64, spp)
+	for s := range l.white {
+		l.white[s] = float64(int64(1)<<uint(bitsPerSample[0]) - 1)
+		if len(l.table) > 0 {
+			l.white[s] = float64(l.table[len(l.table)-1])
+		}
+		if len(levels) == spp {
+			l.white[s] = float64(levels[s])
+		} else if len(levels) > 0 {
+			l.white[s] = float64(levels[0])
+		}
+	}
+	if dim, ok := g.GetBlackLevelRepeatDim(); ok && len(dim) == 2 && dim[0] > 0 && dim[1] > 0 {
+		l.blackRows, l.blackCols = int(dim[0]), int(dim[1])
+	}
+	l.black, _ = g.GetBlackLevel()
+	if len(l.black) == 1 {
+		l.black = repeatFloats(l.black[0], l.blackRows*l.blackCols*spp)
+	}
+	if len(l.black) != l.blackRows*l.blackCols*spp {
+		l.black = make([]float64, l.blackRows*l.blackCols*spp)
+	}
+	l.deltaH, _ = entryFloats(p.Raw.EntryMap[TagType_BlackLevelDeltaH])
+	l.deltaV, _ = entryFloats(p.Raw.EntryMap[TagType_BlackLevelDeltaV])
+	if len(l.deltaH) != size.X {
+		l.deltaH = make([]float64, size.X)
+	}
+	if len(l.deltaV) != size.Y {
+		l.deltaV = make([]float64, size.Y)
+	}
+	return l
+}
+
+// linearize returns the linear samples of the active area of the raw
+// samples of the rectangle full.
+func (l *dngLevels) linearize(raw []uint16, spp int, full, active image.Rectangle) []uint16 {
+	w, h := active.Dx(), active.Dy()
+	pix := make([]uint16, w*h*spp)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			src := ((active.Min.Y+y)*full.Dx() + active.Min.X + x) * spp
+			for s := 0; s < spp; s++ {
+				v := raw[src+s]
+				if len(l.table) > 0 {
+					v = uint16(l.table[minInt(int(v), len(l.table)-1)])
+				}
+				b := l.black[((y%l.blackRows)*l.blackCols+x%l.blackCols)*spp+s] + l.deltaH[x] + l.deltaV[y]
+				f := 0.0
+				if l.white[s] > b {
+					f = (float64(v) - b) / (l.white[s] - b)
+				}
+				pix[(y*w+x)*spp+s] = uint16(math.Max(0, math.Min(1, f))*0xffff + 0.5)
+			}
+		}
+	}
+	return pix
+}
+
+func repeatFloats(v float64, n int) []float64 {
+	s := make([]float64, n)
+	for i := range s {
+		s[i] = v
+	}
+	return s
+}
+
+// demosaic interpolates the missing colors of each pixel of a CFA mosaic
+// bilinearly, as the mean of the pixels of that color among its eight
+// neighbors. It returns 3 samples per pixel, in the order of the planes.
+func demosaic(pix []uint16, r image.Rectangle, cfa *DNGCFA) ([]uint16, error) {
+	if len(cfa.PlaneColors) != 3 {
+		return nil, fmt.Errorf("tiff: DNG.DecodeRGB, unsupported %d color planes", len(cfa.PlaneColors))
+	}
+	// Only red, green and blue planes are rendered.
+	for _, c := range cfa.PlaneColors {
+		if c < 0 || c > 2 {
+			return nil, fmt.Errorf("tiff: DNG.DecodeRGB, unsupported plane colors %v", cfa.PlaneColors)
+		}
+	}
+	w, h := r.Dx(), r.Dy()
+	out := make([]uint16, w*h*3)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var sum [3]int
+			var n [3]int
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					xx, yy := x+dx, y+dy
+					if xx < 0 || yy < 0 || xx >= w || yy >= h {
+						continue
+					}
+					plane := cfa.Plane(xx, yy)
+					sum[plane] += int(pix[yy*w+xx])
+					n[plane]++
+				}
+			}
+			own := cfa.Plane(x, y)
+			for plane := 0; plane < 3; plane++ {
+				v := pix[y*w+x]
+				if plane != own {
+					v = 0
+					if n[plane] > 0 {
+						v = uint16((sum[plane] + n[plane]/2) / n[plane])
+					}
+				}
+				out[(y*w+x)*3+plane] = v
+			}
+		}
+	}
+	return out, nil
+}
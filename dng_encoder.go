@@ -0,0 +1,483 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+
+	"github.com/dhushon/tiff/internal/ljpeg"
+)
+
+// DNGOptions are the options of EncodeDNG. UniqueCameraModel and the
+// first color matrix are required; the other tags are only written if
+// they are set.
+type DNGOptions struct {
+	// Compression is the compression of the raw data: None, Deflate, LZW,
+	// or JPEG for lossless JPEG. The zero value writes uncompressed data.
+	Compression TagValue_CompressionType
+
+	// TileWidth and TileLength are the size of the raw data tiles,
+	// multiples of 16. If both are zero, 256x256 tiles are written.
+	TileWidth  int
+	TileLength int
+
+	// ByteOrder is the byte order of the file. The zero value writes
+	// little-endian files.
+	ByteOrder binary.ByteOrder
+
+	// Workers is the number of goroutines that compress the tiles, as
+	// Options.Workers.
+	Workers int
+
+	// CFA is the color filter array of a CFA raw image. It is required for
+	// an *image.Gray16 and must be nil for a LinearRaw *image.RGBA64.
+	CFA *DNGCFA
+
+	// BlackLevel is the black level of every sample, of each sample, or
+	// for a CFA image of each site of the CFA pattern, row by row.
+	// WhiteLevel is the white level of every sample or of each sample,
+	// 0xffff by default.
+	BlackLevel []float64
+	WhiteLevel []int64
+
+	// ActiveArea is the area of the raw image that holds image data, and
+	// DefaultCrop the area of the active area that is rendered, relative to
+	// its top-left corner. Empty rectangles select the whole image.
+	ActiveArea  image.Rectangle
+	DefaultCrop image.Rectangle
+
+	UniqueCameraModel string
+
+	// The color tags, for the calibration illuminants 1 and 2, have the
+	// layout of the methods of the same names of DNG. Their rows have 3
+	// values or one per color plane, row by row.
+	ColorMatrix           [2][]float64
+	CameraCalibration     [2][]float64
+	ForwardMatrix         [2][]float64
+	CalibrationIlluminant [2]int
+	AsShotNeutral         []float64
+	AnalogBalance         []float64
+	BaselineExposure      float64
+
+	// Preview is the image written to IFD 0. If it is nil, a preview of at
+	// most 256x256 pixels is rendered from the raw data, as by
+	// DNG.DecodeRGB with a gamma of 2.2.
+	Preview image.Image
+}
+
+// EncodeDNG writes the raw image m to w as a DNG file, version 1.4.
+//
+// m holds the values of the sensor: an *image.Gray16 for a CFA image with
+// opt.CFA, or an opaque *image.RGBA64 for a LinearRaw image. The file has
+// the preview and the camera and color tags in IFD 0, and the tiled raw
+// data in its SubIFD:
+//
+//  1. Header (8 bytes, or 16 bytes for BigTIFF).
+//  2. IFD 0, then the raw IFD, each with its pointer area.
+//  3. Preview data, then raw data.
+func EncodeDNG(w io.Writer, m image.Image, opt *DNGOptions) error {
+	if opt == nil {
+		return fmt.Errorf("tiff: EncodeDNG, missing options")
+	}
+	pix, spp := dngSamples(m)
+	planes := spp
+	switch {
+	case spp == 1 && opt.CFA != nil:
+		planes = len(opt.CFA.PlaneColors)
+	case spp == 1:
+		return fmt.Errorf("tiff: EncodeDNG, missing CFA of a CFA image")
+	case spp == 3 && opt.CFA != nil:
+		return fmt.Errorf("tiff: EncodeDNG, CFA of a LinearRaw image")
+	case spp == 3:
+	default:
+		return fmt.Errorf("tiff: EncodeDNG, unsupported image type %T", m)
+	}
+	if err := opt.validate(spp, planes); err != nil {
+		return err
+	}
+
+	o := Options{Compression: opt.Compression, TileWidth: opt.TileWidth, TileLength: opt.TileLength, ByteOrder: opt.ByteOrder, Workers: opt.Workers}
+	if o.TileWidth == 0 && o.TileLength == 0 {
+		o.TileWidth, o.TileLength = 256, 256
+	}
+	if o.Compression == TagValue_CompressionType_JPEG {
+		o.Compression = TagValue_CompressionType_None
+	}
+	raw, err := newImageEncoder(m, &o)
+	if err != nil {
+		return err
+	}
+	if raw.samplesPerPixel != spp {
+		return fmt.Errorf("tiff: EncodeDNG, LinearRaw image is not opaque")
+	}
+	raw.compression = opt.Compression
+	if raw.compression == TagValue_CompressionType_Nil {
+		raw.compression = TagValue_CompressionType_None
+	}
+	raw.photometric = TagValue_PhotometricType_LinearRaw
+	if opt.CFA != nil {
+		raw.photometric = TagValue_PhotometricType_CFA
+	}
+	raw.dngTags = opt.rawTags(spp)
+
+	preview := opt.Preview
+	if preview == nil {
+		if preview, err = opt.renderPreview(pix, spp, m.Bounds().Size()); err != nil {
+			return err
+		}
+	}
+	main, err := newImageEncoder(preview, &Options{ByteOrder: opt.ByteOrder, Compression: TagValue_CompressionType_Deflate})
+	if err != nil {
+		return err
+	}
+	main.subfileType = TagValue_NewSubfileType_Reduced
+
+	levels := []*imageEncoder{main, raw}
+	blocks := make([][][]byte, len(levels))
+	counts := make([][]uint64, len(levels))
+	for k, e := range levels {
+		encode := e.encodeBlock
+		if e.compression == TagValue_CompressionType_JPEG {
+			encode = e.encodeLosslessBlock
+		}
+		if blocks[k], counts[k], err = e.encodeBlocks(encode); err != nil {
+			return err
+		}
+	}
+
+	offsets, ifdOffsets, end := dngLayout(levels, opt, counts)
+	if end > math.MaxUint32 {
+		main.bigTiff, raw.bigTiff = true, true
+		offsets, ifdOffsets, _ = dngLayout(levels, opt, counts)
+	}
+	main.dngTags = opt.mainTags(main.bigTiff, ifdOffsets[1])
+
+	h := main.header(ifdOffsets[0])
+	buf := bytes.NewBuffer(h.Bytes())
+	for k, e := range levels {
+		buf.Write(make([]byte, ifdOffsets[k]-int64(buf.Len())))
+		buf.Write(ifdBytes(h, ifdOffsets[k], 0, e.ifd(offsets[k], counts[k])))
+	}
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	for k := range levels {
+		for _, b := range blocks[k] {
+			if _, err := w.Write(b); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// dngLayout returns the block offsets and the IFD offsets of IFD 0 and the
+// raw IFD of a DNG file whose blocks have the given sizes, and the size of
+// the file.
+func dngLayout(levels []*imageEncoder, opt *DNGOptions, counts [][]uint64) (offsets [][]uint64, ifdOffsets []int64, end int64) {
+	// The IFD size does not depend on the values of the offsets.
+	levels[0].dngTags = opt.mainTags(levels[0].bigTiff, 0)
+	h := levels[0].header(0)
+	off := int64(h.HeadSize())
+	offsets = make([][]uint64, len(levels))
+	ifdOffsets = make([]int64, len(levels))
+	for k, e := range levels {
+		offsets[k] = make([]uint64, len(counts[k]))
+		off += off % 2
+		ifdOffsets[k] = off
+		off += int64(len(ifdBytes(h, off, 0, e.ifd(offsets[k], counts[k]))))
+	}
+	for k := range levels {
+		for i, n := range counts[k] {
+			offsets[k][i] = uint64(off)
+			off += int64(n)
+		}
+	}
+	return offsets, ifdOffsets, off
+}
+
+// encodeLosslessBlock returns block i of a 16-bit image encoded as a
+// lossless JPEG image, with a component per sample.
+func (e *imageEncoder) encodeLosslessBlock(i int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := e.writeBlock(&buf, i); err != nil {
+		return nil, err
+	}
+	r := e.blockBounds(i)
+	data := buf.Bytes()
+	m := &ljpeg.Image{
+		Width:      r.Dx(),
+		Height:     r.Dy(),
+		Components: e.samplesPerPixel,
+		Precision:  16,
+		Pix:        make([]uint16, len(data)/2),
+	}
+	for j := range m.Pix {
+		m.Pix[j] = e.order.Uint16(data[2*j:])
+	}
+	return ljpeg.Encode(m, 1)
+}
+
+// dngSamples returns the samples of a Gray16 or RGBA64 image without
+// alpha, and the number of samples per pixel, or 0 for other images.
+func dngSamples(m image.Image) (pix []uint16, spp int) {
+	switch m := m.(type) {
+	case *image.Gray16:
+		b := m.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				pix = append(pix, m.Gray16At(x, y).Y)
+			}
+		}
+		return pix, 1
+	case *image.RGBA64:
+		b := m.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				c := m.RGBA64At(x, y)
+				pix = append(pix, c.R, c.G, c.B)
+			}
+		}
+		return pix, 3
+	}
+	return nil, 0
+}
+
+func (p *DNGOptions) validate(spp, planes int) error {
+	if cfa := p.CFA; cfa != nil {
+		if cfa.Width <= 0 || cfa.Height <= 0 || len(cfa.Pattern) != cfa.Width*cfa.Height || planes == 0 {
+			return fmt.Errorf("tiff: EncodeDNG, bad CFA %+v", *cfa)
+		}
+		for _, plane := range cfa.Pattern {
+			if plane < 0 || plane >= planes {
+				return fmt.Errorf("tiff: EncodeDNG, bad CFA %+v", *cfa)
+			}
+		}
+		// The preview is only rendered from red, green and blue planes.
+		max := 6
+		if p.Preview == nil {
+			max = 2
+		}
+		for _, c := range cfa.PlaneColors {
+			if c < 0 || c > max {
+				return fmt.Errorf("tiff: EncodeDNG, bad CFA plane colors %v", cfa.PlaneColors)
+			}
+		}
+	}
+	if p.UniqueCameraModel == "" {
+		return fmt.Errorf("tiff: EncodeDNG, missing UniqueCameraModel")
+	}
+	if p.ColorMatrix[0] == nil {
+		return fmt.Errorf("tiff: EncodeDNG, missing ColorMatrix1")
+	}
+	for _, m := range []struct {
+		name string
+		v    []float64
+		n    int
+	}{
+		{"ColorMatrix1", p.ColorMatrix[0], 3 * planes},
+		{"ColorMatrix2", p.ColorMatrix[1], 3 * planes},
+		{"CameraCalibration1", p.CameraCalibration[0], planes * planes},
+		{"CameraCalibration2", p.CameraCalibration[1], planes * planes},
+		{"ForwardMatrix1", p.ForwardMatrix[0], 3 * planes},
+		{"ForwardMatrix2", p.ForwardMatrix[1], 3 * planes},
+		{"AsShotNeutral", p.AsShotNeutral, planes},
+		{"AnalogBalance", p.AnalogBalance, planes},
+	} {
+		if m.v != nil && len(m.v) != m.n {
+			return fmt.Errorf("tiff: EncodeDNG, %s has %d values, want %d", m.name, len(m.v), m.n)
+		}
+	}
+	if n := len(p.BlackLevel); n > 1 && n != spp && (p.CFA == nil || n != p.CFA.Width*p.CFA.Height) {
+		return fmt.Errorf("tiff: EncodeDNG, bad number of black levels %d", n)
+	}
+	if n := len(p.WhiteLevel); n > 1 && n != spp {
+		return fmt.Errorf("tiff: EncodeDNG, bad number of white levels %d", n)
+	}
+	return nil
+}
+
+// activeArea returns the active area of a raw image of the given size.
+func (p *DNGOptions) activeArea(size image.Point) image.Rectangle {
+	if p.ActiveArea.Empty() {
+		return image.Rectangle{Max: size}
+	}
+	return p.ActiveArea.Intersect(image.Rectangle{Max: size})
+}
+
+// defaultCrop returns the default crop of an active area of the given size.
+func (p *DNGOptions) defaultCrop(size image.Point) image.Rectangle {
+	if p.DefaultCrop.Empty() {
+		return image.Rectangle{Max: size}
+	}
+	return p.DefaultCrop.Intersect(image.Rectangle{Max: size})
+}
+
+// mainTags returns the DNG tags of IFD 0, whose SubIFD is at rawOffset.
+func (p *DNGOptions) mainTags(bigTiff bool, rawOffset int64) []ifdEntry {
+	subIFDType := DataType_Long
+	if bigTiff {
+		subIFDType = DataType_IFD8
+	}
+	ifd := []ifdEntry{
+		{TagType_SubIFD, subIFDType, []uint64{uint64(rawOffset)}},
+		{TagType_DNGVersion, DataType_Byte, []uint64{1, 4, 0, 0}},
+		{TagType_DNGBackwardVersion, DataType_Byte, []uint64{1, 1, 0, 0}},
+		{TagType_UniqueCameraModel, DataType_ASCII, asciiData(p.UniqueCameraModel)},
+	}
+	matrices := []struct {
+		tags [2]TagType
+		v    [2][]float64
+	}{
+		{[2]TagType{TagType_ColorMatrix1, TagType_ColorMatrix2}, p.ColorMatrix},
+		{[2]TagType{TagType_CameraCalibration1, TagType_CameraCalibration2}, p.CameraCalibration},
+		{[2]TagType{TagType_ForwardMatrix1, TagType_ForwardMatrix2}, p.ForwardMatrix},
+	}
+	for _, m := range matrices {
+		for i := range m.v {
+			if m.v[i] != nil {
+				ifd = append(ifd, ifdEntry{m.tags[i], DataType_SRational, srationalsData(m.v[i])})
+			}
+		}
+	}
+	for i, tag := range []TagType{TagType_CalibrationIlluminant1, TagType_CalibrationIlluminant2} {
+		if p.CalibrationIlluminant[i] != 0 {
+			ifd = append(ifd, ifdEntry{tag, DataType_Short, []uint64{uint64(p.CalibrationIlluminant[i])}})
+		}
+	}
+	if p.AsShotNeutral != nil {
+		ifd = append(ifd, ifdEntry{TagType_AsShotNeutral, DataType_Rational, rationalsData(p.AsShotNeutral)})
+	}
+	if p.AnalogBalance != nil {
+		ifd = append(ifd, ifdEntry{TagType_AnalogBalance, DataType_Rational, rationalsData(p.AnalogBalance)})
+	}
+	if p.BaselineExposure != 0 {
+		ifd = append(ifd, ifdEntry{TagType_BaselineExposure, DataType_SRational, srationalsData([]float64{p.BaselineExposure})})
+	}
+	return ifd
+}
+
+// rawTags returns the DNG tags of the raw IFD.
+func (p *DNGOptions) rawTags(spp int) []ifdEntry {
+	var ifd []ifdEntry
+	if cfa := p.CFA; cfa != nil {
+		pattern := make([]uint64, len(cfa.Pattern))
+		for i, plane := range cfa.Pattern {
+			pattern[i] = uint64(cfa.PlaneColors[plane])
+		}
+		colors := make([]uint64, len(cfa.PlaneColors))
+		for i, c := range cfa.PlaneColors {
+			colors[i] = uint64(c)
+		}
+		ifd = append(ifd,
+			ifdEntry{TagType_CFARepeatPatternDim, DataType_Short, []uint64{uint64(cfa.Height), uint64(cfa.Width)}},
+			ifdEntry{TagType_CFAPattern, DataType_Byte, pattern},
+			ifdEntry{TagType_CFAPlaneColor, DataType_Byte, colors},
+			ifdEntry{TagType_CFALayout, DataType_Short, []uint64{1}},
+		)
+	}
+	if len(p.BlackLevel) > 0 {
+		if p.CFA != nil && len(p.BlackLevel) > 1 {
+			ifd = append(ifd, ifdEntry{TagType_BlackLevelRepeatDim, DataType_Short, []uint64{uint64(p.CFA.Height), uint64(p.CFA.Width)}})
+		}
+		ifd = append(ifd, ifdEntry{TagType_BlackLevel, DataType_Rational, rationalsData(p.BlackLevel)})
+	}
+	if len(p.WhiteLevel) > 0 {
+		ifd = append(ifd, ifdEntry{TagType_WhiteLevel, DataType_Long, intsData(p.WhiteLevel)})
+	}
+	if !p.ActiveArea.Empty() {
+		r := p.ActiveArea
+		ifd = append(ifd, ifdEntry{TagType_ActiveArea, DataType_Long, intsData([]int64{int64(r.Min.Y), int64(r.Min.X), int64(r.Max.Y), int64(r.Max.X)})})
+	}
+	if !p.DefaultCrop.Empty() {
+		r := p.DefaultCrop
+		ifd = append(ifd,
+			ifdEntry{TagType_DefaultCropOrigin, DataType_Long, intsData([]int64{int64(r.Min.X), int64(r.Min.Y)})},
+			ifdEntry{TagType_DefaultCropSize, DataType_Long, intsData([]int64{int64(r.Dx()), int64(r.Dy())})},
+		)
+	}
+	return ifd
+}
+
+// levels returns the levels of a raw image with the options, for an
+// active area of the given size.
+func (p *DNGOptions) levels(spp int, size image.Point) *dngLevels {
+	l := &dngLevels{blackRows: 1, blackCols: 1}
+	l.white = repeatFloats(0xffff, spp)
+	for s := range l.white {
+		if len(p.WhiteLevel) == spp {
+			l.white[s] = float64(p.WhiteLevel[s])
+		} else if len(p.WhiteLevel) > 0 {
+			l.white[s] = float64(p.WhiteLevel[0])
+		}
+	}
+	switch n := len(p.BlackLevel); {
+	case n == 0:
+		l.black = make([]float64, spp)
+	case n == 1:
+		l.black = repeatFloats(p.BlackLevel[0], spp)
+	case n == spp:
+		l.black = p.BlackLevel
+	default:
+		l.blackRows, l.blackCols = p.CFA.Height, p.CFA.Width
+		l.black = p.BlackLevel
+	}
+	l.deltaH, l.deltaV = make([]float64, size.X), make([]float64, size.Y)
+	return l
+}
+
+// renderPreview returns the preview of the raw samples pix of an image of
+// the given size: the default crop in camera colors, reduced by halves to
+// at most 256x256 pixels, with a gamma of 2.2.
+func (p *DNGOptions) renderPreview(pix []uint16, spp int, size image.Point) (image.Image, error) {
+	active := p.activeArea(size)
+	linear := p.levels(spp, active.Size()).linearize(pix, spp, image.Rectangle{Max: size}, active)
+	rgb, err := renderDNG(linear, spp, image.Rectangle{Max: active.Size()}, p.CFA, p.AsShotNeutral, p.defaultCrop(active.Size()))
+	if err != nil {
+		return nil, err
+	}
+	var m image.Image = rgb
+	for b := m.Bounds(); b.Dx() > 256 || b.Dy() > 256; b = m.Bounds() {
+		m = reduceNearest(m)
+	}
+
+	gamma := func(v uint32) uint8 { return uint8(math.Pow(float64(v)/0xffff, 1/2.2)*0xff + 0.5) }
+	b := m.Bounds()
+	preview := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, _ := m.At(x, y).RGBA()
+			preview.SetRGBA(x-b.Min.X, y-b.Min.Y, color.RGBA{gamma(r), gamma(g), gamma(bl), 0xff})
+		}
+	}
+	return preview, nil
+}
+
+// rationalsData returns the values of v as unsigned rationals.
+func rationalsData(v []float64) []uint64 {
+	data := make([]uint64, 0, 2*len(v))
+	for _, f := range v {
+		r := floatToRational(f)
+		data = append(data, uint64(r[0]), uint64(r[1]))
+	}
+	return data
+}
+
+// srationalsData returns the values of v as signed rationals with a
+// denominator of 10000, as written by Adobe's DNG converter.
+func srationalsData(v []float64) []uint64 {
+	data := make([]uint64, 0, 2*len(v))
+	for _, f := range v {
+		n := math.Max(math.MinInt32, math.Min(math.MaxInt32, math.Round(f*10000)))
+		data = append(data, uint64(uint32(int32(n))), 10000)
+	}
+	return data
+}
@@ -0,0 +1,448 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/dhushon/tiff/internal/ljpeg"
+)
+
+// dngTestRaw returns the raw values of a 20x10 RGGB mosaic whose active
+// area begins at column 2: a flat field of red 400, green 600 and blue
+// 300, over a black level of 64.
+func dngTestRaw() []uint16 {
+	pix := make([]uint16, 20*10)
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 20; x++ {
+			v := uint16(600)
+			switch {
+			case y%2 == 0 && x%2 == 0:
+				v = 400
+			case y%2 == 1 && x%2 == 1:
+				v = 300
+			}
+			pix[y*20+x] = v
+		}
+	}
+	return pix
+}
+
+// dngTestFile returns a DNG file with a preview in IFD 0 and the mosaic of
+// dngTestRaw in a SubIFD, in 16x16 tiles that are uncompressed or, if
+// lossless is set, lossless JPEG encoded with two components per pixel.
+func dngTestFile(t *testing.T, lossless bool) []byte {
+	order := binary.LittleEndian
+	raw := dngTestRaw()
+	h := NewHeader(false, 0)
+	h.ByteOrder = order
+
+	var blocks [][]byte
+	for col := 0; col < 2; col++ {
+		tile := make([]uint16, 16*16)
+		for y := 0; y < 10; y++ {
+			for x := 0; x < 16 && col*16+x < 20; x++ {
+				tile[y*16+x] = raw[y*20+col*16+x]
+			}
+		}
+		var data []byte
+		if lossless {
+			var err error
+			m := &ljpeg.Image{Width: 8, Height: 16, Components: 2, Precision: 16, Pix: tile}
+			if data, err = ljpeg.Encode(m, 1); err != nil {
+				t.Fatal(err)
+			}
+		} else {
+			data = make([]byte, 2*len(tile))
+			for i, v := range tile {
+				order.PutUint16(data[2*i:], v)
+			}
+		}
+		blocks = append(blocks, data)
+	}
+
+	var out []byte
+	out = append(out, make([]byte, h.HeadSize())...)
+	var offsets, counts []uint64
+	for _, b := range blocks {
+		offsets, counts = append(offsets, uint64(len(out))), append(counts, uint64(len(b)))
+		out = append(out, b...)
+	}
+	previewOffset := uint64(len(out))
+	out = append(out, 0x80, 0)
+
+	compression := uint64(TagValue_CompressionType_None)
+	if lossless {
+		compression = uint64(TagValue_CompressionType_JPEG)
+	}
+	rawOffset := int64(len(out))
+	out = append(out, ifdBytes(h, rawOffset, 0, []ifdEntry{
+		{TagType_NewSubfileType, DataType_Long, []uint64{0}},
+		{TagType_ImageWidth, DataType_Short, []uint64{20}},
+		{TagType_ImageLength, DataType_Short, []uint64{10}},
+		{TagType_BitsPerSample, DataType_Short, []uint64{16}},
+		{TagType_Compression, DataType_Short, []uint64{compression}},
+		{TagType_PhotometricInterpretation, DataType_Short, []uint64{uint64(TagValue_PhotometricType_CFA)}},
+		{TagType_SamplesPerPixel, DataType_Short, []uint64{1}},
+		{TagType_PlanarConfiguration, DataType_Short, []uint64{1}},
+		{TagType_TileWidth, DataType_Short, []uint64{16}},
+		{TagType_TileLength, DataType_Short, []uint64{16}},
+		{TagType_TileOffsets, DataType_Long, offsets},
+		{TagType_TileByteCounts, DataType_Long, counts},
+		{TagType_CFARepeatPatternDim, DataType_Short, []uint64{2, 2}},
+		{TagType_CFAPattern, DataType_Byte, []uint64{0, 1, 1, 2}},
+		{TagType_BlackLevel, DataType_Short, []uint64{64}},
+		{TagType_WhiteLevel, DataType_Short, []uint64{1023}},
+		{TagType_DefaultCropOrigin, DataType_Rational, []uint64{1, 1, 1, 1}},
+		{TagType_DefaultCropSize, DataType_Rational, []uint64{16, 1, 8, 1}},
+		{TagType_ActiveArea, DataType_Short, []uint64{0, 2, 10, 20}},
+	})...)
+	for len(out)%2 != 0 {
+		out = append(out, 0)
+	}
+
+	mainOffset := int64(len(out))
+	minus := func(v int64) uint64 { return uint64(uint32(v)) }
+	out = append(out, ifdBytes(h, mainOffset, 0, []ifdEntry{
+		{TagType_NewSubfileType, DataType_Long, []uint64{1}},
+		{TagType_ImageWidth, DataType_Short, []uint64{1}},
+		{TagType_ImageLength, DataType_Short, []uint64{1}},
+		{TagType_BitsPerSample, DataType_Short, []uint64{8}},
+		{TagType_Compression, DataType_Short, []uint64{1}},
+		{TagType_PhotometricInterpretation, DataType_Short, []uint64{1}},
+		{TagType_StripOffsets, DataType_Long, []uint64{previewOffset}},
+		{TagType_SamplesPerPixel, DataType_Short, []uint64{1}},
+		{TagType_RowsPerStrip, DataType_Short, []uint64{1}},
+		{TagType_StripByteCounts, DataType_Long, []uint64{1}},
+		{TagType_SubIFD, DataType_Long, []uint64{uint64(rawOffset)}},
+		{TagType_DNGVersion, DataType_Byte, []uint64{1, 4, 0, 0}},
+		{TagType_UniqueCameraModel, DataType_ASCII, asciiData("Test Camera")},
+		{TagType_ColorMatrix1, DataType_SRational, []uint64{
+			2, 1, minus(-1), 2, 0, 1,
+			minus(-1), 4, 1, 1, 1, 4,
+			0, 1, minus(-1), 4, 3, 2,
+		}},
+		{TagType_CalibrationIlluminant1, DataType_Short, []uint64{21}},
+		{TagType_AsShotNeutral, DataType_Rational, []uint64{1, 2, 1, 1, 4, 5}},
+		{TagType_BaselineExposure, DataType_SRational, []uint64{minus(-1), 2}},
+	})...)
+	h.FirstIFD = mainOffset
+	copy(out, h.Bytes())
+	return out
+}
+
+func TestDNG(t *testing.T) {
+	for _, lossless := range []bool{false, true} {
+		p, err := OpenReader(bytes.NewReader(dngTestFile(t, lossless)))
+		if err != nil {
+			t.Fatalf("lossless %v: %v", lossless, err)
+		}
+		dng, err := p.DNG()
+		if err != nil {
+			t.Fatalf("lossless %v: %v", lossless, err)
+		}
+		if dng.Raw == dng.Main || dng.Version() != "1.4.0.0" {
+			t.Errorf("lossless %v: raw IFD %p of %p, version %q", lossless, dng.Raw, dng.Main, dng.Version())
+		}
+
+		m, err := dng.DecodeRaw()
+		if err != nil {
+			t.Fatalf("lossless %v: %v", lossless, err)
+		}
+		want := image.NewGray16(image.Rect(0, 0, 20, 10))
+		for i, v := range dngTestRaw() {
+			want.Pix[2*i], want.Pix[2*i+1] = uint8(v>>8), uint8(v)
+		}
+		if !reflect.DeepEqual(m, want) {
+			t.Errorf("lossless %v: DecodeRaw differs", lossless)
+		}
+
+		linear, err := dng.DecodeLinear()
+		if err != nil {
+			t.Fatalf("lossless %v: %v", lossless, err)
+		}
+		// The active area begins on a red column.
+		if b := linear.Bounds(); b != image.Rect(0, 0, 18, 10) {
+			t.Errorf("lossless %v: DecodeLinear bounds %v", lossless, b)
+		}
+		if v := linear.(*image.Gray16).Gray16At(0, 0).Y; v != 22961 {
+			t.Errorf("lossless %v: DecodeLinear red %d", lossless, v)
+		}
+
+		rgb, err := dng.DecodeRGB()
+		if err != nil {
+			t.Fatalf("lossless %v: %v", lossless, err)
+		}
+		if b := rgb.Bounds(); b != image.Rect(0, 0, 16, 8) {
+			t.Errorf("lossless %v: DecodeRGB bounds %v", lossless, b)
+		}
+		// Red is scaled by 2 and blue by 5/4 to balance the neutral.
+		for _, pt := range []image.Point{{0, 0}, {5, 3}, {15, 7}} {
+			c := rgb.RGBA64At(pt.X, pt.Y)
+			if c.R != 45922 || c.G != 36629 || c.B != 20159 {
+				t.Errorf("lossless %v: DecodeRGB at %v = %v", lossless, pt, c)
+			}
+		}
+	}
+}
+
+func TestDNG_tags(t *testing.T) {
+	p, err := OpenReader(bytes.NewReader(dngTestFile(t, false)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dng, err := p.DNG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfa, err := dng.CFA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfa.Width != 2 || cfa.Height != 2 || cfa.Plane(0, 0) != 0 || cfa.Plane(3, 1) != 2 || cfa.Plane(1, 2) != 1 {
+		t.Errorf("CFA = %+v", cfa)
+	}
+	if r := dng.ActiveArea(); r != image.Rect(2, 0, 20, 10) {
+		t.Errorf("ActiveArea = %v", r)
+	}
+	if r := dng.DefaultCrop(); r != image.Rect(1, 1, 17, 9) {
+		t.Errorf("DefaultCrop = %v", r)
+	}
+	if model, _ := dng.Main.TagGetter().GetUniqueCameraModel(); model != "Test Camera" {
+		t.Errorf("UniqueCameraModel = %q", model)
+	}
+	wantMatrix := []float64{2, -0.5, 0, -0.25, 1, 0.25, 0, -0.25, 1.5}
+	if m := dng.ColorMatrix(1); !reflect.DeepEqual(m, wantMatrix) {
+		t.Errorf("ColorMatrix(1) = %v", m)
+	}
+	if m := dng.ColorMatrix(2); m != nil {
+		t.Errorf("ColorMatrix(2) = %v", m)
+	}
+	if v, ok := dng.CalibrationIlluminant(1); !ok || v != 21 {
+		t.Errorf("CalibrationIlluminant(1) = %v, %v", v, ok)
+	}
+	if v := dng.AsShotNeutral(); !reflect.DeepEqual(v, []float64{0.5, 1, 0.8}) {
+		t.Errorf("AsShotNeutral = %v", v)
+	}
+	if v := dng.BaselineExposure(); v != -0.5 {
+		t.Errorf("BaselineExposure = %v", v)
+	}
+
+	// A plain TIFF file is not a DNG file.
+	var buf bytes.Buffer
+	if err := Encode(&buf, image.NewGray(image.Rect(0, 0, 4, 4)), nil); err != nil {
+		t.Fatal(err)
+	}
+	p2, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p2.DNG(); err == nil {
+		t.Errorf("DNG of a TIFF file: no error")
+	}
+}
+
+func TestEncodeDNG(t *testing.T) {
+	m := image.NewGray16(image.Rect(0, 0, 40, 30))
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < len(m.Pix); i += 2 {
+		v := 64 + rnd.Intn(4000)
+		m.Pix[i], m.Pix[i+1] = uint8(v>>8), uint8(v)
+	}
+	cfa := &DNGCFA{Width: 2, Height: 2, Pattern: []int{0, 1, 1, 2}, PlaneColors: []int{0, 1, 2}}
+	matrix := []float64{0.6722, -0.0635, -0.0963, -0.4287, 1.246, 0.2046, -0.0984, 0.1566, 0.6318}
+	for _, tt := range []struct {
+		compression TagValue_CompressionType
+		order       binary.ByteOrder
+	}{
+		{TagValue_CompressionType_None, binary.LittleEndian},
+		{TagValue_CompressionType_Deflate, binary.BigEndian},
+		{TagValue_CompressionType_JPEG, binary.LittleEndian},
+	} {
+		opt := &DNGOptions{
+			Compression:           tt.compression,
+			TileWidth:             32,
+			TileLength:            16,
+			ByteOrder:             tt.order,
+			CFA:                   cfa,
+			BlackLevel:            []float64{64, 65, 66, 67},
+			WhiteLevel:            []int64{4095},
+			ActiveArea:            image.Rect(2, 0, 40, 30),
+			DefaultCrop:           image.Rect(1, 1, 33, 25),
+			UniqueCameraModel:     "Lab Instrument",
+			ColorMatrix:           [2][]float64{matrix},
+			CalibrationIlluminant: [2]int{21},
+			AsShotNeutral:         []float64{0.5, 1, 0.75},
+			BaselineExposure:      0.25,
+		}
+		var buf bytes.Buffer
+		if err := EncodeDNG(&buf, m, opt); err != nil {
+			t.Fatalf("%v: %v", tt.compression, err)
+		}
+		p, err := OpenReader(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("%v: %v", tt.compression, err)
+		}
+		dng, err := p.DNG()
+		if err != nil {
+			t.Fatalf("%v: %v", tt.compression, err)
+		}
+		if v := dng.Version(); v != "1.4.0.0" {
+			t.Errorf("%v: Version = %q", tt.compression, v)
+		}
+		if model, _ := dng.Main.TagGetter().GetUniqueCameraModel(); model != opt.UniqueCameraModel {
+			t.Errorf("%v: UniqueCameraModel = %q", tt.compression, model)
+		}
+		raw, err := dng.DecodeRaw()
+		if err != nil {
+			t.Fatalf("%v: %v", tt.compression, err)
+		}
+		if !reflect.DeepEqual(raw, m) {
+			t.Errorf("%v: DecodeRaw differs", tt.compression)
+		}
+		if got, err := dng.CFA(); err != nil || !reflect.DeepEqual(got, cfa) {
+			t.Errorf("%v: CFA = %+v, %v", tt.compression, got, err)
+		}
+		if r := dng.ActiveArea(); r != opt.ActiveArea {
+			t.Errorf("%v: ActiveArea = %v", tt.compression, r)
+		}
+		if r := dng.DefaultCrop(); r != opt.DefaultCrop {
+			t.Errorf("%v: DefaultCrop = %v", tt.compression, r)
+		}
+		if black, _ := dng.Raw.TagGetter().GetBlackLevel(); !reflect.DeepEqual(black, opt.BlackLevel) {
+			t.Errorf("%v: BlackLevel = %v", tt.compression, black)
+		}
+		if got := dng.ColorMatrix(1); !floatsNear(got, matrix, 1e-4) {
+			t.Errorf("%v: ColorMatrix(1) = %v", tt.compression, got)
+		}
+		if got := dng.AsShotNeutral(); !reflect.DeepEqual(got, opt.AsShotNeutral) {
+			t.Errorf("%v: AsShotNeutral = %v", tt.compression, got)
+		}
+		if v, _ := dng.CalibrationIlluminant(1); v != 21 || dng.BaselineExposure() != 0.25 {
+			t.Errorf("%v: CalibrationIlluminant(1) = %v, BaselineExposure = %v", tt.compression, v, dng.BaselineExposure())
+		}
+		if rgb, err := dng.DecodeRGB(); err != nil || rgb.Bounds() != image.Rect(0, 0, 32, 24) {
+			t.Errorf("%v: DecodeRGB = %v, %v", tt.compression, rgb.Bounds(), err)
+		}
+
+		// The preview in IFD 0 is rendered from the default crop.
+		preview, err := p.DecodeImage(0, 0)
+		if err != nil {
+			t.Fatalf("%v: %v", tt.compression, err)
+		}
+		if b := preview.Bounds(); b != image.Rect(0, 0, 32, 24) {
+			t.Errorf("%v: preview bounds %v", tt.compression, b)
+		}
+	}
+}
+
+func TestEncodeDNG_linearRaw(t *testing.T) {
+	m := image.NewRGBA64(image.Rect(0, 0, 300, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 300; x++ {
+			m.SetRGBA64(x, y, color.RGBA64{uint16(x * 200), uint16(y * 3000), 0x8000, 0xffff})
+		}
+	}
+	opt := &DNGOptions{
+		Compression:       TagValue_CompressionType_JPEG,
+		UniqueCameraModel: "Scanner",
+		ColorMatrix:       [2][]float64{{1, 0, 0, 0, 1, 0, 0, 0, 1}},
+	}
+	var buf bytes.Buffer
+	if err := EncodeDNG(&buf, m, opt); err != nil {
+		t.Fatal(err)
+	}
+	p, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dng, err := p.DNG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw, err := dng.DecodeRaw(); err != nil || !reflect.DeepEqual(raw, m) {
+		t.Errorf("DecodeRaw = %v", err)
+	}
+	if rgb, err := dng.DecodeRGB(); err != nil || !reflect.DeepEqual(rgb, m) {
+		t.Errorf("DecodeRGB = %v", err)
+	}
+	// The preview is reduced to fit into 256x256 pixels.
+	if cfg, err := p.ImageConfig(0, 0); err != nil || cfg.Width != 150 || cfg.Height != 10 {
+		t.Errorf("preview = %+v, %v", cfg, err)
+	}
+
+	for name, opt := range map[string]*DNGOptions{
+		"no model":  {ColorMatrix: opt.ColorMatrix},
+		"no matrix": {UniqueCameraModel: "Scanner"},
+		"CFA":       {UniqueCameraModel: "Scanner", ColorMatrix: opt.ColorMatrix, CFA: &DNGCFA{Width: 1, Height: 1, Pattern: []int{0}, PlaneColors: []int{0, 1, 2}}},
+		"neutral":   {UniqueCameraModel: "Scanner", ColorMatrix: opt.ColorMatrix, AsShotNeutral: []float64{1, 1}},
+	} {
+		if err := EncodeDNG(ioutil.Discard, m, opt); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+	if err := EncodeDNG(ioutil.Discard, image.NewGray16(image.Rect(0, 0, 4, 4)), opt); err == nil {
+		t.Errorf("CFA image without CFA: no error")
+	}
+}
+
+func TestDNG_planeColors(t *testing.T) {
+	// A cyan, magenta and yellow CFA is stored, but not rendered.
+	opt := &DNGOptions{
+		CFA:               &DNGCFA{Width: 2, Height: 2, Pattern: []int{0, 1, 1, 2}, PlaneColors: []int{3, 4, 5}},
+		UniqueCameraModel: "Lab Instrument",
+		ColorMatrix:       [2][]float64{{1, 0, 0, 0, 1, 0, 0, 0, 1}},
+		Preview:           image.NewGray(image.Rect(0, 0, 2, 2)),
+	}
+	var buf bytes.Buffer
+	if err := EncodeDNG(&buf, image.NewGray16(image.Rect(0, 0, 4, 4)), opt); err != nil {
+		t.Fatal(err)
+	}
+	p, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dng, err := p.DNG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dng.DecodeRaw(); err != nil {
+		t.Errorf("DecodeRaw = %v", err)
+	}
+	if _, err := dng.DecodeRGB(); err == nil {
+		t.Errorf("DecodeRGB: no error")
+	}
+
+	// Without a preview, it would have to be rendered.
+	opt.Preview = nil
+	if err := EncodeDNG(ioutil.Discard, image.NewGray16(image.Rect(0, 0, 4, 4)), opt); err == nil {
+		t.Errorf("no preview: no error")
+	}
+	opt.Preview = image.NewGray(image.Rect(0, 0, 2, 2))
+	opt.CFA.PlaneColors = []int{0, 1, 7}
+	if err := EncodeDNG(ioutil.Discard, image.NewGray16(image.Rect(0, 0, 4, 4)), opt); err == nil {
+		t.Errorf("plane color 7: no error")
+	}
+}
+
+func floatsNear(a, b []float64, eps float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > eps {
+			return false
+		}
+	}
+	return true
+}
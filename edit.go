@@ -0,0 +1,123 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// editIFD changes the tags of the i-th image of the file f: the tags of
+// set are added or replaced, and the tags of remove are deleted.
+//
+// The edited IFD is appended to f, with a copy of the values of all its
+// tags that do not fit in their entries, and linked in place of the
+// original, whose bytes are left unused. Only the image data and the other
+// IFDs are not moved, so that every edit grows f by the size of the IFD.
+func editIFD(f io.ReadWriteSeeker, i int, set []ifdEntry, remove ...TagType) error {
+	h, ifd, ptr, err := findIFD(f, i)
+	if err != nil {
+		return err
+	}
+
+	skip := make(map[TagType]bool)
+	for _, tag := range remove {
+		skip[tag] = true
+	}
+	for _, e := range set {
+		skip[e.tag] = true
+	}
+	var d []ifdEntry
+	for _, e := range ifd.EntryMap {
+		if !skip[e.Tag] {
+			d = append(d, entryData(e))
+		}
+	}
+	d = append(d, set...)
+
+	end, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if end%2 != 0 {
+		if _, err = f.Write([]byte{0}); err != nil {
+			return err
+		}
+		end++
+	}
+	b := ifdBytes(h, end, ifd.NextIFD, d)
+	if !h.IsBigTiff() && end+int64(len(b)) > math.MaxUint32 {
+		return fmt.Errorf("tiff: editIFD, file exceeds 4 GiB")
+	}
+	if _, err = f.Write(b); err != nil {
+		return err
+	}
+
+	if _, err = f.Seek(ptr, io.SeekStart); err != nil {
+		return err
+	}
+	if h.IsBigTiff() {
+		return binary.Write(f, h.ByteOrder, uint64(end))
+	}
+	return binary.Write(f, h.ByteOrder, uint32(end))
+}
+
+// findIFD reads the header and the i-th IFD of the file f. ptr is the
+// position of the offset of the IFD: the header's, or the next IFD offset
+// at the end of the IFD before it.
+func findIFD(f io.ReadSeeker, i int) (h *Header, ifd *IFD, ptr int64, err error) {
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return
+	}
+	if h, err = ReadHeader(f); err != nil {
+		return
+	}
+	if !h.Valid() {
+		err = fmt.Errorf("tiff: editIFD, invalid header: %v", h)
+		return
+	}
+
+	countLen, entryLen := int64(2), int64(12)
+	ptr = 4
+	if h.IsBigTiff() {
+		countLen, entryLen, ptr = 8, 20, 8
+	}
+	offset := h.FirstIFD
+	for k := 0; ; k++ {
+		if offset == 0 {
+			err = fmt.Errorf("tiff: editIFD, image %d not found", i)
+			return
+		}
+		if ifd, err = ReadIFD(f, h, offset); err != nil {
+			return
+		}
+		if k == i {
+			return
+		}
+		var n int64
+		if n, err = readEntryCount(f, h, offset); err != nil {
+			return
+		}
+		ptr = offset + countLen + entryLen*n
+		offset = ifd.NextIFD
+	}
+}
+
+// readEntryCount returns the number of entries of the IFD at offset.
+func readEntryCount(r io.ReadSeeker, h *Header, offset int64) (int64, error) {
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	if h.IsBigTiff() {
+		var n uint64
+		err := binary.Read(r, h.ByteOrder, &n)
+		return int64(n), err
+	}
+	var n uint16
+	err := binary.Read(r, h.ByteOrder, &n)
+	return int64(n), err
+}
@@ -0,0 +1,59 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// memFile is an in-memory file, edited by the Update functions in tests.
+type memFile struct {
+	data []byte
+	off  int64
+}
+
+func (f *memFile) Read(b []byte) (int, error) {
+	if f.off >= int64(len(f.data)) {
+		return 0, io.EOF
+	}
+	n := copy(b, f.data[f.off:])
+	f.off += int64(n)
+	return n, nil
+}
+
+func (f *memFile) Write(b []byte) (int, error) {
+	if end := f.off + int64(len(b)); end > int64(len(f.data)) {
+		f.data = append(f.data, make([]byte, end-int64(len(f.data)))...)
+	}
+	n := copy(f.data[f.off:], b)
+	f.off += int64(n)
+	return n, nil
+}
+
+func (f *memFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += f.off
+	case io.SeekEnd:
+		offset += int64(len(f.data))
+	}
+	if offset < 0 {
+		return 0, errors.New("memFile.Seek: negative position")
+	}
+	f.off = offset
+	return offset, nil
+}
+
+// open opens the file as edited so far.
+func (f *memFile) open(t *testing.T) *Reader {
+	p, err := OpenReader(bytes.NewReader(f.data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
@@ -6,47 +6,85 @@ package tiff
 
 import (
 	"bytes"
-	"compress/zlib"
 	"encoding/binary"
+	"fmt"
 	"image"
 	"io"
+	"math"
+	"runtime"
 	"sort"
+	"sync"
 )
 
 // The TIFF format allows to choose the order of the different elements freely.
 // The basic structure of a TIFF file written by this package is:
 //
-//   1. Header (8 bytes).
-//   2. Image data.
+//   1. Header (8 bytes, or 16 bytes for BigTIFF).
+//   2. Image data, one strip or tile after another.
 //   3. Image File Directory (IFD).
 //   4. "Pointer area" for larger entries in the IFD.
 
-// We only write little-endian TIFF files.
-var enc = binary.LittleEndian
-
 // An ifdEntry is a single entry in an Image File Directory.
 // A value of type DataType_Rational is composed of two 32-bit values,
 // thus data contains two uints (numerator and denominator) for a single number.
 type ifdEntry struct {
 	tag      TagType
 	datatype DataType
-	data     []uint32
+	data     []uint64
+}
+
+func (e ifdEntry) count() int {
+	if e.datatype == DataType_Rational || e.datatype == DataType_SRational {
+		return len(e.data) / 2
+	}
+	return len(e.data)
 }
 
-func (e ifdEntry) putData(p []byte) {
+func (e ifdEntry) putData(order binary.ByteOrder, p []byte) {
 	for _, d := range e.data {
 		switch e.datatype {
-		case DataType_Byte, DataType_ASCII:
+		case DataType_Byte, DataType_ASCII, DataType_SByte, DataType_Undefined:
 			p[0] = byte(d)
 			p = p[1:]
-		case DataType_Short:
-			enc.PutUint16(p, uint16(d))
+		case DataType_Short, DataType_SShort:
+			order.PutUint16(p, uint16(d))
 			p = p[2:]
-		case DataType_Long, DataType_Rational:
-			enc.PutUint32(p, uint32(d))
+		case DataType_Long, DataType_Rational, DataType_SLong, DataType_SRational, DataType_Float, DataType_IFD:
+			order.PutUint32(p, uint32(d))
 			p = p[4:]
+		case DataType_Long8, DataType_Double, DataType_SLong8, DataType_IFD8:
+			order.PutUint64(p, d)
+			p = p[8:]
+		}
+	}
+}
+
+// entryData returns the entry e of a decoded IFD as an ifdEntry, with the
+// raw bits of its values. Rationals become two values each.
+func entryData(e *IFDEntry) ifdEntry {
+	size, n := e.DataType.ByteSize(), e.Count
+	if e.DataType == DataType_Rational || e.DataType == DataType_SRational {
+		size, n = 4, 2*n
+	}
+	d := ifdEntry{tag: e.Tag, datatype: e.DataType}
+	if size == 0 {
+		return d
+	}
+	// Inline values are padded to the size of an offset.
+	order := e.Header.ByteOrder
+	for p := e.Data; len(p) >= size && len(d.data) < n; p = p[size:] {
+		switch size {
+		case 1:
+			d.data = append(d.data, uint64(p[0]))
+		case 2:
+			d.data = append(d.data, uint64(order.Uint16(p)))
+		case 4:
+			d.data = append(d.data, uint64(order.Uint32(p)))
+		case 8:
+			d.data = append(d.data, order.Uint64(p))
 		}
 	}
+	return d
 }
 
 type byTag []ifdEntry
@@ -55,109 +93,448 @@ func (d byTag) Len() int           { return len(d) }
 func (d byTag) Less(i, j int) bool { return d[i].tag < d[j].tag }
 func (d byTag) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
 
-func encodeGray(w io.Writer, pix []uint8, dx, dy, stride int, predictor bool) error {
-	if !predictor {
-		return writePix(w, pix, dy, dx, stride)
-	}
-	buf := make([]byte, dx)
-	for y := 0; y < dy; y++ {
-		min := y*stride + 0
-		max := y*stride + dx
-		off := 0
-		var v0 uint8
-		for i := min; i < max; i++ {
-			v1 := pix[i]
-			buf[off] = v1 - v0
-			v0 = v1
-			off++
+// A pixelEncoder converts the pixels of an image into TIFF samples.
+type pixelEncoder struct {
+	m               image.Image
+	order           binary.ByteOrder
+	photometric     TagValue_PhotometricType
+	samplesPerPixel int
+	bitsPerSample   []uint64
+	extraSamples    uint64
+	colorMap        []uint64
+
+	// putRow stores the samples of the pixels [x0, x1) of row y into dst.
+	// The coordinates are relative to the origin of m's bounds.
+	putRow func(dst []byte, x0, x1, y int)
+}
+
+func newPixelEncoder(m image.Image, order binary.ByteOrder) *pixelEncoder {
+	opaque := false
+	if o, ok := m.(interface{ Opaque() bool }); ok {
+		opaque = o.Opaque()
+	}
+	g, ok := m.(*image.Gray)
+	return newFormatEncoder(m, order, opaque, ok && isBilevel(g))
+}
+
+// newFormatEncoder returns a pixelEncoder of m, which writes RGB images
+// without alpha if opaque is set, and gray images in 1 bit per pixel if
+// bilevel is set, whatever the pixels of m.
+func newFormatEncoder(m image.Image, order binary.ByteOrder, opaque, bilevel bool) *pixelEncoder {
+	b := m.Bounds()
+	p := &pixelEncoder{
+		m:               m,
+		order:           order,
+		photometric:     TagValue_PhotometricType_RGB,
+		samplesPerPixel: 4,
+		bitsPerSample:   []uint64{8, 8, 8, 8},
+	}
+	switch m := m.(type) {
+	case *image.Paletted:
+		// Small palettes are packed into 1, 2 or 4 bits per pixel.
+		bps := uint(8)
+		switch n := len(m.Palette); {
+		case n <= 2:
+			bps = 1
+		case n <= 4:
+			bps = 2
+		case n <= 16:
+			bps = 4
 		}
-		if _, err := w.Write(buf); err != nil {
-			return err
+		size := 1 << bps
+		p.photometric = TagValue_PhotometricType_Paletted
+		p.samplesPerPixel = 1
+		p.bitsPerSample = []uint64{uint64(bps)}
+		p.colorMap = make([]uint64, size*3)
+		for i := 0; i < size && i < len(m.Palette); i++ {
+			r, g, b, _ := m.Palette[i].RGBA()
+			p.colorMap[i+0*size] = uint64(r)
+			p.colorMap[i+1*size] = uint64(g)
+			p.colorMap[i+2*size] = uint64(b)
+		}
+		p.putRow = func(dst []byte, x0, x1, y int) {
+			i := m.PixOffset(b.Min.X+x0, b.Min.Y+y)
+			packSamples(dst, m.Pix[i:i+(x1-x0)], bps)
+		}
+	case *image.Gray:
+		p.photometric = TagValue_PhotometricType_BlackIsZero
+		p.samplesPerPixel = 1
+		p.bitsPerSample = []uint64{8}
+		p.putRow = func(dst []byte, x0, x1, y int) {
+			i := m.PixOffset(b.Min.X+x0, b.Min.Y+y)
+			copy(dst, m.Pix[i:i+(x1-x0)])
+		}
+		if bilevel {
+			// Black and white images are packed into 1 bit per pixel.
+			p.bitsPerSample = []uint64{1}
+			p.putRow = func(dst []byte, x0, x1, y int) {
+				i := m.PixOffset(b.Min.X+x0, b.Min.Y+y)
+				for j, v := range m.Pix[i : i+(x1-x0)] {
+					if j%8 == 0 {
+						dst[j/8] = 0
+					}
+					dst[j/8] |= (v >> 7) << (7 - uint(j%8))
+				}
+			}
+		}
+	case *image.Gray16:
+		p.photometric = TagValue_PhotometricType_BlackIsZero
+		p.samplesPerPixel = 1
+		p.bitsPerSample = []uint64{16}
+		p.putRow = func(dst []byte, x0, x1, y int) {
+			i := m.PixOffset(b.Min.X+x0, b.Min.Y+y)
+			putSamples16(order, dst, m.Pix[i:i+(x1-x0)*2])
+		}
+	case *image.NRGBA:
+		if opaque {
+			p.setRGB(8, func(dst []byte, x0, x1, y int) {
+				i := m.PixOffset(b.Min.X+x0, b.Min.Y+y)
+				dropAlpha(dst, m.Pix[i:i+(x1-x0)*4], 1)
+			})
+			break
+		}
+		p.extraSamples = 2 // Unassociated alpha.
+		p.putRow = func(dst []byte, x0, x1, y int) {
+			i := m.PixOffset(b.Min.X+x0, b.Min.Y+y)
+			copy(dst, m.Pix[i:i+(x1-x0)*4])
+		}
+	case *image.NRGBA64:
+		if opaque {
+			p.setRGB(16, func(dst []byte, x0, x1, y int) {
+				i := m.PixOffset(b.Min.X+x0, b.Min.Y+y)
+				n := dropAlpha(dst, m.Pix[i:i+(x1-x0)*8], 2)
+				putSamples16(order, dst, dst[:n])
+			})
+			break
+		}
+		p.extraSamples = 2 // Unassociated alpha.
+		p.bitsPerSample = []uint64{16, 16, 16, 16}
+		p.putRow = func(dst []byte, x0, x1, y int) {
+			i := m.PixOffset(b.Min.X+x0, b.Min.Y+y)
+			putSamples16(order, dst, m.Pix[i:i+(x1-x0)*8])
+		}
+	case *image.RGBA:
+		if opaque {
+			p.setRGB(8, func(dst []byte, x0, x1, y int) {
+				i := m.PixOffset(b.Min.X+x0, b.Min.Y+y)
+				dropAlpha(dst, m.Pix[i:i+(x1-x0)*4], 1)
+			})
+			break
+		}
+		p.extraSamples = 1 // Associated alpha.
+		p.putRow = func(dst []byte, x0, x1, y int) {
+			i := m.PixOffset(b.Min.X+x0, b.Min.Y+y)
+			copy(dst, m.Pix[i:i+(x1-x0)*4])
+		}
+	case *image.RGBA64:
+		if opaque {
+			p.setRGB(16, func(dst []byte, x0, x1, y int) {
+				i := m.PixOffset(b.Min.X+x0, b.Min.Y+y)
+				n := dropAlpha(dst, m.Pix[i:i+(x1-x0)*8], 2)
+				putSamples16(order, dst, dst[:n])
+			})
+			break
+		}
+		p.extraSamples = 1 // Associated alpha.
+		p.bitsPerSample = []uint64{16, 16, 16, 16}
+		p.putRow = func(dst []byte, x0, x1, y int) {
+			i := m.PixOffset(b.Min.X+x0, b.Min.Y+y)
+			putSamples16(order, dst, m.Pix[i:i+(x1-x0)*8])
+		}
+	default:
+		if opaque {
+			p.setRGB(8, func(dst []byte, x0, x1, y int) {
+				off := 0
+				for x := x0; x < x1; x++ {
+					r, g, b, _ := m.At(b.Min.X+x, b.Min.Y+y).RGBA()
+					dst[off+0] = uint8(r >> 8)
+					dst[off+1] = uint8(g >> 8)
+					dst[off+2] = uint8(b >> 8)
+					off += 3
+				}
+			})
+			break
+		}
+		p.extraSamples = 1 // Associated alpha.
+		p.putRow = func(dst []byte, x0, x1, y int) {
+			off := 0
+			for x := x0; x < x1; x++ {
+				r, g, b, a := m.At(b.Min.X+x, b.Min.Y+y).RGBA()
+				dst[off+0] = uint8(r >> 8)
+				dst[off+1] = uint8(g >> 8)
+				dst[off+2] = uint8(b >> 8)
+				dst[off+3] = uint8(a >> 8)
+				off += 4
+			}
 		}
 	}
-	return nil
+	return p
+}
+
+// setRGB makes p write opaque images as RGB samples of the given depth
+// without an alpha channel.
+func (p *pixelEncoder) setRGB(bps uint64, putRow func(dst []byte, x0, x1, y int)) {
+	p.samplesPerPixel = 3
+	p.bitsPerSample = []uint64{bps, bps, bps}
+	p.putRow = putRow
+}
+
+// dropAlpha copies the color samples of the 4-channel pixels in src to dst,
+// skipping the alpha samples. Each sample is n bytes wide. It returns the
+// number of bytes written.
+func dropAlpha(dst, src []byte, n int) int {
+	j := 0
+	for i := 0; i+4*n <= len(src); i += 4 * n {
+		j += copy(dst[j:], src[i:i+3*n])
+	}
+	return j
 }
 
-func encodeGray16(w io.Writer, pix []uint8, dx, dy, stride int, predictor bool) error {
-	buf := make([]byte, dx*2)
-	for y := 0; y < dy; y++ {
-		min := y*stride + 0
-		max := y*stride + dx*2
-		off := 0
-		var v0 uint16
-		for i := min; i < max; i += 2 {
-			// An image.Gray16's Pix is in big-endian order.
-			v1 := uint16(pix[i])<<8 | uint16(pix[i+1])
-			if predictor {
-				v0, v1 = v1, v1-v0
+// packSamples stores the low bps bits of each sample of src into dst,
+// most significant bits first. The last byte is padded with zero bits.
+func packSamples(dst, src []byte, bps uint) {
+	if bps == 8 {
+		copy(dst, src)
+		return
+	}
+	perByte := int(8 / bps)
+	mask := byte(1)<<bps - 1
+	for j, v := range src {
+		if j%perByte == 0 {
+			dst[j/perByte] = 0
+		}
+		dst[j/perByte] |= (v & mask) << (8 - bps*uint(j%perByte+1))
+	}
+}
+
+// isBilevel reports whether m contains only black and white pixels.
+func isBilevel(m *image.Gray) bool {
+	b := m.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		i := m.PixOffset(b.Min.X, y)
+		for _, v := range m.Pix[i : i+b.Dx()] {
+			if v != 0 && v != 0xff {
+				return false
 			}
-			// We only write little-endian TIFF files.
-			buf[off+0] = byte(v1)
-			buf[off+1] = byte(v1 >> 8)
-			off += 2
 		}
-		if _, err := w.Write(buf); err != nil {
-			return err
+	}
+	return true
+}
+
+// putSamples16 converts the big-endian 16-bit samples of an image.Gray16,
+// image.RGBA64 or image.NRGBA64 into the byte order of the file.
+func putSamples16(order binary.ByteOrder, dst, src []byte) {
+	for i := 0; i+1 < len(src); i += 2 {
+		order.PutUint16(dst[i:], uint16(src[i])<<8|uint16(src[i+1]))
+	}
+}
+
+func (p *pixelEncoder) bitsPerPixel() int {
+	var n int
+	for _, v := range p.bitsPerSample {
+		n += int(v)
+	}
+	return n
+}
+
+// canPredict reports whether the horizontal predictor can be applied
+// to the samples of p.
+func (p *pixelEncoder) canPredict() bool {
+	return p.bitsPerSample[0] == 8 || p.bitsPerSample[0] == 16
+}
+
+// predict applies the horizontal differencing predictor to one row of samples.
+func (p *pixelEncoder) predict(row []byte) {
+	spp := p.samplesPerPixel
+	switch p.bitsPerSample[0] {
+	case 8:
+		for i := len(row) - 1; i >= spp; i-- {
+			row[i] -= row[i-spp]
+		}
+	case 16:
+		for i := len(row) - 2; i >= 2*spp; i -= 2 {
+			v := p.order.Uint16(row[i:]) - p.order.Uint16(row[i-2*spp:])
+			p.order.PutUint16(row[i:], v)
 		}
 	}
-	return nil
 }
 
-func encodeRGBA(w io.Writer, pix []uint8, dx, dy, stride int, predictor bool) error {
-	if !predictor {
-		return writePix(w, pix, dy, dx*4, stride)
-	}
-	buf := make([]byte, dx*4)
-	for y := 0; y < dy; y++ {
-		min := y*stride + 0
-		max := y*stride + dx*4
-		off := 0
-		var r0, g0, b0, a0 uint8
-		for i := min; i < max; i += 4 {
-			r1, g1, b1, a1 := pix[i+0], pix[i+1], pix[i+2], pix[i+3]
-			buf[off+0] = r1 - r0
-			buf[off+1] = g1 - g0
-			buf[off+2] = b1 - b0
-			buf[off+3] = a1 - a0
-			off += 4
-			r0, g0, b0, a0 = r1, g1, b1, a1
+// An imageEncoder splits an image into strips or tiles and encodes them.
+type imageEncoder struct {
+	*pixelEncoder
+
+	size         image.Point
+	compression  TagValue_CompressionType
+	predictor    bool
+	subfileType  TagValue_NewSubfileType
+	resolution   Resolution
+	geoTags      []ifdEntry
+	gdalTags     []ifdEntry
+	metadataTags []ifdEntry // Descriptive metadata, such as XMP.
+	iccProfile   []byte
+	dngTags      []ifdEntry // See EncodeDNG.
+	bigTiff      bool
+	workers      int
+	tiled        bool
+	blockWidth   int
+	blockHeight  int
+	blocksAcross int
+	blocksDown   int
+}
+
+func newImageEncoder(m image.Image, opt *Options) (e *imageEncoder, err error) {
+	return newSizedEncoder(m.Bounds().Size(), opt, func(order binary.ByteOrder) *pixelEncoder {
+		return newPixelEncoder(m, order)
+	})
+}
+
+// newSizedEncoder returns an imageEncoder of an image of the given size,
+// whose pixels are converted by the pixelEncoder returned by pix.
+func newSizedEncoder(size image.Point, opt *Options, pix func(order binary.ByteOrder) *pixelEncoder) (e *imageEncoder, err error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+	order := opt.ByteOrder
+	switch order {
+	case nil:
+		order = binary.LittleEndian
+	case binary.LittleEndian, binary.BigEndian:
+	default:
+		err = fmt.Errorf("tiff: Encode, bad byte order %v", order)
+		return
+	}
+	e = &imageEncoder{
+		pixelEncoder: pix(order),
+		size:         size,
+		compression:  opt.Compression,
+		bigTiff:      opt.BigTiff,
+		workers:      opt.Workers,
+	}
+
+	switch e.compression {
+	case TagValue_CompressionType_Nil:
+		e.compression = TagValue_CompressionType_None
+	case TagValue_CompressionType_None, TagValue_CompressionType_Deflate, TagValue_CompressionType_LZW:
+	default:
+		err = fmt.Errorf("tiff: Encode, unsupport %v compression type", e.compression)
+		return
+	}
+	e.predictor = opt.Predictor && e.canPredict()
+
+	e.resolution = NewResolutionDPI(72, 72)
+	if opt.Resolution != nil {
+		if !opt.Resolution.Valid() {
+			err = fmt.Errorf("tiff: Encode, bad resolution %v", opt.Resolution)
+			return
 		}
-		if _, err := w.Write(buf); err != nil {
-			return err
+		e.resolution = *opt.Resolution
+	}
+
+	if e.geoTags, err = geoTiffEntries(opt.GeoKeys, opt.GeoTransform); err != nil {
+		return
+	}
+	e.gdalTags = gdalEntries(opt.GDALMetadata, opt.GDALNoData)
+	if opt.XMP != nil {
+		e.metadataTags = append(e.metadataTags, ifdEntry{TagType_XMP, DataType_Byte, bytesData(opt.XMP)})
+	}
+	if opt.IPTC != nil {
+		e.metadataTags = append(e.metadataTags, ifdEntry{TagType_IPTC, DataType_Undefined, bytesData(opt.IPTC)})
+	}
+	if opt.Photoshop != nil {
+		e.metadataTags = append(e.metadataTags, ifdEntry{TagType_Photoshop, DataType_Byte, bytesData(opt.Photoshop)})
+	}
+	if opt.ICCProfile != nil {
+		var profile *ICCProfile
+		if profile, err = ParseICCProfile(opt.ICCProfile); err != nil {
+			err = fmt.Errorf("tiff: Encode, bad ICC profile: %v", err)
+			return
 		}
+		if cs, ok := iccColorSpace(e.photometric); !ok || cs != profile.ColorSpace {
+			err = fmt.Errorf("tiff: Encode, %v ICC profile for a %v image", profile.ColorSpace, e.photometric)
+			return
+		}
+		e.iccProfile = opt.ICCProfile
 	}
-	return nil
+
+	switch {
+	case opt.TileWidth != 0 || opt.TileLength != 0:
+		if opt.TileWidth <= 0 || opt.TileWidth%16 != 0 || opt.TileLength <= 0 || opt.TileLength%16 != 0 {
+			err = fmt.Errorf("tiff: Encode, bad tile size %dx%d, must be multiples of 16", opt.TileWidth, opt.TileLength)
+			return
+		}
+		e.tiled = true
+		e.blockWidth = opt.TileWidth
+		e.blockHeight = opt.TileLength
+	default:
+		if opt.RowsPerStrip < 0 {
+			err = fmt.Errorf("tiff: Encode, bad rows per strip %d", opt.RowsPerStrip)
+			return
+		}
+		e.blockWidth = e.size.X
+		e.blockHeight = opt.RowsPerStrip
+		if e.blockHeight == 0 || e.blockHeight > e.size.Y {
+			e.blockHeight = e.size.Y
+		}
+	}
+
+	// Empty images are still written with a single (empty) block.
+	e.blocksAcross, e.blocksDown = 1, 1
+	if e.blockWidth > 0 && e.size.X > 0 {
+		e.blocksAcross = (e.size.X + e.blockWidth - 1) / e.blockWidth
+	}
+	if e.blockHeight > 0 && e.size.Y > 0 {
+		e.blocksDown = (e.size.Y + e.blockHeight - 1) / e.blockHeight
+	}
+	return
+}
+
+func (e *imageEncoder) blockNum() int {
+	return e.blocksAcross * e.blocksDown
 }
 
-func encodeRGBA64(w io.Writer, pix []uint8, dx, dy, stride int, predictor bool) error {
-	buf := make([]byte, dx*8)
-	for y := 0; y < dy; y++ {
-		min := y*stride + 0
-		max := y*stride + dx*8
-		off := 0
-		var r0, g0, b0, a0 uint16
-		for i := min; i < max; i += 8 {
-			// An image.RGBA64's Pix is in big-endian order.
-			r1 := uint16(pix[i+0])<<8 | uint16(pix[i+1])
-			g1 := uint16(pix[i+2])<<8 | uint16(pix[i+3])
-			b1 := uint16(pix[i+4])<<8 | uint16(pix[i+5])
-			a1 := uint16(pix[i+6])<<8 | uint16(pix[i+7])
-			if predictor {
-				r0, r1 = r1, r1-r0
-				g0, g1 = g1, g1-g0
-				b0, b1 = b1, b1-b0
-				a0, a1 = a1, a1-a0
+// blockBounds returns the bounds of block i, in the coordinates used by
+// putRow. Tiles on the right and bottom edges may extend past the image.
+func (e *imageEncoder) blockBounds(i int) image.Rectangle {
+	col, row := i%e.blocksAcross, i/e.blocksAcross
+	r := image.Rect(
+		col*e.blockWidth, row*e.blockHeight,
+		(col+1)*e.blockWidth, (row+1)*e.blockHeight,
+	)
+	if !e.tiled {
+		// The last strip only holds the remaining rows.
+		r.Max.Y = minInt(r.Max.Y, e.size.Y)
+	}
+	return r
+}
+
+// rowSize returns the number of bytes used by a row of n pixels.
+func (e *imageEncoder) rowSize(n int) int {
+	return (n*e.bitsPerPixel() + 7) / 8
+}
+
+// blockSize returns the uncompressed size of block i in bytes.
+func (e *imageEncoder) blockSize(i int) int {
+	r := e.blockBounds(i)
+	return r.Dy() * e.rowSize(r.Dx())
+}
+
+// writeBlock writes the uncompressed samples of block i to w.
+// The parts of a tile that lie outside the image are zero.
+func (e *imageEncoder) writeBlock(w io.Writer, i int) error {
+	r := e.blockBounds(i)
+	buf := make([]byte, e.rowSize(r.Dx()))
+	x1 := minInt(r.Max.X, e.size.X)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		if x1 < r.Max.X || y >= e.size.Y {
+			for j := range buf {
+				buf[j] = 0
 			}
-			// We only write little-endian TIFF files.
-			buf[off+0] = byte(r1)
-			buf[off+1] = byte(r1 >> 8)
-			buf[off+2] = byte(g1)
-			buf[off+3] = byte(g1 >> 8)
-			buf[off+4] = byte(b1)
-			buf[off+5] = byte(b1 >> 8)
-			buf[off+6] = byte(a1)
-			buf[off+7] = byte(a1 >> 8)
-			off += 8
+		}
+		if y < e.size.Y && r.Min.X < x1 {
+			e.putRow(buf, r.Min.X, x1, y)
+		}
+		if e.predictor {
+			e.predict(buf)
 		}
 		if _, err := w.Write(buf); err != nil {
 			return err
@@ -166,113 +543,284 @@ func encodeRGBA64(w io.Writer, pix []uint8, dx, dy, stride int, predictor bool)
 	return nil
 }
 
-func encode(w io.Writer, m image.Image, predictor bool) error {
-	bounds := m.Bounds()
-	buf := make([]byte, 4*bounds.Dx())
-	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
-		off := 0
-		if predictor {
-			var r0, g0, b0, a0 uint8
-			for x := bounds.Min.X; x < bounds.Max.X; x++ {
-				r, g, b, a := m.At(x, y).RGBA()
-				r1 := uint8(r >> 8)
-				g1 := uint8(g >> 8)
-				b1 := uint8(b >> 8)
-				a1 := uint8(a >> 8)
-				buf[off+0] = r1 - r0
-				buf[off+1] = g1 - g0
-				buf[off+2] = b1 - b0
-				buf[off+3] = a1 - a0
-				off += 4
-				r0, g0, b0, a0 = r1, g1, b1, a1
-			}
-		} else {
-			for x := bounds.Min.X; x < bounds.Max.X; x++ {
-				r, g, b, a := m.At(x, y).RGBA()
-				buf[off+0] = uint8(r >> 8)
-				buf[off+1] = uint8(g >> 8)
-				buf[off+2] = uint8(b >> 8)
-				buf[off+3] = uint8(a >> 8)
-				off += 4
+// encodeBlock returns the compressed data of block i.
+func (e *imageEncoder) encodeBlock(i int) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(e.blockSize(i))
+	if err := e.writeBlock(&buf, i); err != nil {
+		return nil, err
+	}
+	r := e.blockBounds(i)
+	return e.compression.Encode(buf.Bytes(), r.Dx(), r.Dy())
+}
+
+// encodeBlocks returns the data of all blocks, as returned by encode, and
+// their sizes. The blocks are encoded on e.workers goroutines, and as they
+// are encoded independently, the data does not depend on their number.
+// The error of the first failing block is returned.
+func (e *imageEncoder) encodeBlocks(encode func(i int) ([]byte, error)) (blocks [][]byte, counts []uint64, err error) {
+	return e.encodeBlockRange(0, e.blockNum(), encode)
+}
+
+// encodeBlockRange is like encodeBlocks for the blocks [i0, i1) only.
+func (e *imageEncoder) encodeBlockRange(i0, i1 int, encode func(i int) ([]byte, error)) (blocks [][]byte, counts []uint64, err error) {
+	blocks = make([][]byte, i1-i0)
+	counts = make([]uint64, i1-i0)
+	workers := e.workers
+	if workers < 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers <= 1 || len(blocks) <= 1 {
+		for i := range blocks {
+			if blocks[i], err = encode(i0 + i); err != nil {
+				return nil, nil, err
 			}
+			counts[i] = uint64(len(blocks[i]))
 		}
-		if _, err := w.Write(buf); err != nil {
-			return err
+		return
+	}
+
+	errs := make([]error, len(blocks))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < minInt(workers, len(blocks)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				blocks[i], errs[i] = encode(i0 + i)
+			}
+		}()
+	}
+	for i := range blocks {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	for i := range blocks {
+		if errs[i] != nil {
+			return nil, nil, errs[i]
 		}
+		counts[i] = uint64(len(blocks[i]))
 	}
-	return nil
+	return
 }
 
-// writePix writes the internal byte array of an image to w. It is less general
-// but much faster then encode. writePix is used when pix directly
-// corresponds to one of the TIFF image types.
-func writePix(w io.Writer, pix []byte, nrows, length, stride int) error {
-	if length == stride {
-		_, err := w.Write(pix[:nrows*length])
-		return err
+// ifd returns the IFD entries describing the image, whose blocks were
+// written at the given offsets with the given sizes.
+func (e *imageEncoder) ifd(offsets, counts []uint64) []ifdEntry {
+	// Strip and tile offsets of BigTIFF files are 64-bit.
+	offsetType := DataType_Long
+	if e.bigTiff {
+		offsetType = DataType_Long8
 	}
-	for ; nrows > 0; nrows-- {
-		if _, err := w.Write(pix[:length]); err != nil {
-			return err
+	ifd := []ifdEntry{
+		{TagType_ImageWidth, shortOrLong(e.size.X), []uint64{uint64(e.size.X)}},
+		{TagType_ImageLength, shortOrLong(e.size.Y), []uint64{uint64(e.size.Y)}},
+		{TagType_BitsPerSample, DataType_Short, e.bitsPerSample},
+		{TagType_Compression, DataType_Short, []uint64{uint64(e.compression)}},
+		{TagType_PhotometricInterpretation, DataType_Short, []uint64{uint64(e.photometric)}},
+		{TagType_SamplesPerPixel, DataType_Short, []uint64{uint64(e.samplesPerPixel)}},
+		{TagType_XResolution, DataType_Rational, []uint64{uint64(e.resolution.X[0]), uint64(e.resolution.X[1])}},
+		{TagType_YResolution, DataType_Rational, []uint64{uint64(e.resolution.Y[0]), uint64(e.resolution.Y[1])}},
+		{TagType_ResolutionUnit, DataType_Short, []uint64{uint64(e.resolution.Unit)}},
+	}
+	if e.subfileType != 0 {
+		ifd = append(ifd, ifdEntry{TagType_NewSubfileType, DataType_Long, []uint64{uint64(e.subfileType)}})
+	}
+	if e.tiled {
+		ifd = append(ifd,
+			ifdEntry{TagType_TileWidth, shortOrLong(e.blockWidth), []uint64{uint64(e.blockWidth)}},
+			ifdEntry{TagType_TileLength, shortOrLong(e.blockHeight), []uint64{uint64(e.blockHeight)}},
+			ifdEntry{TagType_TileOffsets, offsetType, offsets},
+			ifdEntry{TagType_TileByteCounts, offsetType, counts},
+		)
+	} else {
+		ifd = append(ifd,
+			ifdEntry{TagType_StripOffsets, offsetType, offsets},
+			ifdEntry{TagType_RowsPerStrip, shortOrLong(e.blockHeight), []uint64{uint64(e.blockHeight)}},
+			ifdEntry{TagType_StripByteCounts, offsetType, counts},
+		)
+	}
+	if e.predictor {
+		ifd = append(ifd, ifdEntry{TagType_Predictor, DataType_Short, []uint64{uint64(TagValue_PredictorType_Horizontal)}})
+	}
+	if len(e.colorMap) != 0 {
+		ifd = append(ifd, ifdEntry{TagType_ColorMap, DataType_Short, e.colorMap})
+	}
+	if e.extraSamples > 0 {
+		ifd = append(ifd, ifdEntry{TagType_ExtraSamples, DataType_Short, []uint64{e.extraSamples}})
+	}
+	ifd = append(ifd, e.geoTags...)
+	ifd = append(ifd, e.gdalTags...)
+	ifd = append(ifd, e.metadataTags...)
+	if e.iccProfile != nil {
+		ifd = append(ifd, ifdEntry{TagType_ICCProfile, DataType_Undefined, bytesData(e.iccProfile)})
+	}
+	ifd = append(ifd, e.dngTags...)
+	return ifd
+}
+
+// geoTiffEntries returns the IFD entries of the GeoTIFF tags that store
+// the given keys and transform, either of which may be nil.
+func geoTiffEntries(keys *GeoKeyDirectory, gt *GeoTransform) (ifd []ifdEntry, err error) {
+	raster := GeoRasterType_PixelIsArea
+	if keys != nil {
+		raster = keys.RasterType()
+		dir, doubles, ascii := keys.Values()
+		ifd = append(ifd, ifdEntry{TagType_GeoKeyDirectoryTag, DataType_Short, intsData(dir)})
+		if len(doubles) > 0 {
+			ifd = append(ifd, ifdEntry{TagType_GeoDoubleParamsTag, DataType_Double, floatsData(doubles)})
+		}
+		if len(ascii) > 0 {
+			ifd = append(ifd, ifdEntry{TagType_GeoAsciiParamsTag, DataType_ASCII, asciiData(ascii)})
 		}
-		pix = pix[stride:]
 	}
-	return nil
+	if gt != nil {
+		if _, ok := gt.Invert(); !ok {
+			err = fmt.Errorf("tiff: Encode, bad geotransform %v", *gt)
+			return
+		}
+		scale, tiepoints, matrix := gt.ModelTags(raster)
+		if matrix != nil {
+			ifd = append(ifd, ifdEntry{TagType_ModelTransformationTag, DataType_Double, floatsData(matrix)})
+		} else {
+			ifd = append(ifd,
+				ifdEntry{TagType_ModelPixelScaleTag, DataType_Double, floatsData(scale)},
+				ifdEntry{TagType_ModelTiepointTag, DataType_Double, floatsData(tiepoints)},
+			)
+		}
+	}
+	return
 }
 
-func writeIFD(w io.Writer, ifdOffset int, d []ifdEntry) error {
-	const ifdLen = 12
+func intsData(v []int64) []uint64 {
+	data := make([]uint64, len(v))
+	for i := range v {
+		data[i] = uint64(v[i])
+	}
+	return data
+}
 
-	var buf [ifdLen]byte
-	// Make space for "pointer area" containing IFD entry data
-	// longer than 4 bytes.
-	parea := make([]byte, 1024)
-	pstart := ifdOffset + ifdLen*len(d) + 6
-	var o int // Current offset in parea.
+func floatsData(v []float64) []uint64 {
+	data := make([]uint64, len(v))
+	for i := range v {
+		data[i] = math.Float64bits(v[i])
+	}
+	return data
+}
+
+func bytesData(b []byte) []uint64 {
+	data := make([]uint64, len(b))
+	for i := range b {
+		data[i] = uint64(b[i])
+	}
+	return data
+}
+
+// asciiData returns the bytes of s with a terminating NUL.
+func asciiData(s string) []uint64 {
+	data := make([]uint64, len(s)+1)
+	for i := 0; i < len(s); i++ {
+		data[i] = uint64(s[i])
+	}
+	return data
+}
+
+// header returns the file header for an IFD at ifdOffset.
+func (e *imageEncoder) header(ifdOffset int64) *Header {
+	h := NewHeader(e.bigTiff, ifdOffset)
+	h.ByteOrder = e.order
+	return h
+}
+
+// layout returns the offsets of blocks with the given sizes when they are
+// written one after another right after the header, and the offset of the
+// IFD that follows them. The IFD has to begin on a word boundary.
+func (e *imageEncoder) layout(counts []uint64) (offsets []uint64, ifdOffset int64) {
+	offsets = make([]uint64, len(counts))
+	off := int64(e.header(0).HeadSize())
+	for i := range counts {
+		offsets[i] = uint64(off)
+		off += int64(counts[i])
+	}
+	return offsets, off + off%2
+}
+
+// shortOrLong returns the smallest unsigned integer type that holds v.
+func shortOrLong(v int) DataType {
+	if v <= 0xffff {
+		return DataType_Short
+	}
+	return DataType_Long
+}
+
+func writeIFD(w io.Writer, h *Header, ifdOffset, nextIFD int64, d []ifdEntry) error {
+	_, err := w.Write(ifdBytes(h, ifdOffset, nextIFD, d))
+	return err
+}
+
+// ifdBytes returns the encoded IFD, followed by the "pointer area"
+// containing IFD entry data that does not fit into the entries.
+// Classic TIFF entries are 12 bytes with 4 bytes of inline data;
+// BigTIFF entries are 20 bytes with 8 bytes of inline data.
+// The IFD links to the one at nextIFD, or to none if it is zero.
+func ifdBytes(h *Header, ifdOffset, nextIFD int64, d []ifdEntry) []byte {
+	countLen, entryLen, valueLen := 2, 12, 4
+	if h.IsBigTiff() {
+		countLen, entryLen, valueLen = 8, 20, 8
+	}
+	pstart := ifdOffset + int64(countLen+entryLen*len(d)+valueLen)
 
 	// The IFD has to be written with the tags in ascending order.
 	sort.Sort(byTag(d))
 
+	dir := make([]byte, countLen+entryLen*len(d)+valueLen, countLen+entryLen*len(d)+valueLen+1024)
+	var parea []byte
+
 	// Write the number of entries in this IFD.
-	if err := binary.Write(w, enc, uint16(len(d))); err != nil {
-		return err
+	if h.IsBigTiff() {
+		h.ByteOrder.PutUint64(dir[0:8], uint64(len(d)))
+	} else {
+		h.ByteOrder.PutUint16(dir[0:2], uint16(len(d)))
 	}
-	for _, ent := range d {
-		enc.PutUint16(buf[0:2], uint16(ent.tag))
-		enc.PutUint16(buf[2:4], uint16(ent.datatype))
-		count := uint32(len(ent.data))
-		if ent.datatype == DataType_Rational {
-			count /= 2
-		}
-		enc.PutUint32(buf[4:8], count)
-		datalen := int(count * uint32(ent.datatype.ByteSize()))
-		if datalen <= 4 {
-			ent.putData(buf[8:12])
+	for i, ent := range d {
+		buf := dir[countLen+entryLen*i:][:entryLen]
+		h.ByteOrder.PutUint16(buf[0:2], uint16(ent.tag))
+		h.ByteOrder.PutUint16(buf[2:4], uint16(ent.datatype))
+		count := ent.count()
+		value := buf[entryLen-valueLen:]
+		if h.IsBigTiff() {
+			h.ByteOrder.PutUint64(buf[4:12], uint64(count))
 		} else {
-			if (o + datalen) > len(parea) {
-				newlen := len(parea) + 1024
-				for (o + datalen) > newlen {
-					newlen += 1024
-				}
-				newarea := make([]byte, newlen)
-				copy(newarea, parea)
-				parea = newarea
-			}
-			ent.putData(parea[o : o+datalen])
-			enc.PutUint32(buf[8:12], uint32(pstart+o))
-			o += datalen
+			h.ByteOrder.PutUint32(buf[4:8], uint32(count))
 		}
-		if _, err := w.Write(buf[:]); err != nil {
-			return err
+		datalen := count * ent.datatype.ByteSize()
+		if datalen <= valueLen {
+			ent.putData(h.ByteOrder, value)
+			continue
+		}
+		// Values in the pointer area begin on a word boundary.
+		if len(parea)%2 != 0 {
+			parea = append(parea, 0)
 		}
+		off := uint64(pstart) + uint64(len(parea))
+		if h.IsBigTiff() {
+			h.ByteOrder.PutUint64(value, off)
+		} else {
+			h.ByteOrder.PutUint32(value, uint32(off))
+		}
+		parea = append(parea, make([]byte, datalen)...)
+		ent.putData(h.ByteOrder, parea[len(parea)-datalen:])
 	}
 	// The IFD ends with the offset of the next IFD in the file,
 	// or zero if it is the last one (page 14).
-	if err := binary.Write(w, enc, uint32(0)); err != nil {
-		return err
+	if h.IsBigTiff() {
+		h.ByteOrder.PutUint64(dir[len(dir)-valueLen:], uint64(nextIFD))
+	} else {
+		h.ByteOrder.PutUint32(dir[len(dir)-valueLen:], uint32(nextIFD))
 	}
-	_, err := w.Write(parea[:o])
-	return err
+	return append(dir, parea...)
 }
 
 func EncodeAll(w io.Writer, m [][]image.Image, opt [][]*Options) error {
@@ -280,148 +828,65 @@ func EncodeAll(w io.Writer, m [][]image.Image, opt [][]*Options) error {
 }
 
 // Encode writes the image m to w. opt determines the options used for
-// encoding, such as the compression type. If opt is nil, an uncompressed
-// image is written.
+// encoding, such as the compression type and the strip or tile layout.
+// If opt is nil, an uncompressed image is written as a single strip.
+//
+// A BigTIFF file is written if opt.BigTiff is set, or if the output
+// would not fit into the 32-bit offsets of a classic TIFF file.
+//
+// Opaque images are written as RGB without an alpha channel, keeping
+// 16 bits per sample for image.RGBA64 and image.NRGBA64. An image.Gray
+// holding only black and white pixels is packed into 1 bit per pixel,
+// and an image.Paletted with at most 2, 4 or 16 colors into 1, 2 or 4 bits.
 func Encode(w io.Writer, m image.Image, opt *Options) error {
-	d := m.Bounds().Size()
-
-	compression := TagValue_CompressionType_None
-	predictor := false
-
-	_, err := io.WriteString(w, ClassicTiffLittleEnding)
+	e, err := newImageEncoder(m, opt)
 	if err != nil {
 		return err
 	}
 
-	// Compressed data is written into a buffer first, so that we
-	// know the compressed size.
-	var buf bytes.Buffer
-	// dst holds the destination for the pixel data of the image --
-	// either w or a writer to buf.
-	var dst io.Writer
-	// imageLen is the length of the pixel data in bytes.
-	// The offset of the IFD is imageLen + 8 header bytes.
-	var imageLen int
-
-	switch compression {
-	case TagValue_CompressionType_None:
-		dst = w
-		// Write IFD offset before outputting pixel data.
-		switch m.(type) {
-		case *image.Paletted:
-			imageLen = d.X * d.Y * 1
-		case *image.Gray:
-			imageLen = d.X * d.Y * 1
-		case *image.Gray16:
-			imageLen = d.X * d.Y * 2
-		case *image.RGBA64:
-			imageLen = d.X * d.Y * 8
-		case *image.NRGBA64:
-			imageLen = d.X * d.Y * 8
-		default:
-			imageLen = d.X * d.Y * 4
-		}
-		err = binary.Write(w, enc, uint32(imageLen+8))
-		if err != nil {
-			return err
+	// Compressed blocks are written into buffers first, so that we
+	// know their compressed size. Uncompressed blocks are written
+	// straight to w.
+	var blocks [][]byte
+	var counts []uint64
+	if e.compression == TagValue_CompressionType_None {
+		counts = make([]uint64, e.blockNum())
+		for i := range counts {
+			counts[i] = uint64(e.blockSize(i))
 		}
-	case TagValue_CompressionType_Deflate:
-		dst = zlib.NewWriter(&buf)
+	} else if blocks, counts, err = e.encodeBlocks(e.encodeBlock); err != nil {
+		return err
 	}
 
-	pr := uint32(TagValue_PredictorType_None)
-	photometricInterpretation := uint32(TagValue_PhotometricType_RGB)
-	samplesPerPixel := uint32(4)
-	bitsPerSample := []uint32{8, 8, 8, 8}
-	extraSamples := uint32(0)
-	colorMap := []uint32{}
-
-	if predictor {
-		pr = uint32(TagValue_PredictorType_Horizontal)
-	}
-	switch m := m.(type) {
-	case *image.Paletted:
-		photometricInterpretation = uint32(TagValue_PhotometricType_Paletted)
-		samplesPerPixel = 1
-		bitsPerSample = []uint32{8}
-		colorMap = make([]uint32, 256*3)
-		for i := 0; i < 256 && i < len(m.Palette); i++ {
-			r, g, b, _ := m.Palette[i].RGBA()
-			colorMap[i+0*256] = uint32(r)
-			colorMap[i+1*256] = uint32(g)
-			colorMap[i+2*256] = uint32(b)
+	offsets, ifdOffset := e.layout(counts)
+	if !e.bigTiff {
+		ifdLen := len(ifdBytes(e.header(ifdOffset), ifdOffset, 0, e.ifd(offsets, counts)))
+		if ifdOffset+int64(ifdLen) > math.MaxUint32 {
+			e.bigTiff = true
+			offsets, ifdOffset = e.layout(counts)
 		}
-		err = encodeGray(dst, m.Pix, d.X, d.Y, m.Stride, predictor)
-	case *image.Gray:
-		photometricInterpretation = uint32(TagValue_PhotometricType_BlackIsZero)
-		samplesPerPixel = 1
-		bitsPerSample = []uint32{8}
-		err = encodeGray(dst, m.Pix, d.X, d.Y, m.Stride, predictor)
-	case *image.Gray16:
-		photometricInterpretation = uint32(TagValue_PhotometricType_BlackIsZero)
-		samplesPerPixel = 1
-		bitsPerSample = []uint32{16}
-		err = encodeGray16(dst, m.Pix, d.X, d.Y, m.Stride, predictor)
-	case *image.NRGBA:
-		extraSamples = 2 // Unassociated alpha.
-		err = encodeRGBA(dst, m.Pix, d.X, d.Y, m.Stride, predictor)
-	case *image.NRGBA64:
-		extraSamples = 2 // Unassociated alpha.
-		bitsPerSample = []uint32{16, 16, 16, 16}
-		err = encodeRGBA64(dst, m.Pix, d.X, d.Y, m.Stride, predictor)
-	case *image.RGBA:
-		extraSamples = 1 // Associated alpha.
-		err = encodeRGBA(dst, m.Pix, d.X, d.Y, m.Stride, predictor)
-	case *image.RGBA64:
-		extraSamples = 1 // Associated alpha.
-		bitsPerSample = []uint32{16, 16, 16, 16}
-		err = encodeRGBA64(dst, m.Pix, d.X, d.Y, m.Stride, predictor)
-	default:
-		extraSamples = 1 // Associated alpha.
-		err = encode(dst, m, predictor)
 	}
-	if err != nil {
+
+	if _, err = w.Write(e.header(ifdOffset).Bytes()); err != nil {
 		return err
 	}
-
-	if compression != TagValue_CompressionType_None {
-		if err = dst.(io.Closer).Close(); err != nil {
-			return err
+	end := int64(e.header(ifdOffset).HeadSize())
+	for i := range counts {
+		if blocks != nil {
+			_, err = w.Write(blocks[i])
+		} else {
+			err = e.writeBlock(w, i)
 		}
-		imageLen = buf.Len()
-		if err = binary.Write(w, enc, uint32(imageLen+8)); err != nil {
+		if err != nil {
 			return err
 		}
-		if _, err = buf.WriteTo(w); err != nil {
+		end += int64(counts[i])
+	}
+	if end < ifdOffset {
+		if _, err = w.Write(make([]byte, ifdOffset-end)); err != nil {
 			return err
 		}
 	}
 
-	ifd := []ifdEntry{
-		{TagType_ImageWidth, DataType_Short, []uint32{uint32(d.X)}},
-		{TagType_ImageLength, DataType_Short, []uint32{uint32(d.Y)}},
-		{TagType_BitsPerSample, DataType_Short, bitsPerSample},
-		{TagType_Compression, DataType_Short, []uint32{uint32(compression)}},
-		{TagType_PhotometricInterpretation, DataType_Short, []uint32{photometricInterpretation}},
-		{TagType_StripOffsets, DataType_Long, []uint32{8}},
-		{TagType_SamplesPerPixel, DataType_Short, []uint32{samplesPerPixel}},
-		{TagType_RowsPerStrip, DataType_Short, []uint32{uint32(d.Y)}},
-		{TagType_StripByteCounts, DataType_Long, []uint32{uint32(imageLen)}},
-		// There is currently no support for storing the image
-		// resolution, so give a bogus value of 72x72 dpi.
-		{TagType_XResolution, DataType_Rational, []uint32{72, 1}},
-		{TagType_YResolution, DataType_Rational, []uint32{72, 1}},
-		{TagType_ResolutionUnit, DataType_Short, []uint32{uint32(TagValue_ResolutionUnitType_PerInch)}},
-	}
-	if pr != uint32(TagValue_PredictorType_None) {
-		ifd = append(ifd, ifdEntry{TagType_Predictor, DataType_Short, []uint32{pr}})
-	}
-	if len(colorMap) != 0 {
-		ifd = append(ifd, ifdEntry{TagType_ColorMap, DataType_Short, colorMap})
-	}
-	if extraSamples > 0 {
-		ifd = append(ifd, ifdEntry{TagType_ExtraSamples, DataType_Short, []uint32{extraSamples}})
-	}
-
-	return writeIFD(w, imageLen+8, ifd)
+	return writeIFD(w, e.header(ifdOffset), ifdOffset, 0, e.ifd(offsets, counts))
 }
@@ -6,9 +6,14 @@ package tiff
 
 import (
 	"bytes"
+	"encoding/binary"
 	"image"
+	"image/color"
+	"image/draw"
 	"io/ioutil"
+	"math/rand"
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -92,6 +97,217 @@ func TestRoundtrip2(t *testing.T) {
 	compare(t, m0, m1)
 }
 
+// TestRoundtripLayout tests that images written in strips and tiles,
+// with and without compression, decode to the same pixel data.
+func TestRoundtripLayout(t *testing.T) {
+	var layoutTests = []*Options{
+		{RowsPerStrip: 1},
+		{RowsPerStrip: 7},
+		{RowsPerStrip: 7, Compression: TagValue_CompressionType_Deflate},
+		{RowsPerStrip: 16, Compression: TagValue_CompressionType_Deflate, Predictor: true},
+		{TileWidth: 16, TileLength: 16},
+		{TileWidth: 32, TileLength: 16, Compression: TagValue_CompressionType_Deflate},
+		{TileWidth: 16, TileLength: 48, Compression: TagValue_CompressionType_Deflate, Predictor: true},
+		{RowsPerStrip: 9, Compression: TagValue_CompressionType_LZW},
+		{TileWidth: 32, TileLength: 32, Compression: TagValue_CompressionType_LZW, Predictor: true},
+	}
+	for _, rt := range roundtripTests {
+		img, err := openImage(rt.filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, opt := range layoutTests {
+			out := new(bytes.Buffer)
+			if err = Encode(out, img, opt); err != nil {
+				t.Fatalf("%s %+v: %v", rt.filename, opt, err)
+			}
+			p, err := OpenReader(bytes.NewReader(out.Bytes()))
+			if err != nil {
+				t.Fatalf("%s %+v: %v", rt.filename, opt, err)
+			}
+			_, isTiled := p.Ifd[0][0].TagGetter().GetTileWidth()
+			if isTiled != (opt.TileWidth != 0) {
+				t.Fatalf("%s %+v: tiled = %v", rt.filename, opt, isTiled)
+			}
+			b := img.Bounds()
+			blockWidth, blockHeight := b.Dx(), opt.RowsPerStrip
+			if isTiled {
+				blockWidth, blockHeight = opt.TileWidth, opt.TileLength
+			}
+			wantAcross := (b.Dx() + blockWidth - 1) / blockWidth
+			wantDown := (b.Dy() + blockHeight - 1) / blockHeight
+			if n := p.ImageBlocksAcross(0, 0); n != wantAcross {
+				t.Fatalf("%s %+v: blocks across = %d, want %d", rt.filename, opt, n, wantAcross)
+			}
+			if n := p.ImageBlocksDown(0, 0); n != wantDown {
+				t.Fatalf("%s %+v: blocks down = %d, want %d", rt.filename, opt, n, wantDown)
+			}
+			img2, err := p.DecodeImage(0, 0)
+			if err != nil {
+				t.Fatalf("%s %+v: %v", rt.filename, opt, err)
+			}
+			p.Close()
+			compare(t, img, img2)
+		}
+	}
+}
+
+func TestRoundtripBigTiff(t *testing.T) {
+	for _, opt := range []*Options{
+		{BigTiff: true},
+		{BigTiff: true, RowsPerStrip: 10, Compression: TagValue_CompressionType_Deflate},
+		{BigTiff: true, TileWidth: 32, TileLength: 32},
+	} {
+		img, err := openImage("video-001.tiff")
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := new(bytes.Buffer)
+		if err = Encode(out, img, opt); err != nil {
+			t.Fatalf("%+v: %v", opt, err)
+		}
+		if !bytes.HasPrefix(out.Bytes(), []byte(BigTiffLittleEnding)) {
+			t.Fatalf("%+v: bad header %q", opt, out.Bytes()[:4])
+		}
+		p, err := OpenReader(bytes.NewReader(out.Bytes()))
+		if err != nil {
+			t.Fatalf("%+v: %v", opt, err)
+		}
+		if !p.Header.IsBigTiff() {
+			t.Fatalf("%+v: not a BigTIFF header: %v", opt, p.Header)
+		}
+		img2, err := p.DecodeImage(0, 0)
+		if err != nil {
+			t.Fatalf("%+v: %v", opt, err)
+		}
+		p.Close()
+		compare(t, img, img2)
+	}
+}
+
+func TestRoundtripBigEndian(t *testing.T) {
+	for _, opt := range []*Options{
+		{ByteOrder: binary.BigEndian},
+		{ByteOrder: binary.BigEndian, RowsPerStrip: 10, Compression: TagValue_CompressionType_Deflate, Predictor: true},
+		{ByteOrder: binary.BigEndian, TileWidth: 32, TileLength: 32, BigTiff: true},
+	} {
+		magic := ClassicTiffBigEnding
+		if opt.BigTiff {
+			magic = BigTiffBigEnding
+		}
+		for _, rt := range roundtripTests {
+			img, err := openImage(rt.filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out := new(bytes.Buffer)
+			if err = Encode(out, img, opt); err != nil {
+				t.Fatalf("%s %+v: %v", rt.filename, opt, err)
+			}
+			if !bytes.HasPrefix(out.Bytes(), []byte(magic)) {
+				t.Fatalf("%s %+v: bad header %q", rt.filename, opt, out.Bytes()[:4])
+			}
+			img2, err := Decode(bytes.NewReader(out.Bytes()))
+			if err != nil {
+				t.Fatalf("%s %+v: %v", rt.filename, opt, err)
+			}
+			compare(t, img, img2)
+		}
+	}
+}
+
+// TestRoundtripNative tests that opaque, bilevel and small paletted
+// images are written with their native sample layout.
+func TestRoundtripNative(t *testing.T) {
+	r := image.Rect(0, 0, 13, 5)
+	rgba := image.NewRGBA(r)
+	rgba64 := image.NewRGBA64(r)
+	nrgba64 := image.NewNRGBA64(r)
+	gray := image.NewGray(r)
+	for i := range rgba.Pix {
+		rgba.Pix[i] = uint8(i * 7)
+		if i%4 == 3 {
+			rgba.Pix[i] = 0xff
+		}
+	}
+	for i := range rgba64.Pix {
+		rgba64.Pix[i] = uint8(i * 11)
+		nrgba64.Pix[i] = uint8(i * 13)
+		if i%8 >= 6 {
+			rgba64.Pix[i] = 0xff
+			nrgba64.Pix[i] = 0xff
+		}
+	}
+	for i := range gray.Pix {
+		if i%3 == 0 {
+			gray.Pix[i] = 0xff
+		}
+	}
+	paletted := func(n int) *image.Paletted {
+		p := make(color.Palette, n)
+		for i := range p {
+			p[i] = color.RGBA{uint8(i * 15), uint8(255 - i*15), uint8(i), 0xff}
+		}
+		m := image.NewPaletted(r, p)
+		for i := range m.Pix {
+			m.Pix[i] = uint8(i % n)
+		}
+		return m
+	}
+
+	for _, tt := range []struct {
+		m    image.Image
+		bits []int64
+	}{
+		{rgba, []int64{8, 8, 8}},
+		{rgba64, []int64{16, 16, 16}},
+		{nrgba64, []int64{16, 16, 16}},
+		{gray, []int64{1}},
+		{paletted(2), []int64{1}},
+		{paletted(3), []int64{2}},
+		{paletted(16), []int64{4}},
+		{paletted(17), []int64{8}},
+	} {
+		for _, opt := range []*Options{
+			nil,
+			{TileWidth: 16, TileLength: 16, Compression: TagValue_CompressionType_Deflate},
+			{RowsPerStrip: 2, Predictor: true, ByteOrder: binary.BigEndian},
+		} {
+			out := new(bytes.Buffer)
+			if err := Encode(out, tt.m, opt); err != nil {
+				t.Fatalf("%T %v: %v", tt.m, tt.bits, err)
+			}
+			p, err := OpenReader(bytes.NewReader(out.Bytes()))
+			if err != nil {
+				t.Fatalf("%T %v: %v", tt.m, tt.bits, err)
+			}
+			bits, _ := p.Ifd[0][0].TagGetter().GetBitsPerSample()
+			if !reflect.DeepEqual(bits, tt.bits) {
+				t.Fatalf("%T: bits per sample = %v, want %v", tt.m, bits, tt.bits)
+			}
+			m, err := p.DecodeImage(0, 0)
+			if err != nil {
+				t.Fatalf("%T %v: %v", tt.m, tt.bits, err)
+			}
+			p.Close()
+			compare(t, tt.m, m)
+		}
+	}
+}
+
+func TestEncodeBadTileSize(t *testing.T) {
+	m := image.NewGray(image.Rect(0, 0, 20, 20))
+	for _, opt := range []*Options{
+		{TileWidth: 15, TileLength: 16},
+		{TileWidth: 16, TileLength: 0},
+		{TileWidth: -16, TileLength: 16},
+	} {
+		if err := Encode(ioutil.Discard, m, opt); err == nil {
+			t.Fatalf("%+v: got nil error, want non-nil", opt)
+		}
+	}
+}
+
 func benchmarkEncode(b *testing.B, name string, pixelSize int) {
 	img, err := openImage(name)
 	if err != nil {
@@ -111,3 +327,180 @@ func BenchmarkEncodeGray(b *testing.B)     { benchmarkEncode(b, "video-001-gray.
 func BenchmarkEncodeGray16(b *testing.B)   { benchmarkEncode(b, "video-001-gray-16bit.tiff", 2) }
 func BenchmarkEncodeRGBA(b *testing.B)     { benchmarkEncode(b, "video-001.tiff", 4) }
 func BenchmarkEncodeRGBA64(b *testing.B)   { benchmarkEncode(b, "video-001-16bit.tiff", 8) }
+
+func TestLZWEncode(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	long := make([]byte, 1<<20)
+	for i := range long {
+		// Runs and noise, so that the table is cleared several times.
+		long[i] = uint8(i / 300)
+		if rnd.Intn(4) == 0 {
+			long[i] = uint8(rnd.Intn(256))
+		}
+	}
+	for _, data := range [][]byte{nil, {7}, []byte("TOBEORNOTTOBEORTOBEORNOT"), bytes.Repeat([]byte{0}, 100000), long} {
+		got, err := TagValue_CompressionType_LZW.Decode(bytes.NewReader(lzwEncode(data)), 0, 0)
+		if err != nil {
+			t.Fatalf("%d bytes: %v", len(data), err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("%d bytes: roundtrip differs", len(data))
+		}
+	}
+
+	// The strips of a file written by libtiff are compressed to the same bytes.
+	data, err := ioutil.ReadFile("testdata/blue-purple-pink.lzwcompressed.tiff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ifd := p.Ifd[0][0]
+	for row := 0; row < ifd.BlocksDown(); row++ {
+		block, err := ifd.readBlock(p.rs, 0, row)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, err := TagValue_CompressionType_LZW.Decode(bytes.NewReader(block), 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(lzwEncode(raw), block) {
+			t.Errorf("strip %d differs from libtiff", row)
+		}
+	}
+}
+
+// TestEncodeWorkers tests that blocks compressed concurrently are written
+// as by a single worker.
+func TestEncodeWorkers(t *testing.T) {
+	m := image.NewRGBA(image.Rect(0, 0, 300, 200))
+	for i := range m.Pix {
+		m.Pix[i] = uint8(i * 7 / 5)
+	}
+	for _, opt := range []Options{
+		{Compression: TagValue_CompressionType_Deflate, TileWidth: 32, TileLength: 32},
+		{Compression: TagValue_CompressionType_LZW, Predictor: true, RowsPerStrip: 16},
+		{Compression: TagValue_CompressionType_LZW, TileWidth: 64, TileLength: 16},
+	} {
+		var want []byte
+		for _, workers := range []int{0, 1, 4, -1} {
+			opt.Workers = workers
+			var buf bytes.Buffer
+			if err := Encode(&buf, m, &opt); err != nil {
+				t.Fatalf("%+v: %v", opt, err)
+			}
+			var cog bytes.Buffer
+			if err := EncodeCOG(&cog, m, &opt, nil); err != nil {
+				t.Fatalf("%+v: %v", opt, err)
+			}
+			got := append(buf.Bytes(), cog.Bytes()...)
+			if want == nil {
+				want = got
+			} else if !bytes.Equal(got, want) {
+				t.Errorf("%+v: output differs", opt)
+			}
+		}
+	}
+}
+
+// TestScanlineWriter tests that images written row by row are those
+// written by Encode.
+func TestScanlineWriter(t *testing.T) {
+	r := image.Rect(0, 0, 70, 45)
+	gray16 := image.NewGray16(r)
+	rgba := image.NewRGBA(r)
+	nrgba := image.NewNRGBA(r)
+	paletted := image.NewPaletted(r, color.Palette{color.Black, color.White, color.Gray{0x80}})
+	for i := range rgba.Pix {
+		gray16.Pix[i/2] = uint8(i * 3)
+		rgba.Pix[i] = uint8(i * 7 / 3)
+		if i%4 == 3 {
+			rgba.Pix[i] = 0xff
+		}
+		nrgba.Pix[i] = uint8(i)
+		paletted.Pix[i/4] = uint8(i % 3)
+	}
+	for _, m := range []image.Image{gray16, rgba, nrgba, paletted} {
+		for _, opt := range []*Options{
+			nil,
+			{Compression: TagValue_CompressionType_LZW, Predictor: true, RowsPerStrip: 8},
+			{Compression: TagValue_CompressionType_Deflate, TileWidth: 32, TileLength: 16, Workers: 4},
+			{BigTiff: true, RowsPerStrip: 10, ByteOrder: binary.BigEndian},
+		} {
+			var want bytes.Buffer
+			if err := Encode(&want, m, opt); err != nil {
+				t.Fatal(err)
+			}
+			var got bytes.Buffer
+			ws, _ := NewSeekWriter(&got, 0)
+			s, err := NewScanlineWriter(ws, image.Config{ColorModel: m.ColorModel(), Width: 70, Height: 45}, opt)
+			if err != nil {
+				t.Fatalf("%T, %+v: %v", m, opt, err)
+			}
+			for y := 0; y < 45; y += 7 {
+				band := m.(interface {
+					SubImage(image.Rectangle) image.Image
+				}).SubImage(image.Rect(0, y, 70, y+7))
+				if err := s.WriteRows(band); err != nil {
+					t.Fatalf("%T, %+v: %v", m, opt, err)
+				}
+			}
+			if err := s.Close(); err != nil {
+				t.Fatalf("%T, %+v: %v", m, opt, err)
+			}
+			ws.Close()
+			if !bytes.Equal(got.Bytes(), want.Bytes()) {
+				t.Errorf("%T, %+v: output differs from Encode", m, opt)
+			}
+		}
+	}
+
+	// Rows of another type are converted.
+	var buf bytes.Buffer
+	ws, _ := NewSeekWriter(&buf, 0)
+	s, err := NewScanlineWriter(ws, image.Config{ColorModel: color.RGBAModel, Width: 70, Height: 45}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.WriteRows(image.NewGray(image.Rect(0, 0, 71, 1))); err == nil {
+		t.Errorf("bad width, no error")
+	}
+	if err := s.WriteRows(nrgba); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.WriteRows(image.NewGray(image.Rect(0, 0, 70, 1))); err == nil {
+		t.Errorf("too many rows, no error")
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	ws.Close()
+	p, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := p.DecodeImage(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := image.NewRGBA(r)
+	draw.Draw(want, r, nrgba, image.Point{}, draw.Src)
+	for i := 3; i < len(want.Pix); i += 4 {
+		want.Pix[i] = 0xff
+	}
+	if !imageEqual(got, want) {
+		t.Errorf("converted image differs")
+	}
+
+	ws, _ = NewSeekWriter(ioutil.Discard, 0)
+	s, _ = NewScanlineWriter(ws, image.Config{ColorModel: color.GrayModel, Width: 10, Height: 10}, nil)
+	if err := s.Close(); err == nil {
+		t.Errorf("missing rows, no error")
+	}
+	if _, err := NewScanlineWriter(ws, image.Config{ColorModel: color.CMYKModel, Width: 10, Height: 10}, nil); err == nil {
+		t.Errorf("CMYK, no error")
+	}
+}
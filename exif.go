@@ -0,0 +1,540 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// An ExifIFD is the private IFD of EXIF 2.3 tags that the ExifIFD tag of
+// an image points to. Its tags live in their own namespace, so its
+// entries are keyed by ExifIFD_TagType.
+type ExifIFD struct {
+	Header   *Header
+	EntryMap map[ExifIFD_TagType]*IFDEntry
+	ThisIFD  int64
+
+	// Interoperability is the IFD that the InteroperabilityIFD tag of
+	// the EXIF IFD points to, or nil.
+	Interoperability *InteroperabilityIFD
+}
+
+// A GPSIFD is the private IFD of GPS tags that the GPSIFD tag of an image
+// points to. Its entries are keyed by GPSIFD_TagType.
+type GPSIFD struct {
+	Header   *Header
+	EntryMap map[GPSIFD_TagType]*IFDEntry
+	ThisIFD  int64
+}
+
+// An InteroperabilityIFD is the private IFD of EXIF interoperability
+// tags. Its entries are keyed by InteroperabilityIFD_TagType.
+type InteroperabilityIFD struct {
+	Header   *Header
+	EntryMap map[InteroperabilityIFD_TagType]*IFDEntry
+	ThisIFD  int64
+}
+
+// readExifIFD reads the EXIF IFD at offset and the interoperability IFD
+// it points to, which is skipped if it cannot be read.
+func readExifIFD(r io.ReadSeeker, h *Header, offset int64, opt *DecodeOptions) (p *ExifIFD, err error) {
+	ifd, err := readIFDLimited(r, h, offset, opt)
+	if err != nil || ifd == nil {
+		return
+	}
+	p = &ExifIFD{
+		Header:   h,
+		EntryMap: make(map[ExifIFD_TagType]*IFDEntry, len(ifd.EntryMap)),
+		ThisIFD:  ifd.ThisIFD,
+	}
+	for tag, entry := range ifd.EntryMap {
+		p.EntryMap[ExifIFD_TagType(tag)] = entry
+	}
+	if v, ok := entryInt(p.EntryMap[ExifIFD_TagType_InteroperabilityIFD]); ok && v != 0 {
+		if ifd, _ = readIFDLimited(r, h, v, opt); ifd == nil {
+			return
+		}
+		p.Interoperability = &InteroperabilityIFD{
+			Header:   h,
+			EntryMap: make(map[InteroperabilityIFD_TagType]*IFDEntry, len(ifd.EntryMap)),
+			ThisIFD:  ifd.ThisIFD,
+		}
+		for tag, entry := range ifd.EntryMap {
+			p.Interoperability.EntryMap[InteroperabilityIFD_TagType(tag)] = entry
+		}
+	}
+	return
+}
+
+func readGPSIFD(r io.ReadSeeker, h *Header, offset int64, opt *DecodeOptions) (p *GPSIFD, err error) {
+	ifd, err := readIFDLimited(r, h, offset, opt)
+	if err != nil || ifd == nil {
+		return
+	}
+	p = &GPSIFD{
+		Header:   h,
+		EntryMap: make(map[GPSIFD_TagType]*IFDEntry, len(ifd.EntryMap)),
+		ThisIFD:  ifd.ThisIFD,
+	}
+	for tag, entry := range ifd.EntryMap {
+		p.EntryMap[GPSIFD_TagType(tag)] = entry
+	}
+	return
+}
+
+// readPrivateIFDs reads the EXIF and GPS IFDs of p, and returns the number
+// of IFDs read, interoperability IFD included. Like broken SubIFDs, broken
+// private IFDs are skipped rather than failing the whole file.
+func (p *IFD) readPrivateIFDs(r io.ReadSeeker) (n int) {
+	tags := p.TagGetter()
+	if v, ok := tags.GetExifIFD(); ok && len(v) > 0 && v[0] != 0 {
+		if exif, err := readExifIFD(r, p.Header, v[0], p.opt); err == nil && exif != nil {
+			p.Exif = exif
+			if n++; exif.Interoperability != nil {
+				n++
+			}
+		}
+	}
+	if v, ok := tags.GetGPSIFD(); ok && len(v) > 0 && v[0] != 0 {
+		if gps, err := readGPSIFD(r, p.Header, v[0], p.opt); err == nil && gps != nil {
+			p.GPS = gps
+			n++
+		}
+	}
+	return
+}
+
+// entryInt returns the first value of an integer entry. It accepts a nil
+// entry, so that missing tags can be looked up directly in an EntryMap.
+func entryInt(e *IFDEntry) (v int64, ok bool) {
+	if e == nil {
+		return
+	}
+	if ints := e.GetInts(); len(ints) > 0 {
+		return ints[0], true
+	}
+	return
+}
+
+// entryFloats returns the values of a rational, floating point or integer
+// entry. ok is false if a rational has a zero denominator.
+func entryFloats(e *IFDEntry) (v []float64, ok bool) {
+	if e == nil {
+		return
+	}
+	for _, r := range e.GetRationals() {
+		if r[1] == 0 {
+			return nil, false
+		}
+	}
+	v = e.GetFloats()
+	return v, len(v) > 0
+}
+
+func entryFloat(e *IFDEntry) (v float64, ok bool) {
+	vs, ok := entryFloats(e)
+	if !ok {
+		return
+	}
+	return vs[0], true
+}
+
+// entryString returns the value of an ASCII or UNDEFINED entry, without
+// trailing NULs and spaces.
+func entryString(e *IFDEntry) (v string, ok bool) {
+	if e == nil {
+		return
+	}
+	switch e.DataType {
+	case DataType_ASCII:
+		v = e.GetString()
+	case DataType_Undefined, DataType_Byte:
+		v = string(e.Data)
+	default:
+		return
+	}
+	return strings.TrimRight(v, "\x00 "), true
+}
+
+// entryComment returns the value of an EXIF comment entry, whose first
+// 8 bytes name its character code. Only ASCII and UTF-8 text and text
+// with an undefined code are supported.
+func entryComment(e *IFDEntry) (v string, ok bool) {
+	if e == nil || len(e.Data) < 8 {
+		return
+	}
+	code, text := e.Data[:8], e.Data[8:]
+	switch {
+	case bytes.Equal(code, []byte("ASCII\x00\x00\x00")),
+		bytes.Equal(code, []byte("UTF-8\x00\x00\x00")),
+		bytes.Equal(code, make([]byte, 8)):
+		return strings.TrimRight(string(text), "\x00 "), true
+	}
+	return
+}
+
+// exifTime parses an EXIF date and time, "YYYY:MM:DD HH:MM:SS", and its
+// fraction of a second. EXIF times carry no time zone, so the result is
+// in UTC. Unknown times, written with blanks or zeros, are not ok.
+func exifTime(s, subsec string) (t time.Time, ok bool) {
+	var year, month, day, hour, min, sec int
+	if _, err := fmt.Sscanf(s, "%d:%d:%d %d:%d:%d",
+		&year, &month, &day,
+		&hour, &min, &sec,
+	); err != nil || year == 0 || month == 0 || day == 0 {
+		return
+	}
+	var nsec int
+	subsec = strings.TrimSpace(subsec)
+	for i := 0; i < 9; i++ {
+		nsec *= 10
+		if i < len(subsec) && subsec[i] >= '0' && subsec[i] <= '9' {
+			nsec += int(subsec[i] - '0')
+		}
+	}
+	return time.Date(year, time.Month(month), day, hour, min, sec, nsec, time.UTC), true
+}
+
+// Entry returns the entry of the tag.
+func (p *ExifIFD) Entry(tag ExifIFD_TagType) (entry *IFDEntry, ok bool) {
+	entry, ok = p.EntryMap[tag]
+	return
+}
+
+// ExifVersion returns the version of the EXIF standard, such as "0230".
+func (p *ExifIFD) ExifVersion() (string, bool) {
+	return entryString(p.EntryMap[ExifIFD_TagType_ExifVersion])
+}
+
+// ExposureTime returns the exposure time in seconds.
+func (p *ExifIFD) ExposureTime() (float64, bool) {
+	return entryFloat(p.EntryMap[ExifIFD_TagType_ExposureTime])
+}
+
+// FNumber returns the F number of the lens.
+func (p *ExifIFD) FNumber() (float64, bool) {
+	return entryFloat(p.EntryMap[ExifIFD_TagType_FNumber])
+}
+
+func (p *ExifIFD) ExposureProgram() (int64, bool) {
+	return entryInt(p.EntryMap[ExifIFD_TagType_ExposureProgram])
+}
+
+// ISOSpeedRatings returns the ISO speed, or another sensitivity value as
+// given by the SensitivityType tag.
+func (p *ExifIFD) ISOSpeedRatings() (int64, bool) {
+	return entryInt(p.EntryMap[ExifIFD_TagType_ISOSpeedRatings])
+}
+
+func (p *ExifIFD) SensitivityType() (int64, bool) {
+	return entryInt(p.EntryMap[ExifIFD_TagType_SensitivityType])
+}
+
+// DateTimeOriginal returns the time when the image was taken, including
+// the fraction of a second of SubsecTimeOriginal. EXIF times carry no
+// time zone, so the result is in UTC.
+func (p *ExifIFD) DateTimeOriginal() (time.Time, bool) {
+	s, _ := entryString(p.EntryMap[ExifIFD_TagType_DateTimeOriginal])
+	subsec, _ := entryString(p.EntryMap[ExifIFD_TagType_SubsecTimeOriginal])
+	return exifTime(s, subsec)
+}
+
+// DateTimeDigitized returns the time when the image was stored as digital
+// data, including the fraction of a second of SubsecTimeDigitized.
+func (p *ExifIFD) DateTimeDigitized() (time.Time, bool) {
+	s, _ := entryString(p.EntryMap[ExifIFD_TagType_DateTimeDigitized])
+	subsec, _ := entryString(p.EntryMap[ExifIFD_TagType_SubsecTimeDigitized])
+	return exifTime(s, subsec)
+}
+
+// ShutterSpeedValue returns the shutter speed in APEX units.
+func (p *ExifIFD) ShutterSpeedValue() (float64, bool) {
+	return entryFloat(p.EntryMap[ExifIFD_TagType_ShutterSpeedValue])
+}
+
+// ApertureValue returns the lens aperture in APEX units.
+func (p *ExifIFD) ApertureValue() (float64, bool) {
+	return entryFloat(p.EntryMap[ExifIFD_TagType_ApertureValue])
+}
+
+// BrightnessValue returns the brightness in APEX units.
+func (p *ExifIFD) BrightnessValue() (float64, bool) {
+	return entryFloat(p.EntryMap[ExifIFD_TagType_BrightnessValue])
+}
+
+// ExposureBiasValue returns the exposure bias in APEX units.
+func (p *ExifIFD) ExposureBiasValue() (float64, bool) {
+	return entryFloat(p.EntryMap[ExifIFD_TagType_ExposureBiasValue])
+}
+
+// MaxApertureValue returns the smallest F number of the lens in APEX units.
+func (p *ExifIFD) MaxApertureValue() (float64, bool) {
+	return entryFloat(p.EntryMap[ExifIFD_TagType_MaxApertureValue])
+}
+
+// SubjectDistance returns the distance to the subject in meters.
+func (p *ExifIFD) SubjectDistance() (float64, bool) {
+	return entryFloat(p.EntryMap[ExifIFD_TagType_SubjectDistance])
+}
+
+func (p *ExifIFD) MeteringMode() (int64, bool) {
+	return entryInt(p.EntryMap[ExifIFD_TagType_MeteringMode])
+}
+
+func (p *ExifIFD) LightSource() (int64, bool) {
+	return entryInt(p.EntryMap[ExifIFD_TagType_LightSource])
+}
+
+// Flash returns the flash status bits; bit 0 is set if the flash fired.
+func (p *ExifIFD) Flash() (int64, bool) {
+	return entryInt(p.EntryMap[ExifIFD_TagType_Flash])
+}
+
+// FocalLength returns the actual focal length of the lens in mm.
+func (p *ExifIFD) FocalLength() (float64, bool) {
+	return entryFloat(p.EntryMap[ExifIFD_TagType_FocalLength])
+}
+
+// FocalLengthIn35mmFilm returns the equivalent focal length for a 35mm
+// film camera in mm.
+func (p *ExifIFD) FocalLengthIn35mmFilm() (int64, bool) {
+	return entryInt(p.EntryMap[ExifIFD_TagType_FocalLengthIn35mmFilm])
+}
+
+// UserComment returns the user comment if it is ASCII or UTF-8 text.
+func (p *ExifIFD) UserComment() (string, bool) {
+	return entryComment(p.EntryMap[ExifIFD_TagType_UserComment])
+}
+
+func (p *ExifIFD) SpectralSensitivity() (string, bool) {
+	return entryString(p.EntryMap[ExifIFD_TagType_SpectralSensitivity])
+}
+
+// ColorSpace returns 1 for sRGB and 0xFFFF for an uncalibrated space.
+func (p *ExifIFD) ColorSpace() (int64, bool) {
+	return entryInt(p.EntryMap[ExifIFD_TagType_ColorSpace])
+}
+
+// PixelDimension returns the valid size of a compressed image.
+func (p *ExifIFD) PixelDimension() (width, height int64, ok bool) {
+	if width, ok = entryInt(p.EntryMap[ExifIFD_TagType_PixelXDimension]); !ok {
+		return
+	}
+	height, ok = entryInt(p.EntryMap[ExifIFD_TagType_PixelYDimension])
+	return
+}
+
+func (p *ExifIFD) WhiteBalance() (int64, bool) {
+	return entryInt(p.EntryMap[ExifIFD_TagType_WhiteBalance])
+}
+
+func (p *ExifIFD) DigitalZoomRatio() (float64, bool) {
+	return entryFloat(p.EntryMap[ExifIFD_TagType_DigitalZoomRatio])
+}
+
+func (p *ExifIFD) ImageUniqueID() (string, bool) {
+	return entryString(p.EntryMap[ExifIFD_TagType_ImageUniqueID])
+}
+
+func (p *ExifIFD) CameraOwnerName() (string, bool) {
+	return entryString(p.EntryMap[ExifIFD_TagType_CameraOwnerName])
+}
+
+func (p *ExifIFD) BodySerialNumber() (string, bool) {
+	return entryString(p.EntryMap[ExifIFD_TagType_BodySerialNumber])
+}
+
+// LensSpecification returns the minimum and maximum focal lengths in mm,
+// and the F numbers at those focal lengths.
+func (p *ExifIFD) LensSpecification() (v []float64, ok bool) {
+	if v, ok = entryFloats(p.EntryMap[ExifIFD_TagType_LensSpecification]); !ok || len(v) != 4 {
+		return nil, false
+	}
+	return
+}
+
+func (p *ExifIFD) LensMake() (string, bool) {
+	return entryString(p.EntryMap[ExifIFD_TagType_LensMake])
+}
+
+func (p *ExifIFD) LensModel() (string, bool) {
+	return entryString(p.EntryMap[ExifIFD_TagType_LensModel])
+}
+
+func (p *ExifIFD) LensSerialNumber() (string, bool) {
+	return entryString(p.EntryMap[ExifIFD_TagType_LensSerialNumber])
+}
+
+func (p *ExifIFD) Gamma() (float64, bool) {
+	return entryFloat(p.EntryMap[ExifIFD_TagType_Gamma])
+}
+
+// Entry returns the entry of the tag.
+func (p *GPSIFD) Entry(tag GPSIFD_TagType) (entry *IFDEntry, ok bool) {
+	entry, ok = p.EntryMap[tag]
+	return
+}
+
+// VersionID returns the version of the GPS IFD, such as "2.3.0.0".
+func (p *GPSIFD) VersionID() (string, bool) {
+	e := p.EntryMap[GPSIFD_TagType_GPSVersionID]
+	if e == nil {
+		return "", false
+	}
+	v := e.GetInts()
+	if len(v) == 0 {
+		return "", false
+	}
+	s := make([]string, len(v))
+	for i := range v {
+		s[i] = fmt.Sprint(v[i])
+	}
+	return strings.Join(s, "."), true
+}
+
+// degrees returns the decimal degrees of a latitude or longitude given as
+// degrees, minutes and seconds, negated if ref is neg.
+func (p *GPSIFD) degrees(tag, refTag GPSIFD_TagType, neg string) (v float64, ok bool) {
+	dms, ok := entryFloats(p.EntryMap[tag])
+	if !ok {
+		return
+	}
+	for i, f := range []float64{1, 60, 3600} {
+		if i < len(dms) {
+			v += dms[i] / f
+		}
+	}
+	if ref, _ := entryString(p.EntryMap[refTag]); strings.EqualFold(ref, neg) {
+		v = -v
+	}
+	return v, true
+}
+
+// Latitude returns the latitude in decimal degrees, negative south of
+// the equator.
+func (p *GPSIFD) Latitude() (float64, bool) {
+	return p.degrees(GPSIFD_TagType_GPSLatitude, GPSIFD_TagType_GPSLatitudeRef, "S")
+}
+
+// Longitude returns the longitude in decimal degrees, negative west of
+// the prime meridian.
+func (p *GPSIFD) Longitude() (float64, bool) {
+	return p.degrees(GPSIFD_TagType_GPSLongitude, GPSIFD_TagType_GPSLongitudeRef, "W")
+}
+
+// DestLatitude returns the latitude of the destination point in decimal
+// degrees.
+func (p *GPSIFD) DestLatitude() (float64, bool) {
+	return p.degrees(GPSIFD_TagType_GPSDestLatitude, GPSIFD_TagType_GPSDestLatitudeRef, "S")
+}
+
+// DestLongitude returns the longitude of the destination point in decimal
+// degrees.
+func (p *GPSIFD) DestLongitude() (float64, bool) {
+	return p.degrees(GPSIFD_TagType_GPSDestLongitude, GPSIFD_TagType_GPSDestLongitudeRef, "W")
+}
+
+// Altitude returns the altitude in meters, negative below sea level.
+func (p *GPSIFD) Altitude() (v float64, ok bool) {
+	if v, ok = entryFloat(p.EntryMap[GPSIFD_TagType_GPSAltitude]); !ok {
+		return
+	}
+	if ref, _ := entryInt(p.EntryMap[GPSIFD_TagType_GPSAltitudeRef]); ref == 1 {
+		v = -v
+	}
+	return
+}
+
+// TimeStamp returns the UTC time of the GPSDateStamp and GPSTimeStamp tags.
+func (p *GPSIFD) TimeStamp() (t time.Time, ok bool) {
+	date, ok := entryString(p.EntryMap[GPSIFD_TagType_GPSDateStamp])
+	if !ok {
+		return
+	}
+	hms, ok := entryFloats(p.EntryMap[GPSIFD_TagType_GPSTimeStamp])
+	if !ok || len(hms) != 3 {
+		return t, false
+	}
+	var year, month, day int
+	if _, err := fmt.Sscanf(date, "%d:%d:%d", &year, &month, &day); err != nil || year == 0 {
+		return t, false
+	}
+	sec, frac := math.Modf(hms[2])
+	t = time.Date(year, time.Month(month), day, int(hms[0]), int(hms[1]), int(sec), int(math.Round(frac*1e9)), time.UTC)
+	return t, true
+}
+
+// Speed returns the speed of the GPS receiver in km/h.
+func (p *GPSIFD) Speed() (v float64, ok bool) {
+	if v, ok = entryFloat(p.EntryMap[GPSIFD_TagType_GPSSpeed]); !ok {
+		return
+	}
+	switch ref, _ := entryString(p.EntryMap[GPSIFD_TagType_GPSSpeedRef]); ref {
+	case "M":
+		v *= 1.609344
+	case "N":
+		v *= 1.852
+	}
+	return
+}
+
+// Track returns the direction of movement of the GPS receiver in degrees,
+// and whether it is relative to true ("T") or magnetic ("M") north.
+func (p *GPSIFD) Track() (v float64, ref string, ok bool) {
+	if v, ok = entryFloat(p.EntryMap[GPSIFD_TagType_GPSTrack]); !ok {
+		return
+	}
+	ref, _ = entryString(p.EntryMap[GPSIFD_TagType_GPSTrackRef])
+	return
+}
+
+// ImgDirection returns the direction of the image in degrees, and whether
+// it is relative to true ("T") or magnetic ("M") north.
+func (p *GPSIFD) ImgDirection() (v float64, ref string, ok bool) {
+	if v, ok = entryFloat(p.EntryMap[GPSIFD_TagType_GPSImgDirection]); !ok {
+		return
+	}
+	ref, _ = entryString(p.EntryMap[GPSIFD_TagType_GPSImgDirectionRef])
+	return
+}
+
+func (p *GPSIFD) Satellites() (string, bool) {
+	return entryString(p.EntryMap[GPSIFD_TagType_GPSSatellites])
+}
+
+func (p *GPSIFD) MapDatum() (string, bool) {
+	return entryString(p.EntryMap[GPSIFD_TagType_GPSMapDatum])
+}
+
+func (p *GPSIFD) DOP() (float64, bool) {
+	return entryFloat(p.EntryMap[GPSIFD_TagType_GPSDOP])
+}
+
+// ProcessingMethod returns the name of the method used for location
+// finding if it is ASCII or UTF-8 text.
+func (p *GPSIFD) ProcessingMethod() (string, bool) {
+	return entryComment(p.EntryMap[GPSIFD_TagType_GPSProcessingMethod])
+}
+
+// HPositioningError returns the horizontal positioning error in meters.
+func (p *GPSIFD) HPositioningError() (float64, bool) {
+	return entryFloat(p.EntryMap[GPSIFD_TagType_GPSHPositioningError])
+}
+
+// Entry returns the entry of the tag.
+func (p *InteroperabilityIFD) Entry(tag InteroperabilityIFD_TagType) (entry *IFDEntry, ok bool) {
+	entry, ok = p.EntryMap[tag]
+	return
+}
+
+// Index returns the interoperability rule, such as "R98".
+func (p *InteroperabilityIFD) Index() (string, bool) {
+	return entryString(p.EntryMap[InteroperabilityIFD_TagType_InteroperabilityIndex])
+}
@@ -0,0 +1,173 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExifIFD_read(t *testing.T) {
+	p := openGeoTiff(t, "./testdata/gdal_autotest/gcore/data/exif_and_gps.tif")
+	defer p.Close()
+
+	exif := p.ImageExif(0, 0)
+	if exif == nil {
+		t.Fatal("no EXIF IFD")
+	}
+	if exif.ThisIFD != 234 || len(exif.EntryMap) != 3 {
+		t.Errorf("EXIF IFD at %d with %d entries", exif.ThisIFD, len(exif.EntryMap))
+	}
+	if v, ok := exif.ExifVersion(); !ok || v != "0220" {
+		t.Errorf("ExifVersion = %q, %v", v, ok)
+	}
+	if v, ok := exif.SpectralSensitivity(); !ok || v != "EXIF Spectral Sensitivity" {
+		t.Errorf("SpectralSensitivity = %q, %v", v, ok)
+	}
+	if e, ok := exif.Entry(ExifIFD_TagType_ComponentsConfiguration); !ok || !reflect.DeepEqual(e.Data, []byte{1, 2, 3, 0}) {
+		t.Errorf("ComponentsConfiguration = %v, %v", e, ok)
+	}
+	if _, ok := exif.ExposureTime(); ok {
+		t.Errorf("ExposureTime is present")
+	}
+	if exif.Interoperability != nil {
+		t.Errorf("Interoperability IFD is present")
+	}
+
+	gps := p.ImageGPS(0, 0)
+	if gps == nil {
+		t.Fatal("no GPS IFD")
+	}
+	if v, ok := gps.VersionID(); !ok || v != "2.2.0.0" {
+		t.Errorf("VersionID = %q, %v", v, ok)
+	}
+	if v, ok := gps.Latitude(); !ok || math.Abs(v-(-(77+5/60.0+60/3600.0))) > 1e-12 {
+		t.Errorf("Latitude = %v, %v", v, ok)
+	}
+	if v, ok := gps.Longitude(); !ok || math.Abs(v-(34+12/60.0)) > 1e-12 {
+		t.Errorf("Longitude = %v, %v", v, ok)
+	}
+	if _, ok := gps.Altitude(); ok {
+		t.Errorf("Altitude is present")
+	}
+}
+
+func TestExifIFD_getters(t *testing.T) {
+	h := &Header{ByteOrder: binary.BigEndian, TiffType: TiffType_ClassicTIFF}
+	entry := func(dataType DataType, value interface{}) *IFDEntry {
+		e := &IFDEntry{Header: h, DataType: dataType}
+		switch v := value.(type) {
+		case string:
+			if dataType == DataType_ASCII {
+				e.SetString(v)
+			} else {
+				e.SetUndefined([]byte(v))
+			}
+		case []int64:
+			e.SetInts(v...)
+		case [][2]int64:
+			e.SetRationals(v...)
+		}
+		return e
+	}
+
+	exif := &ExifIFD{Header: h, EntryMap: map[ExifIFD_TagType]*IFDEntry{
+		ExifIFD_TagType_ExposureTime:          entry(DataType_Rational, [][2]int64{{1, 250}}),
+		ExifIFD_TagType_FNumber:               entry(DataType_Rational, [][2]int64{{28, 10}}),
+		ExifIFD_TagType_ISOSpeedRatings:       entry(DataType_Short, []int64{400}),
+		ExifIFD_TagType_ExposureBiasValue:     entry(DataType_SRational, [][2]int64{{-2, 3}}),
+		ExifIFD_TagType_FocalLength:           entry(DataType_Rational, [][2]int64{{35, 1}}),
+		ExifIFD_TagType_FocalLengthIn35mmFilm: entry(DataType_Short, []int64{52}),
+		ExifIFD_TagType_SubjectDistance:       entry(DataType_Rational, [][2]int64{{1, 0}}),
+		ExifIFD_TagType_DateTimeOriginal:      entry(DataType_ASCII, "2015:06:01 12:34:56"),
+		ExifIFD_TagType_SubsecTimeOriginal:    entry(DataType_ASCII, "25"),
+		ExifIFD_TagType_DateTimeDigitized:     entry(DataType_ASCII, "    :  :     :  :  "),
+		ExifIFD_TagType_UserComment:           entry(DataType_Undefined, "ASCII\x00\x00\x00hello   "),
+		ExifIFD_TagType_LensSpecification:     entry(DataType_Rational, [][2]int64{{24, 1}, {70, 1}, {28, 10}, {28, 10}}),
+		ExifIFD_TagType_LensModel:             entry(DataType_ASCII, "24-70mm"),
+		ExifIFD_TagType_PixelXDimension:       entry(DataType_Long, []int64{6000}),
+		ExifIFD_TagType_PixelYDimension:       entry(DataType_Short, []int64{4000}),
+	}}
+	if v, ok := exif.ExposureTime(); !ok || v != 1.0/250 {
+		t.Errorf("ExposureTime = %v, %v", v, ok)
+	}
+	if v, ok := exif.FNumber(); !ok || v != 2.8 {
+		t.Errorf("FNumber = %v, %v", v, ok)
+	}
+	if v, ok := exif.ISOSpeedRatings(); !ok || v != 400 {
+		t.Errorf("ISOSpeedRatings = %v, %v", v, ok)
+	}
+	if v, ok := exif.ExposureBiasValue(); !ok || v != -2.0/3 {
+		t.Errorf("ExposureBiasValue = %v, %v", v, ok)
+	}
+	if v, ok := exif.FocalLength(); !ok || v != 35 {
+		t.Errorf("FocalLength = %v, %v", v, ok)
+	}
+	if v, ok := exif.FocalLengthIn35mmFilm(); !ok || v != 52 {
+		t.Errorf("FocalLengthIn35mmFilm = %v, %v", v, ok)
+	}
+	if v, ok := exif.SubjectDistance(); ok {
+		t.Errorf("SubjectDistance with a zero denominator = %v", v)
+	}
+	want := time.Date(2015, 6, 1, 12, 34, 56, 250000000, time.UTC)
+	if v, ok := exif.DateTimeOriginal(); !ok || !v.Equal(want) {
+		t.Errorf("DateTimeOriginal = %v, %v, want %v", v, ok, want)
+	}
+	if v, ok := exif.DateTimeDigitized(); ok {
+		t.Errorf("unknown DateTimeDigitized = %v", v)
+	}
+	if v, ok := exif.UserComment(); !ok || v != "hello" {
+		t.Errorf("UserComment = %q, %v", v, ok)
+	}
+	if v, ok := exif.LensSpecification(); !ok || !reflect.DeepEqual(v, []float64{24, 70, 2.8, 2.8}) {
+		t.Errorf("LensSpecification = %v, %v", v, ok)
+	}
+	if v, ok := exif.LensModel(); !ok || v != "24-70mm" {
+		t.Errorf("LensModel = %q, %v", v, ok)
+	}
+	if w, h, ok := exif.PixelDimension(); !ok || w != 6000 || h != 4000 {
+		t.Errorf("PixelDimension = %d, %d, %v", w, h, ok)
+	}
+
+	gps := &GPSIFD{Header: h, EntryMap: map[GPSIFD_TagType]*IFDEntry{
+		GPSIFD_TagType_GPSLatitudeRef:     entry(DataType_ASCII, "N"),
+		GPSIFD_TagType_GPSLatitude:        entry(DataType_Rational, [][2]int64{{48, 1}, {51, 1}, {2412, 100}}),
+		GPSIFD_TagType_GPSLongitudeRef:    entry(DataType_ASCII, "W"),
+		GPSIFD_TagType_GPSLongitude:       entry(DataType_Rational, [][2]int64{{122, 1}, {30, 1}, {0, 1}}),
+		GPSIFD_TagType_GPSAltitudeRef:     entry(DataType_Byte, []int64{1}),
+		GPSIFD_TagType_GPSAltitude:        entry(DataType_Rational, [][2]int64{{125, 10}}),
+		GPSIFD_TagType_GPSTimeStamp:       entry(DataType_Rational, [][2]int64{{23, 1}, {59, 1}, {595, 10}}),
+		GPSIFD_TagType_GPSDateStamp:       entry(DataType_ASCII, "2015:12:31"),
+		GPSIFD_TagType_GPSSpeedRef:        entry(DataType_ASCII, "N"),
+		GPSIFD_TagType_GPSSpeed:           entry(DataType_Rational, [][2]int64{{10, 1}}),
+		GPSIFD_TagType_GPSImgDirection:    entry(DataType_Rational, [][2]int64{{2705, 10}}),
+		GPSIFD_TagType_GPSImgDirectionRef: entry(DataType_ASCII, "T"),
+	}}
+	if v, ok := gps.Latitude(); !ok || math.Abs(v-(48+51/60.0+24.12/3600)) > 1e-12 {
+		t.Errorf("Latitude = %v, %v", v, ok)
+	}
+	if v, ok := gps.Longitude(); !ok || v != -122.5 {
+		t.Errorf("Longitude = %v, %v", v, ok)
+	}
+	if v, ok := gps.Altitude(); !ok || v != -12.5 {
+		t.Errorf("Altitude = %v, %v", v, ok)
+	}
+	want = time.Date(2015, 12, 31, 23, 59, 59, 500000000, time.UTC)
+	if v, ok := gps.TimeStamp(); !ok || !v.Equal(want) {
+		t.Errorf("TimeStamp = %v, %v, want %v", v, ok, want)
+	}
+	if v, ok := gps.Speed(); !ok || v != 18.52 {
+		t.Errorf("Speed = %v, %v", v, ok)
+	}
+	if v, ref, ok := gps.ImgDirection(); !ok || v != 270.5 || ref != "T" {
+		t.Errorf("ImgDirection = %v, %q, %v", v, ref, ok)
+	}
+	if _, ok := gps.DestLatitude(); ok {
+		t.Errorf("DestLatitude is present")
+	}
+}
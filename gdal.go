@@ -0,0 +1,279 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Roles of the band items that GDAL stores in the GDAL_METADATA tag.
+const (
+	GDALRole_Scale       = "scale"
+	GDALRole_Offset      = "offset"
+	GDALRole_Description = "description"
+	GDALRole_UnitType    = "unittype"
+)
+
+// A GDALMetadataItem is a name=value pair of the GDAL_METADATA tag.
+//
+// Sample is the band the item belongs to, counted from zero, or -1 for an
+// item about the whole dataset. Role marks the band properties that GDAL
+// keeps outside of its metadata domains, such as the scale and offset.
+// Domain is the metadata domain, empty for the default domain.
+type GDALMetadataItem struct {
+	Name   string
+	Value  string
+	Sample int
+	Role   string
+	Domain string
+}
+
+// GDALMetadata is the content of the GDAL_METADATA tag, an XML document
+// with the dataset and band metadata of files written by GDAL.
+type GDALMetadata struct {
+	Items []GDALMetadataItem
+}
+
+type gdalMetadataXML struct {
+	XMLName xml.Name `xml:"GDALMetadata"`
+	Items   []struct {
+		Name   string  `xml:"name,attr"`
+		Sample *string `xml:"sample,attr"`
+		Role   string  `xml:"role,attr"`
+		Domain string  `xml:"domain,attr"`
+		Value  string  `xml:",chardata"`
+	} `xml:"Item"`
+}
+
+// ParseGDALMetadata parses the value of the GDAL_METADATA tag.
+func ParseGDALMetadata(s string) (*GDALMetadata, error) {
+	var doc gdalMetadataXML
+	if err := xml.Unmarshal([]byte(s), &doc); err != nil {
+		return nil, fmt.Errorf("tiff: ParseGDALMetadata, %v", err)
+	}
+	m := &GDALMetadata{}
+	for _, v := range doc.Items {
+		item := GDALMetadataItem{
+			Name:   v.Name,
+			Value:  v.Value,
+			Sample: -1,
+			Role:   v.Role,
+			Domain: v.Domain,
+		}
+		if v.Sample != nil {
+			n, err := strconv.Atoi(*v.Sample)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("tiff: ParseGDALMetadata, bad sample %q of item %q", *v.Sample, v.Name)
+			}
+			item.Sample = n
+		}
+		m.Items = append(m.Items, item)
+	}
+	return m, nil
+}
+
+// String returns m in the XML format written by GDAL.
+func (m *GDALMetadata) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("<GDALMetadata>\n")
+	for _, item := range m.Items {
+		fmt.Fprintf(&buf, "  <Item name=\"%s\"", xmlEscape(item.Name))
+		if item.Sample >= 0 {
+			fmt.Fprintf(&buf, " sample=\"%d\"", item.Sample)
+		}
+		if item.Role != "" {
+			fmt.Fprintf(&buf, " role=\"%s\"", xmlEscape(item.Role))
+		}
+		if item.Domain != "" {
+			fmt.Fprintf(&buf, " domain=\"%s\"", xmlEscape(item.Domain))
+		}
+		fmt.Fprintf(&buf, ">%s</Item>\n", xmlEscape(item.Value))
+	}
+	buf.WriteString("</GDALMetadata>\n")
+	return buf.String()
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// Item returns the value of the item of the default domain with the given
+// name and sample, which is -1 for dataset items.
+func (m *GDALMetadata) Item(name string, sample int) (value string, ok bool) {
+	if i := m.find("", name, sample); i >= 0 {
+		return m.Items[i].Value, true
+	}
+	return "", false
+}
+
+// SetItem sets the value of the item of the default domain with the given
+// name and sample, which is -1 for dataset items.
+func (m *GDALMetadata) SetItem(name string, sample int, value string) {
+	m.set(GDALMetadataItem{Name: name, Value: value, Sample: sample})
+}
+
+// DomainItem returns the value of the item of a metadata domain.
+func (m *GDALMetadata) DomainItem(domain, name string, sample int) (value string, ok bool) {
+	if i := m.find(domain, name, sample); i >= 0 {
+		return m.Items[i].Value, true
+	}
+	return "", false
+}
+
+func (m *GDALMetadata) find(domain, name string, sample int) int {
+	for i, item := range m.Items {
+		if item.Domain == domain && item.Name == name && item.Sample == sample {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *GDALMetadata) set(item GDALMetadataItem) {
+	if i := m.find(item.Domain, item.Name, item.Sample); i >= 0 {
+		m.Items[i] = item
+		return
+	}
+	m.Items = append(m.Items, item)
+}
+
+// role returns the value of the band item with the given role.
+func (m *GDALMetadata) role(role string, sample int) (value string, ok bool) {
+	for _, item := range m.Items {
+		if item.Role == role && item.Sample == sample {
+			return item.Value, true
+		}
+	}
+	return "", false
+}
+
+// setRole sets the band item with the given role, named as GDAL does.
+func (m *GDALMetadata) setRole(role string, sample int, value string) {
+	for i, item := range m.Items {
+		if item.Role == role && item.Sample == sample {
+			m.Items[i].Value = value
+			return
+		}
+	}
+	m.Items = append(m.Items, GDALMetadataItem{
+		Name:   strings.ToUpper(role),
+		Value:  value,
+		Sample: sample,
+		Role:   role,
+	})
+}
+
+func (m *GDALMetadata) roleFloat(role string, sample int) (v float64, ok bool) {
+	s, ok := m.role(role, sample)
+	if !ok {
+		return
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return v, err == nil
+}
+
+// Scale returns the scale of a band: its real values are the stored
+// values times the scale plus the offset.
+func (m *GDALMetadata) Scale(sample int) (float64, bool) {
+	return m.roleFloat(GDALRole_Scale, sample)
+}
+
+// Offset returns the offset of a band. See Scale.
+func (m *GDALMetadata) Offset(sample int) (float64, bool) {
+	return m.roleFloat(GDALRole_Offset, sample)
+}
+
+// Description returns the description of a band.
+func (m *GDALMetadata) Description(sample int) (string, bool) {
+	return m.role(GDALRole_Description, sample)
+}
+
+// UnitType returns the unit of the values of a band, such as "m".
+func (m *GDALMetadata) UnitType(sample int) (string, bool) {
+	return m.role(GDALRole_UnitType, sample)
+}
+
+func (m *GDALMetadata) SetScale(sample int, v float64) {
+	m.setRole(GDALRole_Scale, sample, formatGDALFloat(v))
+}
+
+func (m *GDALMetadata) SetOffset(sample int, v float64) {
+	m.setRole(GDALRole_Offset, sample, formatGDALFloat(v))
+}
+
+func (m *GDALMetadata) SetDescription(sample int, s string) {
+	m.setRole(GDALRole_Description, sample, s)
+}
+
+func (m *GDALMetadata) SetUnitType(sample int, s string) {
+	m.setRole(GDALRole_UnitType, sample, s)
+}
+
+// formatGDALFloat formats v as GDAL does, which spells NaN as "nan".
+func formatGDALFloat(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "nan"
+	case math.IsInf(v, 1):
+		return "inf"
+	case math.IsInf(v, -1):
+		return "-inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// ParseGDALNoData parses the value of the GDAL_NODATA tag.
+func ParseGDALNoData(s string) (v float64, ok bool) {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(s) {
+	case "nan", "-nan":
+		return math.NaN(), true
+	case "inf", "+inf":
+		return math.Inf(1), true
+	case "-inf":
+		return math.Inf(-1), true
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	return v, err == nil
+}
+
+// GDALMetadata returns the parsed GDAL_METADATA tag. It returns nil and
+// no error if the image has no such tag.
+func (p *IFD) GDALMetadata() (*GDALMetadata, error) {
+	s, ok := p.TagGetter().GetGDAL_METADATA()
+	if !ok {
+		return nil, nil
+	}
+	return ParseGDALMetadata(s)
+}
+
+// GDALNoData returns the nodata value of the GDAL_NODATA tag, the value
+// of the pixels that hold no data in every band.
+func (p *IFD) GDALNoData() (v float64, ok bool) {
+	s, ok := p.TagGetter().GetGDAL_NODATA()
+	if !ok {
+		return
+	}
+	return ParseGDALNoData(s)
+}
+
+// gdalEntries returns the IFD entries of the GDAL tags that store the
+// given metadata and nodata value, either of which may be nil.
+func gdalEntries(md *GDALMetadata, nodata *float64) (ifd []ifdEntry) {
+	if md != nil && len(md.Items) > 0 {
+		ifd = append(ifd, ifdEntry{TagType_GDAL_METADATA, DataType_ASCII, asciiData(md.String())})
+	}
+	if nodata != nil {
+		ifd = append(ifd, ifdEntry{TagType_GDAL_NODATA, DataType_ASCII, asciiData(formatGDALFloat(*nodata))})
+	}
+	return
+}
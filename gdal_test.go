@@ -0,0 +1,140 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"image"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestGDALMetadata_read(t *testing.T) {
+	p := openGeoTiff(t, "./testdata/gdal_autotest/alg/data/2by2.tif")
+	defer p.Close()
+	md, err := p.ImageGDALMetadata(0, 0)
+	if err != nil || md == nil {
+		t.Fatalf("metadata = %v, %v", md, err)
+	}
+	for name, want := range map[string]string{
+		"STATISTICS_MAXIMUM": "13.5",
+		"STATISTICS_MEAN":    "8.5",
+		"STATISTICS_MINIMUM": "3.5",
+		"STATISTICS_STDDEV":  "4.7609522856952",
+	} {
+		if v, ok := md.Item(name, 0); !ok || v != want {
+			t.Errorf("%s = %q, %v, want %q", name, v, ok, want)
+		}
+	}
+	if _, ok := md.Item("STATISTICS_MEAN", -1); ok {
+		t.Errorf("STATISTICS_MEAN is a band item")
+	}
+	if v, ok := p.ImageGDALNoData(0, 0); !ok || v != -1.7e308 {
+		t.Errorf("nodata = %v, %v", v, ok)
+	}
+
+	p = openGeoTiff(t, "./testdata/gdal_autotest/gcore/data/rgbsmall_cmyk.tif")
+	defer p.Close()
+	if md, err = p.ImageGDALMetadata(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := md.DomainItem("IMAGE_STRUCTURE", "INTERLEAVE", -1); !ok || v != "PIXEL" {
+		t.Errorf("INTERLEAVE = %q, %v", v, ok)
+	}
+	if _, ok := md.Item("INTERLEAVE", -1); ok {
+		t.Errorf("INTERLEAVE found in the default domain")
+	}
+	if _, ok := p.ImageGDALNoData(0, 0); ok {
+		t.Errorf("rgbsmall_cmyk.tif has a nodata value")
+	}
+}
+
+func TestParseGDALMetadata(t *testing.T) {
+	md, err := ParseGDALMetadata(`<GDALMetadata>
+  <Item name="AREA_OR_POINT">Area</Item>
+  <Item name="OFFSET" sample="1" role="offset">-273.15</Item>
+  <Item name="SCALE" sample="1" role="scale">0.01</Item>
+  <Item name="DESCRIPTION" sample="0" role="description">a &amp; b</Item>
+  <Item name="UNITTYPE" sample="1" role="unittype">C</Item>
+</GDALMetadata>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := md.Item("AREA_OR_POINT", -1); !ok || v != "Area" {
+		t.Errorf("AREA_OR_POINT = %q, %v", v, ok)
+	}
+	if v, ok := md.Scale(1); !ok || v != 0.01 {
+		t.Errorf("scale = %v, %v", v, ok)
+	}
+	if v, ok := md.Offset(1); !ok || v != -273.15 {
+		t.Errorf("offset = %v, %v", v, ok)
+	}
+	if _, ok := md.Scale(0); ok {
+		t.Errorf("band 0 has a scale")
+	}
+	if v, ok := md.Description(0); !ok || v != "a & b" {
+		t.Errorf("description = %q, %v", v, ok)
+	}
+	if v, ok := md.UnitType(1); !ok || v != "C" {
+		t.Errorf("unit type = %q, %v", v, ok)
+	}
+
+	md2, err := ParseGDALMetadata(md.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(md, md2) {
+		t.Errorf("String roundtrip:\n%v\n%v", md, md2)
+	}
+
+	for _, s := range []string{
+		"",
+		"<GDALMetadata><Item>",
+		`<GDALMetadata><Item name="A" sample="x">1</Item></GDALMetadata>`,
+		`<Metadata><Item name="A">1</Item></Metadata>`,
+	} {
+		if _, err := ParseGDALMetadata(s); err == nil {
+			t.Errorf("%q: no error", s)
+		}
+	}
+}
+
+func TestGDALMetadata_encode(t *testing.T) {
+	md := &GDALMetadata{}
+	md.SetItem("AREA_OR_POINT", -1, "Point")
+	md.SetScale(0, 0.5)
+	md.SetOffset(0, 10)
+	md.SetDescription(0, "elevation")
+	md.SetScale(0, 0.25)
+	m := image.NewGray16(image.Rect(0, 0, 16, 16))
+
+	for _, nodata := range []float64{-9999, math.NaN(), math.Inf(-1)} {
+		var out bytes.Buffer
+		opt := &Options{GDALMetadata: md, GDALNoData: &nodata}
+		if err := Encode(&out, m, opt); err != nil {
+			t.Fatal(err)
+		}
+		p, err := OpenReader(bytes.NewReader(out.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := p.ImageGDALMetadata(0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, md) {
+			t.Errorf("metadata = %v, want %v", got, md)
+		}
+		if v, ok := got.Scale(0); !ok || v != 0.25 {
+			t.Errorf("scale = %v, %v", v, ok)
+		}
+		v, ok := p.ImageGDALNoData(0, 0)
+		if !ok || !(v == nodata || math.IsNaN(v) && math.IsNaN(nodata)) {
+			t.Errorf("nodata = %v, %v, want %v", v, ok, nodata)
+		}
+		p.Close()
+	}
+}
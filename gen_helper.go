@@ -372,6 +372,8 @@ func (p *Type) getValueType(typeName string) string {
 		return "[][3]uint16"
 	case "TagType_SMinSampleValue", "TagType_SMaxSampleValue":
 		return "[]float64"
+	case "TagType_BlackLevel", "TagType_DefaultCropOrigin", "TagType_DefaultCropSize", "TagType_AsShotNeutral":
+		return "[]float64"
 	case "TagType_DateTime":
 		return "time.Time"
 	}
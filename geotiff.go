@@ -0,0 +1,371 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// A GeoKey is one key of a GeoKey directory. Depending on DataType, the
+// value is held in Shorts, Doubles or Ascii.
+type GeoKey struct {
+	ID       GeoKeyType
+	DataType DataType // DataType_Short, DataType_Double or DataType_ASCII
+	Shorts   []uint16
+	Doubles  []float64
+	Ascii    string // Without the '|' terminator.
+}
+
+// Count returns the number of values of the key as stored in the directory.
+// The count of an ASCII key includes its '|' terminator.
+func (k *GeoKey) Count() int {
+	switch k.DataType {
+	case DataType_Short:
+		return len(k.Shorts)
+	case DataType_Double:
+		return len(k.Doubles)
+	case DataType_ASCII:
+		return len(k.Ascii) + 1
+	}
+	return 0
+}
+
+func (k *GeoKey) String() string {
+	switch k.DataType {
+	case DataType_Short:
+		return fmt.Sprintf("%v(Short,%d): %v", k.ID, k.Count(), k.Shorts)
+	case DataType_Double:
+		return fmt.Sprintf("%v(Double,%d): %v", k.ID, k.Count(), k.Doubles)
+	case DataType_ASCII:
+		return fmt.Sprintf("%v(Ascii,%d): %q", k.ID, k.Count(), k.Ascii)
+	}
+	return fmt.Sprintf("%v(%v)", k.ID, k.DataType)
+}
+
+// A GeoKeyDirectory is the parsed content of the GeoKeyDirectoryTag, with
+// the values the keys reference in the GeoDoubleParamsTag and
+// GeoAsciiParamsTag resolved.
+//
+// See http://geotiff.maptools.org/spec/geotiff2.4.html
+type GeoKeyDirectory struct {
+	Version       int // KeyDirectoryVersion, always 1.
+	KeyRevision   int
+	MinorRevision int
+	Keys          []GeoKey // In the order of the directory.
+}
+
+// NewGeoKeyDirectory returns an empty directory of GeoKey revision 1.0.
+func NewGeoKeyDirectory() *GeoKeyDirectory {
+	return &GeoKeyDirectory{Version: 1, KeyRevision: 1, MinorRevision: 0}
+}
+
+// NewGeoKeyDirectoryEPSG returns a directory for the coordinate reference
+// system with the given EPSG code, of the given model: geographic or
+// projected. The code has to fit in a SHORT key.
+func NewGeoKeyDirectoryEPSG(model GeoModelType, code int) (*GeoKeyDirectory, error) {
+	if code <= 0 || code > math.MaxUint16 {
+		return nil, fmt.Errorf("tiff: NewGeoKeyDirectoryEPSG, bad code %d", code)
+	}
+	d := NewGeoKeyDirectory()
+	switch model {
+	case GeoModelType_Geographic:
+		d.SetShort(GeoKeyType_GTModelTypeGeoKey, int(GeoModelType_Geographic))
+		d.SetShort(GeoKeyType_GeographicTypeGeoKey, code)
+		d.SetShort(GeoKeyType_GeogAngularUnitsGeoKey, int(GeoUnitsType_Angular_Degree))
+	case GeoModelType_Projected:
+		d.SetShort(GeoKeyType_GTModelTypeGeoKey, int(GeoModelType_Projected))
+		d.SetShort(GeoKeyType_ProjectedCSTypeGeoKey, code)
+	default:
+		return nil, fmt.Errorf("tiff: NewGeoKeyDirectoryEPSG, unsupported model %v", model)
+	}
+	d.SetShort(GeoKeyType_GTRasterTypeGeoKey, int(GeoRasterType_PixelIsArea))
+	return d, nil
+}
+
+// ParseGeoKeyDirectory parses the values of the GeoKeyDirectoryTag, dir,
+// resolving the keys stored in the GeoDoubleParamsTag and GeoAsciiParamsTag
+// from doubles and ascii.
+func ParseGeoKeyDirectory(dir []int64, doubles []float64, ascii string) (d *GeoKeyDirectory, err error) {
+	if len(dir) < 4 {
+		err = fmt.Errorf("tiff: ParseGeoKeyDirectory, short header: %v", dir)
+		return
+	}
+	n := int(dir[3])
+	if n < 0 || len(dir) < 4+4*n {
+		err = fmt.Errorf("tiff: ParseGeoKeyDirectory, %d keys do not fit in %d values", n, len(dir))
+		return
+	}
+	d = &GeoKeyDirectory{
+		Version:       int(dir[0]),
+		KeyRevision:   int(dir[1]),
+		MinorRevision: int(dir[2]),
+		Keys:          make([]GeoKey, 0, n),
+	}
+	for i := 0; i < n; i++ {
+		e := dir[4+4*i : 8+4*i]
+		k := GeoKey{ID: GeoKeyType(e[0])}
+		location, count, offset := TagType(e[1]), int(e[2]), int(e[3])
+		switch location {
+		case 0:
+			k.DataType = DataType_Short
+			k.Shorts = []uint16{uint16(offset)}
+		case TagType_GeoKeyDirectoryTag:
+			if offset < 0 || count < 0 || offset+count > len(dir) {
+				err = fmt.Errorf("tiff: ParseGeoKeyDirectory, %v out of range", k.ID)
+				return
+			}
+			k.DataType = DataType_Short
+			k.Shorts = make([]uint16, count)
+			for j := range k.Shorts {
+				k.Shorts[j] = uint16(dir[offset+j])
+			}
+		case TagType_GeoDoubleParamsTag:
+			if offset < 0 || count < 0 || offset+count > len(doubles) {
+				err = fmt.Errorf("tiff: ParseGeoKeyDirectory, %v out of range", k.ID)
+				return
+			}
+			k.DataType = DataType_Double
+			k.Doubles = append([]float64(nil), doubles[offset:offset+count]...)
+		case TagType_GeoAsciiParamsTag:
+			if offset < 0 || count < 0 || offset > len(ascii) {
+				err = fmt.Errorf("tiff: ParseGeoKeyDirectory, %v out of range", k.ID)
+				return
+			}
+			// Some writers do not count the terminator.
+			end := minInt(offset+count, len(ascii))
+			k.DataType = DataType_ASCII
+			k.Ascii = strings.TrimSuffix(ascii[offset:end], "|")
+		default:
+			err = fmt.Errorf("tiff: ParseGeoKeyDirectory, %v stored in unknown tag %d", k.ID, location)
+			return
+		}
+		d.Keys = append(d.Keys, k)
+	}
+	return
+}
+
+// Values returns the values of the GeoKeyDirectoryTag, GeoDoubleParamsTag
+// and GeoAsciiParamsTag that store d. The keys are written in ascending
+// order of their IDs as the specification requires.
+func (d *GeoKeyDirectory) Values() (dir []int64, doubles []float64, ascii string) {
+	keys := append([]GeoKey(nil), d.Keys...)
+	sort.SliceStable(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+
+	dir = []int64{int64(d.Version), int64(d.KeyRevision), int64(d.MinorRevision), int64(len(keys))}
+	var shorts []int64
+	for _, k := range keys {
+		location, count, offset := int64(0), int64(k.Count()), int64(0)
+		switch {
+		case k.DataType == DataType_Short && len(k.Shorts) == 1:
+			offset = int64(k.Shorts[0])
+		case k.DataType == DataType_Short:
+			location, offset = int64(TagType_GeoKeyDirectoryTag), int64(4+4*len(keys)+len(shorts))
+			for _, v := range k.Shorts {
+				shorts = append(shorts, int64(v))
+			}
+		case k.DataType == DataType_Double:
+			location, offset = int64(TagType_GeoDoubleParamsTag), int64(len(doubles))
+			doubles = append(doubles, k.Doubles...)
+		case k.DataType == DataType_ASCII:
+			location, offset = int64(TagType_GeoAsciiParamsTag), int64(len(ascii))
+			ascii += k.Ascii + "|"
+		}
+		dir = append(dir, int64(k.ID), location, count, offset)
+	}
+	dir = append(dir, shorts...)
+	return
+}
+
+// set adds k to d, replacing any key with the same ID.
+func (d *GeoKeyDirectory) set(k GeoKey) {
+	for i := range d.Keys {
+		if d.Keys[i].ID == k.ID {
+			d.Keys[i] = k
+			return
+		}
+	}
+	d.Keys = append(d.Keys, k)
+}
+
+// SetShort sets the SHORT key with the given ID to v.
+func (d *GeoKeyDirectory) SetShort(id GeoKeyType, v int) {
+	d.set(GeoKey{ID: id, DataType: DataType_Short, Shorts: []uint16{uint16(v)}})
+}
+
+// SetDouble sets the DOUBLE key with the given ID to v.
+func (d *GeoKeyDirectory) SetDouble(id GeoKeyType, v ...float64) {
+	d.set(GeoKey{ID: id, DataType: DataType_Double, Doubles: append([]float64(nil), v...)})
+}
+
+// SetAscii sets the ASCII key with the given ID to v,
+// which must not contain '|'.
+func (d *GeoKeyDirectory) SetAscii(id GeoKeyType, v string) {
+	d.set(GeoKey{ID: id, DataType: DataType_ASCII, Ascii: v})
+}
+
+// Key returns the key with the given ID.
+func (d *GeoKeyDirectory) Key(id GeoKeyType) (k *GeoKey, ok bool) {
+	for i := range d.Keys {
+		if d.Keys[i].ID == id {
+			return &d.Keys[i], true
+		}
+	}
+	return nil, false
+}
+
+// Short returns the first value of the SHORT key with the given ID.
+func (d *GeoKeyDirectory) Short(id GeoKeyType) (v int, ok bool) {
+	if k, ok := d.Key(id); ok && len(k.Shorts) > 0 {
+		return int(k.Shorts[0]), true
+	}
+	return 0, false
+}
+
+// Double returns the first value of the DOUBLE key with the given ID.
+func (d *GeoKeyDirectory) Double(id GeoKeyType) (v float64, ok bool) {
+	if k, ok := d.Key(id); ok && len(k.Doubles) > 0 {
+		return k.Doubles[0], true
+	}
+	return 0, false
+}
+
+// Ascii returns the value of the ASCII key with the given ID.
+func (d *GeoKeyDirectory) Ascii(id GeoKeyType) (v string, ok bool) {
+	if k, ok := d.Key(id); ok && k.DataType == DataType_ASCII {
+		return k.Ascii, true
+	}
+	return "", false
+}
+
+// code returns the value of a SHORT key, or GeoKeyUndefined if it is missing.
+func (d *GeoKeyDirectory) code(id GeoKeyType) int {
+	v, _ := d.Short(id)
+	return v
+}
+
+func (d *GeoKeyDirectory) ModelType() GeoModelType {
+	return GeoModelType(d.code(GeoKeyType_GTModelTypeGeoKey))
+}
+
+// RasterType returns the raster space of the image. It defaults to
+// PixelIsArea if the key is missing.
+func (d *GeoKeyDirectory) RasterType() GeoRasterType {
+	if v, ok := d.Short(GeoKeyType_GTRasterTypeGeoKey); ok {
+		return GeoRasterType(v)
+	}
+	return GeoRasterType_PixelIsArea
+}
+
+func (d *GeoKeyDirectory) Citation() string {
+	v, _ := d.Ascii(GeoKeyType_GTCitationGeoKey)
+	return v
+}
+
+// GeographicType returns the EPSG code of the geographic coordinate system.
+func (d *GeoKeyDirectory) GeographicType() int {
+	return d.code(GeoKeyType_GeographicTypeGeoKey)
+}
+
+func (d *GeoKeyDirectory) GeogCitation() string {
+	v, _ := d.Ascii(GeoKeyType_GeogCitationGeoKey)
+	return v
+}
+
+// GeodeticDatum returns the EPSG code of the geodetic datum.
+func (d *GeoKeyDirectory) GeodeticDatum() int {
+	return d.code(GeoKeyType_GeogGeodeticDatumGeoKey)
+}
+
+// PrimeMeridian returns the EPSG code of the prime meridian.
+func (d *GeoKeyDirectory) PrimeMeridian() int {
+	return d.code(GeoKeyType_GeogPrimeMeridianGeoKey)
+}
+
+// Ellipsoid returns the EPSG code of the ellipsoid.
+func (d *GeoKeyDirectory) Ellipsoid() int {
+	return d.code(GeoKeyType_GeogEllipsoidGeoKey)
+}
+
+func (d *GeoKeyDirectory) GeogLinearUnits() GeoUnitsType {
+	return GeoUnitsType(d.code(GeoKeyType_GeogLinearUnitsGeoKey))
+}
+
+func (d *GeoKeyDirectory) GeogAngularUnits() GeoUnitsType {
+	return GeoUnitsType(d.code(GeoKeyType_GeogAngularUnitsGeoKey))
+}
+
+func (d *GeoKeyDirectory) GeogAzimuthUnits() GeoUnitsType {
+	return GeoUnitsType(d.code(GeoKeyType_GeogAzimuthUnitsGeoKey))
+}
+
+// ProjectedCSType returns the EPSG code of the projected coordinate system.
+func (d *GeoKeyDirectory) ProjectedCSType() int {
+	return d.code(GeoKeyType_ProjectedCSTypeGeoKey)
+}
+
+func (d *GeoKeyDirectory) PCSCitation() string {
+	v, _ := d.Ascii(GeoKeyType_PCSCitationGeoKey)
+	return v
+}
+
+// Projection returns the EPSG code of the projection.
+func (d *GeoKeyDirectory) Projection() int {
+	return d.code(GeoKeyType_ProjectionGeoKey)
+}
+
+func (d *GeoKeyDirectory) CoordTrans() GeoCoordTransType {
+	return GeoCoordTransType(d.code(GeoKeyType_ProjCoordTransGeoKey))
+}
+
+func (d *GeoKeyDirectory) ProjLinearUnits() GeoUnitsType {
+	return GeoUnitsType(d.code(GeoKeyType_ProjLinearUnitsGeoKey))
+}
+
+// ProjParams returns the DOUBLE keys of the projected coordinate system,
+// such as the false easting and the latitude of the natural origin.
+func (d *GeoKeyDirectory) ProjParams() map[GeoKeyType]float64 {
+	m := make(map[GeoKeyType]float64)
+	for _, k := range d.Keys {
+		if k.ID >= GeoKeyType_ProjectedCSTypeGeoKey && k.ID < GeoKeyType_VerticalCSTypeGeoKey && len(k.Doubles) > 0 {
+			m[k.ID] = k.Doubles[0]
+		}
+	}
+	return m
+}
+
+// VerticalCSType returns the EPSG code of the vertical coordinate system.
+func (d *GeoKeyDirectory) VerticalCSType() int {
+	return d.code(GeoKeyType_VerticalCSTypeGeoKey)
+}
+
+func (d *GeoKeyDirectory) VerticalCitation() string {
+	v, _ := d.Ascii(GeoKeyType_VerticalCitationGeoKey)
+	return v
+}
+
+// VerticalDatum returns the EPSG code of the vertical datum.
+func (d *GeoKeyDirectory) VerticalDatum() int {
+	return d.code(GeoKeyType_VerticalDatumGeoKey)
+}
+
+func (d *GeoKeyDirectory) VerticalUnits() GeoUnitsType {
+	return GeoUnitsType(d.code(GeoKeyType_VerticalUnitsGeoKey))
+}
+
+// GeoKeyDirectory returns the parsed GeoKey directory of the IFD.
+// It returns nil and no error if the IFD has no GeoKeyDirectoryTag.
+func (p *IFD) GeoKeyDirectory() (d *GeoKeyDirectory, err error) {
+	tags := p.TagGetter()
+	dir, ok := tags.GetGeoKeyDirectoryTag()
+	if !ok {
+		return
+	}
+	doubles, _ := tags.GetGeoDoubleParamsTag()
+	ascii, _ := tags.GetGeoAsciiParamsTag()
+	return ParseGeoKeyDirectory(dir, doubles, ascii)
+}
@@ -0,0 +1,392 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bufio"
+	"bytes"
+	"image"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// listgeoCodes maps the symbolic names printed by listgeo to their codes.
+// The names of the GeoTIFF tables are derived from the String methods, the
+// EPSG names used by the files in testdata/geotiff are listed here.
+var listgeoCodes = map[string]int{
+	"Undefined":                               GeoKeyUndefined,
+	"User-Defined":                            GeoKeyUserDefined,
+	"ModelTypeProjected":                      int(GeoModelType_Projected),
+	"ModelTypeGeographic":                     int(GeoModelType_Geographic),
+	"ModelTypeGeocentric":                     int(GeoModelType_Geocentric),
+	"RasterPixelIsArea":                       int(GeoRasterType_PixelIsArea),
+	"RasterPixelIsPoint":                      int(GeoRasterType_PixelIsPoint),
+	"Datum_WGS84":                             6326,
+	"Datum_WGS72":                             6322,
+	"Datum_North_American_Datum_1927":         6267,
+	"Datum_North_American_Datum_1983":         6269,
+	"Datum_Tokyo":                             6301,
+	"Datum_Stockholm_1938":                    6308,
+	"Datum_Pulkovo_1942":                      6284,
+	"Datum_Provisional_S_American_Datum_1956": 6248,
+	"Datum_OSGB_1936":                         6277,
+	"Datum_New_Zealand_Geodetic_Datum_1949":   6272,
+	"Datum_European_Datum_1950":               6230,
+	"Datum_Afgooye":                           6205,
+	"Ellipse_WGS_84":                          7030,
+	"Ellipse_Clarke_1866":                     7008,
+	"Ellipse_International_1967":              7023,
+	"Ellipse_GRS_1980":                        7019,
+	"Ellipse_Bessel_1841":                     7004,
+	"Ellipse_Krassowsky_1940":                 7024,
+	"Ellipse_Airy_1830":                       7001,
+	"GCS_NAD27":                               4267,
+	"GCS_WGS_72":                              4322,
+	"GCS_Tokyo":                               4301,
+	"PCS_Pulkovo_Gauss_zone_5":                28405,
+	"PCS_NAD83_Alabama_West":                  26930,
+	"PCS_NAD83_California_6":                  26946,
+	"PCS_NAD27_Alabama_West":                  26730,
+	"PCS_NAD27_California_VI":                 26746,
+	"PCS_NAD27_UTM_zone_11N":                  26711,
+	"PCS_British_National_Grid":               27700,
+	"Proj_UTM_zone_16N":                       16016,
+}
+
+func init() {
+	for v, name := range _GeoCoordTransTypeTable {
+		listgeoCodes["CT_"+strings.TrimPrefix(name, "GeoCoordTransType_")] = int(v)
+	}
+	for v, name := range _GeoUnitsTypeTable {
+		listgeoCodes[strings.TrimPrefix(name, "GeoUnitsType_")] = int(v)
+	}
+}
+
+var listgeoKeyLine = regexp.MustCompile(`^\s*(\w+) \((Short|Double|Ascii),(\d+)\): (.*)$`)
+
+// readListgeo returns the key revision and the keys of a listgeo dump.
+func readListgeo(t *testing.T, filename string) (revision string, keys []string) {
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var inKeys bool
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		switch {
+		case strings.HasPrefix(line, "Key_Revision:"):
+			revision = strings.TrimSpace(strings.TrimPrefix(line, "Key_Revision:"))
+		case line == "Keyed_Information:":
+			inKeys = true
+		case line == "End_Of_Keys.":
+			inKeys = false
+		case inKeys:
+			keys = append(keys, line)
+		}
+	}
+	return
+}
+
+func openGeoTiff(t *testing.T, filename string) *Reader {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("%s: %v", filename, err)
+	}
+	return p
+}
+
+func TestGeoKeyDirectory_listgeo(t *testing.T) {
+	dumps, err := filepath.Glob(testdataDir + "geotiff/*/*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tested int
+	for _, dump := range dumps {
+		revision, want := readListgeo(t, dump)
+		name := strings.TrimSuffix(dump, ".txt") + ".tif"
+		if want == nil {
+			continue
+		}
+		if _, err := os.Stat(name); err != nil {
+			continue
+		}
+		p := openGeoTiff(t, name)
+		d, err := p.ImageGeoKeyDirectory(0, 0)
+		p.Close()
+		if err != nil || d == nil {
+			t.Fatalf("%s: %v, %v", name, d, err)
+		}
+		if s := strconv.Itoa(d.KeyRevision) + "." + strconv.Itoa(d.MinorRevision); s != revision {
+			t.Errorf("%s: revision = %s, want %s", name, s, revision)
+		}
+		if len(d.Keys) != len(want) {
+			t.Fatalf("%s: got %d keys, want %d", name, len(d.Keys), len(want))
+		}
+		for i, line := range want {
+			m := listgeoKeyLine.FindStringSubmatch(line)
+			if m == nil {
+				t.Fatalf("%s: bad listgeo line %q", name, line)
+			}
+			k := &d.Keys[i]
+			if s := strings.TrimPrefix(k.ID.String(), "GeoKeyType_"); s != m[1] {
+				t.Errorf("%s: key %d = %s, want %s", name, i, s, m[1])
+				continue
+			}
+			if n, _ := strconv.Atoi(m[3]); k.Count() != n {
+				t.Errorf("%s: %s count = %d, want %d", name, m[1], k.Count(), n)
+			}
+			value := strings.TrimSpace(m[4])
+			switch m[2] {
+			case "Short":
+				code, ok := listgeoCodes[value]
+				if !ok {
+					code, err = strconv.Atoi(value)
+					if err != nil {
+						t.Fatalf("%s: %s has unknown value %q", name, m[1], value)
+					}
+				}
+				if v, _ := d.Short(k.ID); v != code {
+					t.Errorf("%s: %s = %d, want %s (%d)", name, m[1], v, value, code)
+				}
+			case "Double":
+				f, err := strconv.ParseFloat(strings.Fields(value)[0], 64)
+				if err != nil {
+					t.Fatalf("%s: %s: %v", name, m[1], err)
+				}
+				if v, _ := d.Double(k.ID); math.Abs(v-f) > 1e-6*math.Max(1, math.Abs(f)) {
+					t.Errorf("%s: %s = %v, want %v", name, m[1], v, f)
+				}
+			case "Ascii":
+				if v, _ := d.Ascii(k.ID); strconv.Quote(v) != value {
+					t.Errorf("%s: %s = %q, want %s", name, m[1], v, value)
+				}
+			}
+		}
+		tested++
+	}
+	if tested < 80 {
+		t.Fatalf("only %d listgeo dumps tested", tested)
+	}
+}
+
+func TestGeoKeyDirectory_typed(t *testing.T) {
+	p := openGeoTiff(t, testdataDir+"geotiff/intergraph/utm.tif")
+	defer p.Close()
+	d, err := p.ImageGeoKeyDirectory(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := d.ModelType(); v != GeoModelType_Projected {
+		t.Errorf("ModelType = %v", v)
+	}
+	if v := d.RasterType(); v != GeoRasterType_PixelIsArea {
+		t.Errorf("RasterType = %v", v)
+	}
+	if v := d.Projection(); v != 16016 {
+		t.Errorf("Projection = %v", v)
+	}
+	if v := d.ProjectedCSType(); v != GeoKeyUserDefined {
+		t.Errorf("ProjectedCSType = %v", v)
+	}
+	if v := d.GeodeticDatum(); v != 6267 {
+		t.Errorf("GeodeticDatum = %v", v)
+	}
+	if v := d.Ellipsoid(); v != 7008 {
+		t.Errorf("Ellipsoid = %v", v)
+	}
+	if v := d.ProjLinearUnits(); v != GeoUnitsType_Linear_Meter {
+		t.Errorf("ProjLinearUnits = %v", v)
+	}
+	if v := d.PCSCitation(); v != "Universal Transverse Mercator North American 1927 Zone Number 16N" {
+		t.Errorf("PCSCitation = %q", v)
+	}
+	params := map[GeoKeyType]float64{
+		GeoKeyType_ProjNatOriginLongGeoKey:    -87,
+		GeoKeyType_ProjNatOriginLatGeoKey:     0,
+		GeoKeyType_ProjFalseEastingGeoKey:     500000,
+		GeoKeyType_ProjFalseNorthingGeoKey:    0,
+		GeoKeyType_ProjScaleAtNatOriginGeoKey: 0.9996,
+	}
+	got := d.ProjParams()
+	if len(got) != len(params) {
+		t.Errorf("ProjParams = %v, want %v", got, params)
+	}
+	for k, v := range params {
+		if got[k] != v {
+			t.Errorf("ProjParams[%v] = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestParseGeoKeyDirectory_bad(t *testing.T) {
+	for _, dir := range [][]int64{
+		{1, 1},
+		{1, 1, 0, 2, 1024, 0, 1, 1},
+		{1, 1, 0, 1, 3082, 34736, 1, 5},
+		{1, 1, 0, 1, 1026, 34737, 4, 9},
+		{1, 1, 0, 1, 1026, 12345, 1, 0},
+	} {
+		if _, err := ParseGeoKeyDirectory(dir, []float64{1}, "abc|"); err == nil {
+			t.Errorf("%v: no error", dir)
+		}
+	}
+}
+
+func TestGeoTiff_roundtrip(t *testing.T) {
+	dumps, err := filepath.Glob(testdataDir + "geotiff/intergraph/*.tif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := image.NewGray(image.Rect(0, 0, 8, 8))
+	for _, name := range dumps {
+		p := openGeoTiff(t, name)
+		d, err := p.ImageGeoKeyDirectory(0, 0)
+		if err != nil || d == nil {
+			t.Fatalf("%s: %v, %v", name, d, err)
+		}
+		gt, ok := p.ImageGeoTransform(0, 0)
+		wantScale, _ := p.Ifd[0][0].TagGetter().GetModelPixelScaleTag()
+		p.Close()
+		if !ok {
+			t.Fatalf("%s: no geotransform", name)
+		}
+
+		var out bytes.Buffer
+		if err := Encode(&out, m, &Options{GeoKeys: d, GeoTransform: &gt}); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		p, err = OpenReader(bytes.NewReader(out.Bytes()))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		tags := p.Ifd[0][0].TagGetter()
+		wantDir, wantDoubles, wantAscii := d.Values()
+		if v, _ := tags.GetGeoKeyDirectoryTag(); !reflect.DeepEqual(v, wantDir) {
+			t.Errorf("%s: GeoKeyDirectoryTag = %v, want %v", name, v, wantDir)
+		}
+		if v, _ := tags.GetGeoDoubleParamsTag(); !reflect.DeepEqual(v, wantDoubles) {
+			t.Errorf("%s: GeoDoubleParamsTag = %v, want %v", name, v, wantDoubles)
+		}
+		if v, _ := tags.GetGeoAsciiParamsTag(); v != wantAscii {
+			t.Errorf("%s: GeoAsciiParamsTag = %q, want %q", name, v, wantAscii)
+		}
+		if v, _ := tags.GetModelPixelScaleTag(); wantScale != nil && !reflect.DeepEqual(v[:2], wantScale[:2]) {
+			t.Errorf("%s: ModelPixelScaleTag = %v, want %v", name, v, wantScale)
+		}
+		d2, err := p.ImageGeoKeyDirectory(0, 0)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for _, k := range d.Keys {
+			if k2, ok := d2.Key(k.ID); !ok || !reflect.DeepEqual(*k2, k) {
+				t.Errorf("%s: %v read back as %v", name, &k, k2)
+			}
+		}
+		if gt2, _ := p.ImageGeoTransform(0, 0); gt2 != gt {
+			t.Errorf("%s: geotransform = %v, want %v", name, gt2, gt)
+		}
+		p.Close()
+	}
+}
+
+// epsgKeys returns the GeoKey directory of NewGeoKeyDirectoryEPSG.
+func epsgKeys(t *testing.T, model GeoModelType, code int) *GeoKeyDirectory {
+	d, err := NewGeoKeyDirectoryEPSG(model, code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestNewGeoKeyDirectoryEPSG(t *testing.T) {
+	// The model is not guessed from the code: EPSG:4087 is projected and
+	// EPSG:7844 geographic.
+	if d := epsgKeys(t, GeoModelType_Projected, 4087); d.ModelType() != GeoModelType_Projected || d.ProjectedCSType() != 4087 {
+		t.Errorf("4087: model %v, crs %d", d.ModelType(), d.ProjectedCSType())
+	}
+	if d := epsgKeys(t, GeoModelType_Geographic, 7844); d.ModelType() != GeoModelType_Geographic || d.GeographicType() != 7844 {
+		t.Errorf("7844: model %v, crs %d", d.ModelType(), d.GeographicType())
+	}
+	for _, tt := range []struct {
+		model GeoModelType
+		code  int
+	}{
+		{GeoModelType_Projected, 0},
+		{GeoModelType_Projected, 65536},
+		{GeoModelType_Geographic, -1},
+		{GeoModelType_Geocentric, 4978},
+		{GeoModelType_Undefined, 4326},
+	} {
+		if _, err := NewGeoKeyDirectoryEPSG(tt.model, tt.code); err == nil {
+			t.Errorf("%v %d: no error", tt.model, tt.code)
+		}
+	}
+}
+
+func TestGeoTiff_encode(t *testing.T) {
+	m := image.NewGray(image.Rect(0, 0, 8, 8))
+	point := epsgKeys(t, GeoModelType_Projected, 32633)
+	point.SetShort(GeoKeyType_GTRasterTypeGeoKey, int(GeoRasterType_PixelIsPoint))
+	for _, tt := range []struct {
+		keys      *GeoKeyDirectory
+		gt        GeoTransform
+		model     GeoModelType
+		crs       int
+		hasMatrix bool
+		tiepoints []float64
+	}{
+		{epsgKeys(t, GeoModelType_Geographic, 4326), GeoTransform{-180, 0.5, 0, 90, 0, -0.5}, GeoModelType_Geographic, 4326, false, []float64{0, 0, 0, -180, 90, 0}},
+		{epsgKeys(t, GeoModelType_Projected, 32633), GeoTransform{500000, 30, 5, 4e6, 5, -30}, GeoModelType_Projected, 32633, true, nil},
+		{point, GeoTransform{500000, 30, 0, 4e6, 0, -30}, GeoModelType_Projected, 32633, false, []float64{0, 0, 0, 500015, 4e6 - 15, 0}},
+	} {
+		var out bytes.Buffer
+		if err := Encode(&out, m, &Options{GeoKeys: tt.keys, GeoTransform: &tt.gt}); err != nil {
+			t.Fatal(err)
+		}
+		p, err := OpenReader(bytes.NewReader(out.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, err := p.ImageGeoKeyDirectory(0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		crs := d.ProjectedCSType()
+		if tt.model == GeoModelType_Geographic {
+			crs = d.GeographicType()
+		}
+		if d.ModelType() != tt.model || crs != tt.crs {
+			t.Errorf("%v: model %v, crs %d", tt.gt, d.ModelType(), crs)
+		}
+		tags := p.Ifd[0][0].TagGetter()
+		if _, ok := tags.GetModelTransformationTag(); ok != tt.hasMatrix {
+			t.Errorf("%v: ModelTransformationTag present = %v", tt.gt, ok)
+		}
+		if v, _ := tags.GetModelTiepointTag(); !reflect.DeepEqual(v, tt.tiepoints) {
+			t.Errorf("%v: ModelTiepointTag = %v, want %v", tt.gt, v, tt.tiepoints)
+		}
+		if gt, ok := p.ImageGeoTransform(0, 0); !ok || gt != tt.gt {
+			t.Errorf("geotransform = %v, want %v", gt, tt.gt)
+		}
+		p.Close()
+	}
+
+	if err := Encode(ioutil.Discard, m, &Options{GeoTransform: &GeoTransform{}}); err == nil {
+		t.Errorf("singular geotransform: no error")
+	}
+}
@@ -0,0 +1,326 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"fmt"
+)
+
+// GeoKeyType is the ID of a key in the GeoKeyDirectoryTag.
+//
+// See http://geotiff.maptools.org/spec/geotiff6.html#6.2
+type GeoKeyType uint16
+
+const (
+	// GeoTIFF Configuration Keys.
+	GeoKeyType_GTModelTypeGeoKey  GeoKeyType = 1024 // SHORT # GeoModelType
+	GeoKeyType_GTRasterTypeGeoKey GeoKeyType = 1025 // SHORT # GeoRasterType
+	GeoKeyType_GTCitationGeoKey   GeoKeyType = 1026 // ASCII
+
+	// Geographic CS Parameter Keys.
+	GeoKeyType_GeographicTypeGeoKey        GeoKeyType = 2048 // SHORT # EPSG geographic CS code
+	GeoKeyType_GeogCitationGeoKey          GeoKeyType = 2049 // ASCII
+	GeoKeyType_GeogGeodeticDatumGeoKey     GeoKeyType = 2050 // SHORT # EPSG datum code
+	GeoKeyType_GeogPrimeMeridianGeoKey     GeoKeyType = 2051 // SHORT # EPSG prime meridian code
+	GeoKeyType_GeogLinearUnitsGeoKey       GeoKeyType = 2052 // SHORT # GeoUnitsType
+	GeoKeyType_GeogLinearUnitSizeGeoKey    GeoKeyType = 2053 // DOUBLE # meters
+	GeoKeyType_GeogAngularUnitsGeoKey      GeoKeyType = 2054 // SHORT # GeoUnitsType
+	GeoKeyType_GeogAngularUnitSizeGeoKey   GeoKeyType = 2055 // DOUBLE # radians
+	GeoKeyType_GeogEllipsoidGeoKey         GeoKeyType = 2056 // SHORT # EPSG ellipsoid code
+	GeoKeyType_GeogSemiMajorAxisGeoKey     GeoKeyType = 2057 // DOUBLE # GeogLinearUnits
+	GeoKeyType_GeogSemiMinorAxisGeoKey     GeoKeyType = 2058 // DOUBLE # GeogLinearUnits
+	GeoKeyType_GeogInvFlatteningGeoKey     GeoKeyType = 2059 // DOUBLE
+	GeoKeyType_GeogAzimuthUnitsGeoKey      GeoKeyType = 2060 // SHORT # GeoUnitsType
+	GeoKeyType_GeogPrimeMeridianLongGeoKey GeoKeyType = 2061 // DOUBLE # GeogAngularUnits
+	GeoKeyType_GeogTOWGS84GeoKey           GeoKeyType = 2062 // DOUBLE, 3 or 7 # Datum shift to WGS84.
+
+	// Projected CS Parameter Keys.
+	GeoKeyType_ProjectedCSTypeGeoKey          GeoKeyType = 3072 // SHORT # EPSG projected CS code
+	GeoKeyType_PCSCitationGeoKey              GeoKeyType = 3073 // ASCII
+	GeoKeyType_ProjectionGeoKey               GeoKeyType = 3074 // SHORT # EPSG projection code
+	GeoKeyType_ProjCoordTransGeoKey           GeoKeyType = 3075 // SHORT # GeoCoordTransType
+	GeoKeyType_ProjLinearUnitsGeoKey          GeoKeyType = 3076 // SHORT # GeoUnitsType
+	GeoKeyType_ProjLinearUnitSizeGeoKey       GeoKeyType = 3077 // DOUBLE # meters
+	GeoKeyType_ProjStdParallel1GeoKey         GeoKeyType = 3078 // DOUBLE # GeogAngularUnits
+	GeoKeyType_ProjStdParallel2GeoKey         GeoKeyType = 3079 // DOUBLE # GeogAngularUnits
+	GeoKeyType_ProjNatOriginLongGeoKey        GeoKeyType = 3080 // DOUBLE # GeogAngularUnits
+	GeoKeyType_ProjNatOriginLatGeoKey         GeoKeyType = 3081 // DOUBLE # GeogAngularUnits
+	GeoKeyType_ProjFalseEastingGeoKey         GeoKeyType = 3082 // DOUBLE # ProjLinearUnits
+	GeoKeyType_ProjFalseNorthingGeoKey        GeoKeyType = 3083 // DOUBLE # ProjLinearUnits
+	GeoKeyType_ProjFalseOriginLongGeoKey      GeoKeyType = 3084 // DOUBLE # GeogAngularUnits
+	GeoKeyType_ProjFalseOriginLatGeoKey       GeoKeyType = 3085 // DOUBLE # GeogAngularUnits
+	GeoKeyType_ProjFalseOriginEastingGeoKey   GeoKeyType = 3086 // DOUBLE # ProjLinearUnits
+	GeoKeyType_ProjFalseOriginNorthingGeoKey  GeoKeyType = 3087 // DOUBLE # ProjLinearUnits
+	GeoKeyType_ProjCenterLongGeoKey           GeoKeyType = 3088 // DOUBLE # GeogAngularUnits
+	GeoKeyType_ProjCenterLatGeoKey            GeoKeyType = 3089 // DOUBLE # GeogAngularUnits
+	GeoKeyType_ProjCenterEastingGeoKey        GeoKeyType = 3090 // DOUBLE # ProjLinearUnits
+	GeoKeyType_ProjCenterNorthingGeoKey       GeoKeyType = 3091 // DOUBLE # ProjLinearUnits
+	GeoKeyType_ProjScaleAtNatOriginGeoKey     GeoKeyType = 3092 // DOUBLE # ratio
+	GeoKeyType_ProjScaleAtCenterGeoKey        GeoKeyType = 3093 // DOUBLE # ratio
+	GeoKeyType_ProjAzimuthAngleGeoKey         GeoKeyType = 3094 // DOUBLE # GeogAzimuthUnits
+	GeoKeyType_ProjStraightVertPoleLongGeoKey GeoKeyType = 3095 // DOUBLE # GeogAngularUnits
+	GeoKeyType_ProjRectifiedGridAngleGeoKey   GeoKeyType = 3096 // DOUBLE # GeogAngularUnits
+
+	// Vertical CS Parameter Keys.
+	GeoKeyType_VerticalCSTypeGeoKey   GeoKeyType = 4096 // SHORT # EPSG vertical CS code
+	GeoKeyType_VerticalCitationGeoKey GeoKeyType = 4097 // ASCII
+	GeoKeyType_VerticalDatumGeoKey    GeoKeyType = 4098 // SHORT # EPSG vertical datum code
+	GeoKeyType_VerticalUnitsGeoKey    GeoKeyType = 4099 // SHORT # GeoUnitsType
+)
+
+// Codes shared by all SHORT GeoKeys.
+const (
+	GeoKeyUndefined   = 0
+	GeoKeyUserDefined = 32767
+)
+
+// GeoModelType is the value of the GTModelTypeGeoKey.
+type GeoModelType uint16
+
+const (
+	GeoModelType_Undefined   GeoModelType = 0
+	GeoModelType_Projected   GeoModelType = 1 // # Projection coordinate system.
+	GeoModelType_Geographic  GeoModelType = 2 // # Geographic latitude-longitude system.
+	GeoModelType_Geocentric  GeoModelType = 3 // # Geocentric (X,Y,Z) coordinate system.
+	GeoModelType_UserDefined GeoModelType = 32767
+)
+
+// GeoRasterType is the value of the GTRasterTypeGeoKey.
+type GeoRasterType uint16
+
+const (
+	GeoRasterType_Undefined    GeoRasterType = 0
+	GeoRasterType_PixelIsArea  GeoRasterType = 1
+	GeoRasterType_PixelIsPoint GeoRasterType = 2
+	GeoRasterType_UserDefined  GeoRasterType = 32767
+)
+
+// GeoCoordTransType is the value of the ProjCoordTransGeoKey.
+type GeoCoordTransType uint16
+
+const (
+	GeoCoordTransType_Undefined                    GeoCoordTransType = 0
+	GeoCoordTransType_TransverseMercator           GeoCoordTransType = 1
+	GeoCoordTransType_TransvMercator_Modified      GeoCoordTransType = 2 // # Alaska.
+	GeoCoordTransType_ObliqueMercator              GeoCoordTransType = 3
+	GeoCoordTransType_ObliqueMercator_Laborde      GeoCoordTransType = 4
+	GeoCoordTransType_ObliqueMercator_Rosenmund    GeoCoordTransType = 5
+	GeoCoordTransType_ObliqueMercator_Spherical    GeoCoordTransType = 6
+	GeoCoordTransType_Mercator                     GeoCoordTransType = 7
+	GeoCoordTransType_LambertConfConic_2SP         GeoCoordTransType = 8
+	GeoCoordTransType_LambertConfConic_Helmert     GeoCoordTransType = 9 // # 1SP.
+	GeoCoordTransType_LambertAzimEqualArea         GeoCoordTransType = 10
+	GeoCoordTransType_AlbersEqualArea              GeoCoordTransType = 11
+	GeoCoordTransType_AzimuthalEquidistant         GeoCoordTransType = 12
+	GeoCoordTransType_EquidistantConic             GeoCoordTransType = 13
+	GeoCoordTransType_Stereographic                GeoCoordTransType = 14
+	GeoCoordTransType_PolarStereographic           GeoCoordTransType = 15
+	GeoCoordTransType_ObliqueStereographic         GeoCoordTransType = 16
+	GeoCoordTransType_Equirectangular              GeoCoordTransType = 17
+	GeoCoordTransType_CassiniSoldner               GeoCoordTransType = 18
+	GeoCoordTransType_Gnomonic                     GeoCoordTransType = 19
+	GeoCoordTransType_MillerCylindrical            GeoCoordTransType = 20
+	GeoCoordTransType_Orthographic                 GeoCoordTransType = 21
+	GeoCoordTransType_Polyconic                    GeoCoordTransType = 22
+	GeoCoordTransType_Robinson                     GeoCoordTransType = 23
+	GeoCoordTransType_Sinusoidal                   GeoCoordTransType = 24
+	GeoCoordTransType_VanDerGrinten                GeoCoordTransType = 25
+	GeoCoordTransType_NewZealandMapGrid            GeoCoordTransType = 26
+	GeoCoordTransType_TransvMercator_SouthOriented GeoCoordTransType = 27
+	GeoCoordTransType_CylindricalEqualArea         GeoCoordTransType = 28
+	GeoCoordTransType_UserDefined                  GeoCoordTransType = 32767
+)
+
+// GeoUnitsType is the value of the linear, angular and azimuth units GeoKeys.
+type GeoUnitsType uint16
+
+const (
+	GeoUnitsType_Undefined                          GeoUnitsType = 0
+	GeoUnitsType_Linear_Meter                       GeoUnitsType = 9001
+	GeoUnitsType_Linear_Foot                        GeoUnitsType = 9002
+	GeoUnitsType_Linear_Foot_US_Survey              GeoUnitsType = 9003
+	GeoUnitsType_Linear_Foot_Modified_American      GeoUnitsType = 9004
+	GeoUnitsType_Linear_Foot_Clarke                 GeoUnitsType = 9005
+	GeoUnitsType_Linear_Foot_Indian                 GeoUnitsType = 9006
+	GeoUnitsType_Linear_Link                        GeoUnitsType = 9007
+	GeoUnitsType_Linear_Link_Benoit                 GeoUnitsType = 9008
+	GeoUnitsType_Linear_Link_Sears                  GeoUnitsType = 9009
+	GeoUnitsType_Linear_Chain_Benoit                GeoUnitsType = 9010
+	GeoUnitsType_Linear_Chain_Sears                 GeoUnitsType = 9011
+	GeoUnitsType_Linear_Yard_Sears                  GeoUnitsType = 9012
+	GeoUnitsType_Linear_Yard_Indian                 GeoUnitsType = 9013
+	GeoUnitsType_Linear_Fathom                      GeoUnitsType = 9014
+	GeoUnitsType_Linear_Mile_International_Nautical GeoUnitsType = 9015
+	GeoUnitsType_Angular_Radian                     GeoUnitsType = 9101
+	GeoUnitsType_Angular_Degree                     GeoUnitsType = 9102
+	GeoUnitsType_Angular_Arc_Minute                 GeoUnitsType = 9103
+	GeoUnitsType_Angular_Arc_Second                 GeoUnitsType = 9104
+	GeoUnitsType_Angular_Grad                       GeoUnitsType = 9105
+	GeoUnitsType_Angular_Gon                        GeoUnitsType = 9106
+	GeoUnitsType_Angular_DMS                        GeoUnitsType = 9107
+	GeoUnitsType_Angular_DMS_Hemisphere             GeoUnitsType = 9108
+	GeoUnitsType_UserDefined                        GeoUnitsType = 32767
+)
+
+var _GeoKeyTypeTable = map[GeoKeyType]string{
+	GeoKeyType_GTModelTypeGeoKey:              `GeoKeyType_GTModelTypeGeoKey`,
+	GeoKeyType_GTRasterTypeGeoKey:             `GeoKeyType_GTRasterTypeGeoKey`,
+	GeoKeyType_GTCitationGeoKey:               `GeoKeyType_GTCitationGeoKey`,
+	GeoKeyType_GeographicTypeGeoKey:           `GeoKeyType_GeographicTypeGeoKey`,
+	GeoKeyType_GeogCitationGeoKey:             `GeoKeyType_GeogCitationGeoKey`,
+	GeoKeyType_GeogGeodeticDatumGeoKey:        `GeoKeyType_GeogGeodeticDatumGeoKey`,
+	GeoKeyType_GeogPrimeMeridianGeoKey:        `GeoKeyType_GeogPrimeMeridianGeoKey`,
+	GeoKeyType_GeogLinearUnitsGeoKey:          `GeoKeyType_GeogLinearUnitsGeoKey`,
+	GeoKeyType_GeogLinearUnitSizeGeoKey:       `GeoKeyType_GeogLinearUnitSizeGeoKey`,
+	GeoKeyType_GeogAngularUnitsGeoKey:         `GeoKeyType_GeogAngularUnitsGeoKey`,
+	GeoKeyType_GeogAngularUnitSizeGeoKey:      `GeoKeyType_GeogAngularUnitSizeGeoKey`,
+	GeoKeyType_GeogEllipsoidGeoKey:            `GeoKeyType_GeogEllipsoidGeoKey`,
+	GeoKeyType_GeogSemiMajorAxisGeoKey:        `GeoKeyType_GeogSemiMajorAxisGeoKey`,
+	GeoKeyType_GeogSemiMinorAxisGeoKey:        `GeoKeyType_GeogSemiMinorAxisGeoKey`,
+	GeoKeyType_GeogInvFlatteningGeoKey:        `GeoKeyType_GeogInvFlatteningGeoKey`,
+	GeoKeyType_GeogAzimuthUnitsGeoKey:         `GeoKeyType_GeogAzimuthUnitsGeoKey`,
+	GeoKeyType_GeogPrimeMeridianLongGeoKey:    `GeoKeyType_GeogPrimeMeridianLongGeoKey`,
+	GeoKeyType_GeogTOWGS84GeoKey:              `GeoKeyType_GeogTOWGS84GeoKey`,
+	GeoKeyType_ProjectedCSTypeGeoKey:          `GeoKeyType_ProjectedCSTypeGeoKey`,
+	GeoKeyType_PCSCitationGeoKey:              `GeoKeyType_PCSCitationGeoKey`,
+	GeoKeyType_ProjectionGeoKey:               `GeoKeyType_ProjectionGeoKey`,
+	GeoKeyType_ProjCoordTransGeoKey:           `GeoKeyType_ProjCoordTransGeoKey`,
+	GeoKeyType_ProjLinearUnitsGeoKey:          `GeoKeyType_ProjLinearUnitsGeoKey`,
+	GeoKeyType_ProjLinearUnitSizeGeoKey:       `GeoKeyType_ProjLinearUnitSizeGeoKey`,
+	GeoKeyType_ProjStdParallel1GeoKey:         `GeoKeyType_ProjStdParallel1GeoKey`,
+	GeoKeyType_ProjStdParallel2GeoKey:         `GeoKeyType_ProjStdParallel2GeoKey`,
+	GeoKeyType_ProjNatOriginLongGeoKey:        `GeoKeyType_ProjNatOriginLongGeoKey`,
+	GeoKeyType_ProjNatOriginLatGeoKey:         `GeoKeyType_ProjNatOriginLatGeoKey`,
+	GeoKeyType_ProjFalseEastingGeoKey:         `GeoKeyType_ProjFalseEastingGeoKey`,
+	GeoKeyType_ProjFalseNorthingGeoKey:        `GeoKeyType_ProjFalseNorthingGeoKey`,
+	GeoKeyType_ProjFalseOriginLongGeoKey:      `GeoKeyType_ProjFalseOriginLongGeoKey`,
+	GeoKeyType_ProjFalseOriginLatGeoKey:       `GeoKeyType_ProjFalseOriginLatGeoKey`,
+	GeoKeyType_ProjFalseOriginEastingGeoKey:   `GeoKeyType_ProjFalseOriginEastingGeoKey`,
+	GeoKeyType_ProjFalseOriginNorthingGeoKey:  `GeoKeyType_ProjFalseOriginNorthingGeoKey`,
+	GeoKeyType_ProjCenterLongGeoKey:           `GeoKeyType_ProjCenterLongGeoKey`,
+	GeoKeyType_ProjCenterLatGeoKey:            `GeoKeyType_ProjCenterLatGeoKey`,
+	GeoKeyType_ProjCenterEastingGeoKey:        `GeoKeyType_ProjCenterEastingGeoKey`,
+	GeoKeyType_ProjCenterNorthingGeoKey:       `GeoKeyType_ProjCenterNorthingGeoKey`,
+	GeoKeyType_ProjScaleAtNatOriginGeoKey:     `GeoKeyType_ProjScaleAtNatOriginGeoKey`,
+	GeoKeyType_ProjScaleAtCenterGeoKey:        `GeoKeyType_ProjScaleAtCenterGeoKey`,
+	GeoKeyType_ProjAzimuthAngleGeoKey:         `GeoKeyType_ProjAzimuthAngleGeoKey`,
+	GeoKeyType_ProjStraightVertPoleLongGeoKey: `GeoKeyType_ProjStraightVertPoleLongGeoKey`,
+	GeoKeyType_ProjRectifiedGridAngleGeoKey:   `GeoKeyType_ProjRectifiedGridAngleGeoKey`,
+	GeoKeyType_VerticalCSTypeGeoKey:           `GeoKeyType_VerticalCSTypeGeoKey`,
+	GeoKeyType_VerticalCitationGeoKey:         `GeoKeyType_VerticalCitationGeoKey`,
+	GeoKeyType_VerticalDatumGeoKey:            `GeoKeyType_VerticalDatumGeoKey`,
+	GeoKeyType_VerticalUnitsGeoKey:            `GeoKeyType_VerticalUnitsGeoKey`,
+}
+
+func (p GeoKeyType) String() string {
+	if name, ok := _GeoKeyTypeTable[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("GeoKeyType_Unknown(%d)", uint16(p))
+}
+
+var _GeoModelTypeTable = map[GeoModelType]string{
+	GeoModelType_Undefined:   `GeoModelType_Undefined`,
+	GeoModelType_Projected:   `GeoModelType_Projected`,
+	GeoModelType_Geographic:  `GeoModelType_Geographic`,
+	GeoModelType_Geocentric:  `GeoModelType_Geocentric`,
+	GeoModelType_UserDefined: `GeoModelType_UserDefined`,
+}
+
+func (p GeoModelType) String() string {
+	if name, ok := _GeoModelTypeTable[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("GeoModelType_Unknown(%d)", uint16(p))
+}
+
+var _GeoRasterTypeTable = map[GeoRasterType]string{
+	GeoRasterType_Undefined:    `GeoRasterType_Undefined`,
+	GeoRasterType_PixelIsArea:  `GeoRasterType_PixelIsArea`,
+	GeoRasterType_PixelIsPoint: `GeoRasterType_PixelIsPoint`,
+	GeoRasterType_UserDefined:  `GeoRasterType_UserDefined`,
+}
+
+func (p GeoRasterType) String() string {
+	if name, ok := _GeoRasterTypeTable[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("GeoRasterType_Unknown(%d)", uint16(p))
+}
+
+var _GeoCoordTransTypeTable = map[GeoCoordTransType]string{
+	GeoCoordTransType_Undefined:                    `GeoCoordTransType_Undefined`,
+	GeoCoordTransType_TransverseMercator:           `GeoCoordTransType_TransverseMercator`,
+	GeoCoordTransType_TransvMercator_Modified:      `GeoCoordTransType_TransvMercator_Modified`,
+	GeoCoordTransType_ObliqueMercator:              `GeoCoordTransType_ObliqueMercator`,
+	GeoCoordTransType_ObliqueMercator_Laborde:      `GeoCoordTransType_ObliqueMercator_Laborde`,
+	GeoCoordTransType_ObliqueMercator_Rosenmund:    `GeoCoordTransType_ObliqueMercator_Rosenmund`,
+	GeoCoordTransType_ObliqueMercator_Spherical:    `GeoCoordTransType_ObliqueMercator_Spherical`,
+	GeoCoordTransType_Mercator:                     `GeoCoordTransType_Mercator`,
+	GeoCoordTransType_LambertConfConic_2SP:         `GeoCoordTransType_LambertConfConic_2SP`,
+	GeoCoordTransType_LambertConfConic_Helmert:     `GeoCoordTransType_LambertConfConic_Helmert`,
+	GeoCoordTransType_LambertAzimEqualArea:         `GeoCoordTransType_LambertAzimEqualArea`,
+	GeoCoordTransType_AlbersEqualArea:              `GeoCoordTransType_AlbersEqualArea`,
+	GeoCoordTransType_AzimuthalEquidistant:         `GeoCoordTransType_AzimuthalEquidistant`,
+	GeoCoordTransType_EquidistantConic:             `GeoCoordTransType_EquidistantConic`,
+	GeoCoordTransType_Stereographic:                `GeoCoordTransType_Stereographic`,
+	GeoCoordTransType_PolarStereographic:           `GeoCoordTransType_PolarStereographic`,
+	GeoCoordTransType_ObliqueStereographic:         `GeoCoordTransType_ObliqueStereographic`,
+	GeoCoordTransType_Equirectangular:              `GeoCoordTransType_Equirectangular`,
+	GeoCoordTransType_CassiniSoldner:               `GeoCoordTransType_CassiniSoldner`,
+	GeoCoordTransType_Gnomonic:                     `GeoCoordTransType_Gnomonic`,
+	GeoCoordTransType_MillerCylindrical:            `GeoCoordTransType_MillerCylindrical`,
+	GeoCoordTransType_Orthographic:                 `GeoCoordTransType_Orthographic`,
+	GeoCoordTransType_Polyconic:                    `GeoCoordTransType_Polyconic`,
+	GeoCoordTransType_Robinson:                     `GeoCoordTransType_Robinson`,
+	GeoCoordTransType_Sinusoidal:                   `GeoCoordTransType_Sinusoidal`,
+	GeoCoordTransType_VanDerGrinten:                `GeoCoordTransType_VanDerGrinten`,
+	GeoCoordTransType_NewZealandMapGrid:            `GeoCoordTransType_NewZealandMapGrid`,
+	GeoCoordTransType_TransvMercator_SouthOriented: `GeoCoordTransType_TransvMercator_SouthOriented`,
+	GeoCoordTransType_CylindricalEqualArea:         `GeoCoordTransType_CylindricalEqualArea`,
+	GeoCoordTransType_UserDefined:                  `GeoCoordTransType_UserDefined`,
+}
+
+func (p GeoCoordTransType) String() string {
+	if name, ok := _GeoCoordTransTypeTable[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("GeoCoordTransType_Unknown(%d)", uint16(p))
+}
+
+var _GeoUnitsTypeTable = map[GeoUnitsType]string{
+	GeoUnitsType_Undefined:                          `GeoUnitsType_Undefined`,
+	GeoUnitsType_Linear_Meter:                       `GeoUnitsType_Linear_Meter`,
+	GeoUnitsType_Linear_Foot:                        `GeoUnitsType_Linear_Foot`,
+	GeoUnitsType_Linear_Foot_US_Survey:              `GeoUnitsType_Linear_Foot_US_Survey`,
+	GeoUnitsType_Linear_Foot_Modified_American:      `GeoUnitsType_Linear_Foot_Modified_American`,
+	GeoUnitsType_Linear_Foot_Clarke:                 `GeoUnitsType_Linear_Foot_Clarke`,
+	GeoUnitsType_Linear_Foot_Indian:                 `GeoUnitsType_Linear_Foot_Indian`,
+	GeoUnitsType_Linear_Link:                        `GeoUnitsType_Linear_Link`,
+	GeoUnitsType_Linear_Link_Benoit:                 `GeoUnitsType_Linear_Link_Benoit`,
+	GeoUnitsType_Linear_Link_Sears:                  `GeoUnitsType_Linear_Link_Sears`,
+	GeoUnitsType_Linear_Chain_Benoit:                `GeoUnitsType_Linear_Chain_Benoit`,
+	GeoUnitsType_Linear_Chain_Sears:                 `GeoUnitsType_Linear_Chain_Sears`,
+	GeoUnitsType_Linear_Yard_Sears:                  `GeoUnitsType_Linear_Yard_Sears`,
+	GeoUnitsType_Linear_Yard_Indian:                 `GeoUnitsType_Linear_Yard_Indian`,
+	GeoUnitsType_Linear_Fathom:                      `GeoUnitsType_Linear_Fathom`,
+	GeoUnitsType_Linear_Mile_International_Nautical: `GeoUnitsType_Linear_Mile_International_Nautical`,
+	GeoUnitsType_Angular_Radian:                     `GeoUnitsType_Angular_Radian`,
+	GeoUnitsType_Angular_Degree:                     `GeoUnitsType_Angular_Degree`,
+	GeoUnitsType_Angular_Arc_Minute:                 `GeoUnitsType_Angular_Arc_Minute`,
+	GeoUnitsType_Angular_Arc_Second:                 `GeoUnitsType_Angular_Arc_Second`,
+	GeoUnitsType_Angular_Grad:                       `GeoUnitsType_Angular_Grad`,
+	GeoUnitsType_Angular_Gon:                        `GeoUnitsType_Angular_Gon`,
+	GeoUnitsType_Angular_DMS:                        `GeoUnitsType_Angular_DMS`,
+	GeoUnitsType_Angular_DMS_Hemisphere:             `GeoUnitsType_Angular_DMS_Hemisphere`,
+	GeoUnitsType_UserDefined:                        `GeoUnitsType_UserDefined`,
+}
+
+func (p GeoUnitsType) String() string {
+	if name, ok := _GeoUnitsTypeTable[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("GeoUnitsType_Unknown(%d)", uint16(p))
+}
@@ -0,0 +1,230 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"math"
+)
+
+// A GeoTransform is an affine transform from raster to model space,
+// with the same six coefficients as the geotransform of GDAL:
+//
+//	X = T[0] + col*T[1] + row*T[2]
+//	Y = T[3] + col*T[4] + row*T[5]
+//
+// Raster coordinates follow the PixelIsArea convention: (0, 0) is the
+// upper left corner of the upper left pixel, and (0.5, 0.5) its center.
+type GeoTransform [6]float64
+
+// A GeoPoint is a position in model space.
+type GeoPoint struct {
+	X, Y float64
+}
+
+// GeoCorners holds the model coordinates of the corners and the center
+// of a raster.
+type GeoCorners struct {
+	UpperLeft  GeoPoint
+	LowerLeft  GeoPoint
+	UpperRight GeoPoint
+	LowerRight GeoPoint
+	Center     GeoPoint
+}
+
+// NewGeoTransform returns the geotransform defined by the values of the
+// ModelTransformationTag, or else by the ModelPixelScaleTag and the first
+// tiepoint of the ModelTiepointTag. Without a scale, an affine transform is
+// fitted through three or more tiepoints, as GDAL does, and ok is false if
+// that transform misses any tiepoint by more than a quarter of a pixel.
+//
+// PixelIsPoint rasters are shifted by half a pixel so that the result
+// follows the PixelIsArea convention.
+func NewGeoTransform(scale, tiepoints, matrix []float64, raster GeoRasterType) (t GeoTransform, ok bool) {
+	switch {
+	case len(matrix) >= 16:
+		t = GeoTransform{matrix[3], matrix[0], matrix[1], matrix[7], matrix[4], matrix[5]}
+	case len(scale) >= 2 && len(tiepoints) >= 6:
+		t = GeoTransform{
+			tiepoints[3] - tiepoints[0]*scale[0], scale[0], 0,
+			tiepoints[4] + tiepoints[1]*scale[1], 0, -scale[1],
+		}
+	case len(scale) == 0 && len(tiepoints) >= 18:
+		if t, ok = fitGeoTransform(tiepoints); !ok {
+			return
+		}
+	default:
+		return
+	}
+	if raster == GeoRasterType_PixelIsPoint {
+		t[0] -= 0.5*t[1] + 0.5*t[2]
+		t[3] -= 0.5*t[4] + 0.5*t[5]
+	}
+	if _, ok = t.Invert(); !ok {
+		return
+	}
+	return t, true
+}
+
+// fitGeoTransform returns the least squares affine fit of the tiepoints.
+func fitGeoTransform(tiepoints []float64) (t GeoTransform, ok bool) {
+	// Normal equations of [1 col row] * coef = model.
+	var a [3][3]float64
+	var bx, by [3]float64
+	n := len(tiepoints) / 6
+	for i := 0; i < n; i++ {
+		p := tiepoints[6*i:]
+		v := [3]float64{1, p[0], p[1]}
+		for r := 0; r < 3; r++ {
+			for c := 0; c < 3; c++ {
+				a[r][c] += v[r] * v[c]
+			}
+			bx[r] += v[r] * p[3]
+			by[r] += v[r] * p[4]
+		}
+	}
+	cx, ok1 := solve3(a, bx)
+	cy, ok2 := solve3(a, by)
+	if !ok1 || !ok2 {
+		return
+	}
+	t = GeoTransform{cx[0], cx[1], cx[2], cy[0], cy[1], cy[2]}
+
+	inv, ok := t.Invert()
+	if !ok {
+		return
+	}
+	for i := 0; i < n; i++ {
+		p := tiepoints[6*i:]
+		col, row := inv.Apply(p[3], p[4])
+		if math.Abs(col-p[0]) > 0.25 || math.Abs(row-p[1]) > 0.25 {
+			return t, false
+		}
+	}
+	return t, true
+}
+
+// solve3 solves the linear system a*x = b by Cramer's rule.
+func solve3(a [3][3]float64, b [3]float64) (x [3]float64, ok bool) {
+	det := det3(a)
+	if det == 0 {
+		return
+	}
+	for i := 0; i < 3; i++ {
+		m := a
+		for r := 0; r < 3; r++ {
+			m[r][i] = b[r]
+		}
+		x[i] = det3(m) / det
+	}
+	return x, true
+}
+
+func det3(a [3][3]float64) float64 {
+	return a[0][0]*(a[1][1]*a[2][2]-a[1][2]*a[2][1]) -
+		a[0][1]*(a[1][0]*a[2][2]-a[1][2]*a[2][0]) +
+		a[0][2]*(a[1][0]*a[2][1]-a[1][1]*a[2][0])
+}
+
+// Apply returns t applied to (u, v).
+func (t GeoTransform) Apply(u, v float64) (x, y float64) {
+	return t[0] + u*t[1] + v*t[2], t[3] + u*t[4] + v*t[5]
+}
+
+// Invert returns the inverse transform, which maps model to raster space.
+// ok is false if t is singular.
+func (t GeoTransform) Invert() (inv GeoTransform, ok bool) {
+	det := t[1]*t[5] - t[2]*t[4]
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return
+	}
+	inv[1] = t[5] / det
+	inv[2] = -t[2] / det
+	inv[4] = -t[4] / det
+	inv[5] = t[1] / det
+	inv[0] = -(inv[1]*t[0] + inv[2]*t[3])
+	inv[3] = -(inv[4]*t[0] + inv[5]*t[3])
+	return inv, true
+}
+
+// PixelToModel returns the model coordinates of the raster position
+// (col, row). Use (col+0.5, row+0.5) for the center of a pixel.
+func (t GeoTransform) PixelToModel(col, row float64) GeoPoint {
+	x, y := t.Apply(col, row)
+	return GeoPoint{x, y}
+}
+
+// ModelToPixel returns the raster position of the model coordinates p.
+// ok is false if t is singular.
+func (t GeoTransform) ModelToPixel(p GeoPoint) (col, row float64, ok bool) {
+	inv, ok := t.Invert()
+	if !ok {
+		return
+	}
+	col, row = inv.Apply(p.X, p.Y)
+	return col, row, true
+}
+
+// Corners returns the model coordinates of the corners and the center of
+// a raster of width by height pixels.
+func (t GeoTransform) Corners(width, height int) GeoCorners {
+	w, h := float64(width), float64(height)
+	return GeoCorners{
+		UpperLeft:  t.PixelToModel(0, 0),
+		LowerLeft:  t.PixelToModel(0, h),
+		UpperRight: t.PixelToModel(w, 0),
+		LowerRight: t.PixelToModel(w, h),
+		Center:     t.PixelToModel(w/2, h/2),
+	}
+}
+
+// BoundingBox returns the smallest model space rectangle that contains a
+// raster of width by height pixels.
+func (t GeoTransform) BoundingBox(width, height int) (min, max GeoPoint) {
+	c := t.Corners(width, height)
+	min, max = c.UpperLeft, c.UpperLeft
+	for _, p := range []GeoPoint{c.LowerLeft, c.UpperRight, c.LowerRight} {
+		min.X, max.X = math.Min(min.X, p.X), math.Max(max.X, p.X)
+		min.Y, max.Y = math.Min(min.Y, p.Y), math.Max(max.Y, p.Y)
+	}
+	return
+}
+
+// ModelTags returns the values of the tags that store t for a raster of
+// the given type, the inverse of NewGeoTransform. Transforms without
+// rotation, with north up and east to the right are stored as a
+// ModelPixelScaleTag and a ModelTiepointTag, all others as a
+// ModelTransformationTag.
+func (t GeoTransform) ModelTags(raster GeoRasterType) (scale, tiepoints, matrix []float64) {
+	if raster == GeoRasterType_PixelIsPoint {
+		t[0] += 0.5*t[1] + 0.5*t[2]
+		t[3] += 0.5*t[4] + 0.5*t[5]
+	}
+	if t[1] > 0 && t[2] == 0 && t[4] == 0 && t[5] < 0 {
+		scale = []float64{t[1], -t[5], 0}
+		tiepoints = []float64{0, 0, 0, t[0], t[3], 0}
+		return
+	}
+	matrix = []float64{
+		t[1], t[2], 0, t[0],
+		t[4], t[5], 0, t[3],
+		0, 0, 0, 0,
+		0, 0, 0, 1,
+	}
+	return
+}
+
+// GeoTransform returns the geotransform of a GeoTIFF image.
+// See NewGeoTransform.
+func (p *IFD) GeoTransform() (t GeoTransform, ok bool) {
+	tags := p.TagGetter()
+	scale, _ := tags.GetModelPixelScaleTag()
+	tiepoints, _ := tags.GetModelTiepointTag()
+	matrix, _ := tags.GetModelTransformationTag()
+	raster := GeoRasterType_PixelIsArea
+	if d, err := p.GeoKeyDirectory(); err == nil && d != nil {
+		raster = d.RasterType()
+	}
+	return NewGeoTransform(scale, tiepoints, matrix, raster)
+}
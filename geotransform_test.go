@@ -0,0 +1,155 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bufio"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var listgeoCornerLine = regexp.MustCompile(`^(Upper Left|Lower Left|Upper Right|Lower Right|Center)\s+\(\s*(-?[0-9.]+),\s*(-?[0-9.]+)\)`)
+
+// readListgeoCorners returns the projected corner coordinates of a listgeo dump.
+func readListgeoCorners(t *testing.T, filename string) map[string]GeoPoint {
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	corners := make(map[string]GeoPoint)
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		m := listgeoCornerLine.FindStringSubmatch(s.Text())
+		if m == nil {
+			continue
+		}
+		x, _ := strconv.ParseFloat(m[2], 64)
+		y, _ := strconv.ParseFloat(m[3], 64)
+		corners[m[1]] = GeoPoint{x, y}
+	}
+	return corners
+}
+
+func TestGeoTransform_listgeo(t *testing.T) {
+	dumps, err := filepath.Glob(testdataDir + "geotiff/*/*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tested int
+	for _, dump := range dumps {
+		want := readListgeoCorners(t, dump)
+		name := strings.TrimSuffix(dump, ".txt") + ".tif"
+		if len(want) == 0 {
+			continue
+		}
+		if _, err := os.Stat(name); err != nil {
+			continue
+		}
+		p := openGeoTiff(t, name)
+		gt, ok := p.ImageGeoTransform(0, 0)
+		size := p.Ifd[0][0].Bounds().Size()
+		p.Close()
+		if !ok {
+			t.Fatalf("%s: no geotransform", name)
+		}
+		c := gt.Corners(size.X, size.Y)
+		for corner, got := range map[string]GeoPoint{
+			"Upper Left":  c.UpperLeft,
+			"Lower Left":  c.LowerLeft,
+			"Upper Right": c.UpperRight,
+			"Lower Right": c.LowerRight,
+			"Center":      c.Center,
+		} {
+			w, ok := want[corner]
+			if !ok {
+				continue
+			}
+			// listgeo prints three decimals.
+			if math.Abs(got.X-w.X) > 1e-3 || math.Abs(got.Y-w.Y) > 1e-3 {
+				t.Errorf("%s: %s = %v, want %v", name, corner, got, w)
+			}
+		}
+		tested++
+	}
+	if tested < 50 {
+		t.Fatalf("only %d listgeo dumps tested", tested)
+	}
+}
+
+func TestGeoTransform_roundtrip(t *testing.T) {
+	gt := GeoTransform{440720, 60, 0.5, 3751320, -0.25, -60}
+	for _, q := range [][2]float64{{0, 0}, {0.5, 0.5}, {17, 3}, {-4.25, 1e3}} {
+		col, row, ok := gt.ModelToPixel(gt.PixelToModel(q[0], q[1]))
+		if !ok || math.Abs(col-q[0]) > 1e-9 || math.Abs(row-q[1]) > 1e-6 {
+			t.Errorf("%v: got (%v, %v), %v", q, col, row, ok)
+		}
+	}
+	min, max := gt.BoundingBox(10, 20)
+	if want := (GeoPoint{440720, 3751320 - 20*60 - 10*0.25}); min != want {
+		t.Errorf("min = %v, want %v", min, want)
+	}
+	if want := (GeoPoint{440720 + 10*60 + 20*0.5, 3751320}); max != want {
+		t.Errorf("max = %v, want %v", max, want)
+	}
+}
+
+func TestNewGeoTransform(t *testing.T) {
+	scale := []float64{2, 3, 0}
+	tiepoints := []float64{10, 20, 0, 1000, 2000, 0}
+	gt, ok := NewGeoTransform(scale, tiepoints, nil, GeoRasterType_PixelIsArea)
+	if want := (GeoTransform{980, 2, 0, 2060, 0, -3}); !ok || gt != want {
+		t.Errorf("area = %v, %v, want %v", gt, ok, want)
+	}
+	gt, ok = NewGeoTransform(scale, tiepoints, nil, GeoRasterType_PixelIsPoint)
+	if want := (GeoTransform{979, 2, 0, 2061.5, 0, -3}); !ok || gt != want {
+		t.Errorf("point = %v, %v, want %v", gt, ok, want)
+	}
+	matrix := []float64{
+		2, 0.5, 0, 100,
+		0.25, -3, 0, 200,
+		0, 0, 0, 0,
+		0, 0, 0, 1,
+	}
+	gt, ok = NewGeoTransform(nil, nil, matrix, GeoRasterType_PixelIsArea)
+	if want := (GeoTransform{100, 2, 0.5, 200, 0.25, -3}); !ok || gt != want {
+		t.Errorf("matrix = %v, %v, want %v", gt, ok, want)
+	}
+	// Tiepoints that do not fit an affine transform.
+	tiepoints = []float64{
+		0, 0, 0, 0, 0, 0,
+		10, 0, 0, 10, 0, 0,
+		0, 10, 0, 0, -10, 0,
+		10, 10, 0, 50, -50, 0,
+	}
+	if gt, ok = NewGeoTransform(nil, tiepoints, nil, GeoRasterType_PixelIsArea); ok {
+		t.Errorf("bad tiepoints = %v, want !ok", gt)
+	}
+	if _, ok = NewGeoTransform(nil, nil, nil, GeoRasterType_PixelIsArea); ok {
+		t.Errorf("no tags: ok")
+	}
+}
+
+func TestGeoTransform_ModelTags(t *testing.T) {
+	for _, gt := range []GeoTransform{
+		{980, 2, 0, 2060, 0, -3},
+		{980, -2, 0, 2060, 0, -3}, // Flipped east to west.
+	} {
+		for _, raster := range []GeoRasterType{GeoRasterType_PixelIsArea, GeoRasterType_PixelIsPoint} {
+			scale, tiepoints, matrix := gt.ModelTags(raster)
+			if scale != nil && scale[0] <= 0 {
+				t.Errorf("%v, %v: scale %v", gt, raster, scale)
+			}
+			if got, ok := NewGeoTransform(scale, tiepoints, matrix, raster); !ok || got != gt {
+				t.Errorf("%v, %v: roundtrip = %v, %v", gt, raster, got, ok)
+			}
+		}
+	}
+}
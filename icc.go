@@ -0,0 +1,295 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// An ICCColorSpace is the signature of a color space of an ICC profile,
+// either its data color space or its profile connection space (PCS).
+type ICCColorSpace uint32
+
+const (
+	ICCColorSpace_XYZ   ICCColorSpace = 0x58595A20 // 'XYZ '
+	ICCColorSpace_Lab   ICCColorSpace = 0x4C616220 // 'Lab '
+	ICCColorSpace_Luv   ICCColorSpace = 0x4C757620 // 'Luv '
+	ICCColorSpace_YCbCr ICCColorSpace = 0x59436272 // 'YCbr'
+	ICCColorSpace_Yxy   ICCColorSpace = 0x59787920 // 'Yxy '
+	ICCColorSpace_RGB   ICCColorSpace = 0x52474220 // 'RGB '
+	ICCColorSpace_Gray  ICCColorSpace = 0x47524159 // 'GRAY'
+	ICCColorSpace_HSV   ICCColorSpace = 0x48535620 // 'HSV '
+	ICCColorSpace_HLS   ICCColorSpace = 0x484C5320 // 'HLS '
+	ICCColorSpace_CMYK  ICCColorSpace = 0x434D594B // 'CMYK'
+	ICCColorSpace_CMY   ICCColorSpace = 0x434D5920 // 'CMY '
+)
+
+var _ICCColorSpaceTable = map[ICCColorSpace]string{
+	ICCColorSpace_XYZ:   `ICCColorSpace_XYZ`,
+	ICCColorSpace_Lab:   `ICCColorSpace_Lab`,
+	ICCColorSpace_Luv:   `ICCColorSpace_Luv`,
+	ICCColorSpace_YCbCr: `ICCColorSpace_YCbCr`,
+	ICCColorSpace_Yxy:   `ICCColorSpace_Yxy`,
+	ICCColorSpace_RGB:   `ICCColorSpace_RGB`,
+	ICCColorSpace_Gray:  `ICCColorSpace_Gray`,
+	ICCColorSpace_HSV:   `ICCColorSpace_HSV`,
+	ICCColorSpace_HLS:   `ICCColorSpace_HLS`,
+	ICCColorSpace_CMYK:  `ICCColorSpace_CMYK`,
+	ICCColorSpace_CMY:   `ICCColorSpace_CMY`,
+}
+
+func (p ICCColorSpace) String() string {
+	if name, ok := _ICCColorSpaceTable[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("ICCColorSpace_Unknown(%q)", iccSignature(uint32(p)))
+}
+
+// An ICCProfileClass is the device class of an ICC profile.
+type ICCProfileClass uint32
+
+const (
+	ICCProfileClass_Input      ICCProfileClass = 0x73636E72 // 'scnr'
+	ICCProfileClass_Display    ICCProfileClass = 0x6D6E7472 // 'mntr'
+	ICCProfileClass_Output     ICCProfileClass = 0x70727472 // 'prtr'
+	ICCProfileClass_DeviceLink ICCProfileClass = 0x6C696E6B // 'link'
+	ICCProfileClass_ColorSpace ICCProfileClass = 0x73706163 // 'spac'
+	ICCProfileClass_Abstract   ICCProfileClass = 0x61627374 // 'abst'
+	ICCProfileClass_NamedColor ICCProfileClass = 0x6E6D636C // 'nmcl'
+)
+
+var _ICCProfileClassTable = map[ICCProfileClass]string{
+	ICCProfileClass_Input:      `ICCProfileClass_Input`,
+	ICCProfileClass_Display:    `ICCProfileClass_Display`,
+	ICCProfileClass_Output:     `ICCProfileClass_Output`,
+	ICCProfileClass_DeviceLink: `ICCProfileClass_DeviceLink`,
+	ICCProfileClass_ColorSpace: `ICCProfileClass_ColorSpace`,
+	ICCProfileClass_Abstract:   `ICCProfileClass_Abstract`,
+	ICCProfileClass_NamedColor: `ICCProfileClass_NamedColor`,
+}
+
+func (p ICCProfileClass) String() string {
+	if name, ok := _ICCProfileClassTable[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("ICCProfileClass_Unknown(%q)", iccSignature(uint32(p)))
+}
+
+// An ICCRenderingIntent is the rendering intent of an ICC profile.
+type ICCRenderingIntent uint32
+
+const (
+	ICCRenderingIntent_Perceptual           ICCRenderingIntent = 0
+	ICCRenderingIntent_RelativeColorimetric ICCRenderingIntent = 1
+	ICCRenderingIntent_Saturation           ICCRenderingIntent = 2
+	ICCRenderingIntent_AbsoluteColorimetric ICCRenderingIntent = 3
+)
+
+var _ICCRenderingIntentTable = map[ICCRenderingIntent]string{
+	ICCRenderingIntent_Perceptual:           `ICCRenderingIntent_Perceptual`,
+	ICCRenderingIntent_RelativeColorimetric: `ICCRenderingIntent_RelativeColorimetric`,
+	ICCRenderingIntent_Saturation:           `ICCRenderingIntent_Saturation`,
+	ICCRenderingIntent_AbsoluteColorimetric: `ICCRenderingIntent_AbsoluteColorimetric`,
+}
+
+func (p ICCRenderingIntent) String() string {
+	if name, ok := _ICCRenderingIntentTable[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("ICCRenderingIntent_Unknown(%d)", uint32(p))
+}
+
+// iccSignature returns the four characters of an ICC signature.
+func iccSignature(v uint32) string {
+	return string([]byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)})
+}
+
+// An ICCProfile is an ICC color profile with its parsed header.
+//
+// Only the header and the description and copyright tags are parsed,
+// the transforms of the profile are left to a color management system.
+type ICCProfile struct {
+	Data []byte // The whole profile, as embedded in the file.
+
+	Version         string // Such as "2.1.0" or "4.3.0".
+	DeviceClass     ICCProfileClass
+	ColorSpace      ICCColorSpace // The color space of the image data.
+	PCS             ICCColorSpace // The profile connection space, XYZ or Lab.
+	Created         time.Time
+	RenderingIntent ICCRenderingIntent
+
+	Description string // The 'desc' tag.
+	Copyright   string // The 'cprt' tag.
+}
+
+const (
+	iccHeaderSize = 128
+	iccMagic      = 0x61637370 // 'acsp'
+	iccTagDesc    = 0x64657363 // 'desc'
+	iccTagCprt    = 0x63707274 // 'cprt'
+	iccTypeDesc   = 0x64657363 // 'desc', textDescriptionType of ICC v2
+	iccTypeText   = 0x74657874 // 'text'
+	iccTypeMluc   = 0x6D6C7563 // 'mluc', multiLocalizedUnicodeType of ICC v4
+)
+
+// ParseICCProfile parses the header and the tag table of an ICC profile.
+// Unreadable description and copyright tags are left empty.
+func ParseICCProfile(data []byte) (*ICCProfile, error) {
+	be := binary.BigEndian
+	if len(data) < iccHeaderSize+4 {
+		return nil, fmt.Errorf("tiff: ParseICCProfile, profile too short (%d bytes)", len(data))
+	}
+	if be.Uint32(data[36:]) != iccMagic {
+		return nil, fmt.Errorf("tiff: ParseICCProfile, bad signature %q", data[36:40])
+	}
+	if size := be.Uint32(data[0:]); size > uint32(len(data)) {
+		return nil, fmt.Errorf("tiff: ParseICCProfile, profile size %d exceeds %d bytes", size, len(data))
+	}
+	p := &ICCProfile{
+		Data:            data,
+		Version:         fmt.Sprintf("%d.%d.%d", data[8], data[9]>>4, data[9]&0x0f),
+		DeviceClass:     ICCProfileClass(be.Uint32(data[12:])),
+		ColorSpace:      ICCColorSpace(be.Uint32(data[16:])),
+		PCS:             ICCColorSpace(be.Uint32(data[20:])),
+		RenderingIntent: ICCRenderingIntent(be.Uint32(data[64:])),
+	}
+	var d [6]int
+	for k := range d {
+		d[k] = int(be.Uint16(data[24+2*k:]))
+	}
+	if d[0] != 0 {
+		p.Created = time.Date(d[0], time.Month(d[1]), d[2], d[3], d[4], d[5], 0, time.UTC)
+	}
+
+	n := be.Uint32(data[iccHeaderSize:])
+	if uint64(n)*12 > uint64(len(data)-iccHeaderSize-4) {
+		return nil, fmt.Errorf("tiff: ParseICCProfile, bad tag count %d", n)
+	}
+	for k := 0; k < int(n); k++ {
+		t := data[iccHeaderSize+4+12*k:]
+		sig, offset, size := be.Uint32(t[0:]), be.Uint32(t[4:]), be.Uint32(t[8:])
+		if uint64(offset)+uint64(size) > uint64(len(data)) {
+			return nil, fmt.Errorf("tiff: ParseICCProfile, tag %q out of range", iccSignature(sig))
+		}
+		switch sig {
+		case iccTagDesc:
+			p.Description = iccText(data[offset : offset+size])
+		case iccTagCprt:
+			p.Copyright = iccText(data[offset : offset+size])
+		}
+	}
+	return p, nil
+}
+
+// iccText returns the string of a 'desc', 'text' or 'mluc' tag. For an
+// 'mluc' tag, it is the English record, or the first one if there is none.
+func iccText(b []byte) string {
+	be := binary.BigEndian
+	if len(b) < 12 {
+		return ""
+	}
+	var s []byte
+	switch be.Uint32(b) {
+	case iccTypeText:
+		s = b[8:]
+	case iccTypeDesc:
+		if n := be.Uint32(b[8:]); uint64(n) <= uint64(len(b)-12) {
+			s = b[12 : 12+n]
+		}
+	case iccTypeMluc:
+		if len(b) < 16 {
+			return ""
+		}
+		n, recordSize := be.Uint32(b[8:]), be.Uint32(b[12:])
+		if recordSize < 12 || uint64(n)*uint64(recordSize) > uint64(len(b)-16) {
+			return ""
+		}
+		var text []byte
+		for k := 0; k < int(n); k++ {
+			r := b[16+k*int(recordSize):]
+			length, offset := be.Uint32(r[4:]), be.Uint32(r[8:])
+			if uint64(offset)+uint64(length) > uint64(len(b)) {
+				continue
+			}
+			if text == nil || string(r[:2]) == "en" {
+				text = b[offset : offset+length]
+			}
+			if string(r[:2]) == "en" {
+				break
+			}
+		}
+		u := make([]uint16, len(text)/2)
+		for k := range u {
+			u[k] = be.Uint16(text[2*k:])
+		}
+		return strings.TrimRight(string(utf16.Decode(u)), "\x00")
+	}
+	if i := strings.IndexByte(string(s), 0); i >= 0 {
+		s = s[:i]
+	}
+	return string(s)
+}
+
+// ICCProfileData returns the raw ICC profile of the ICCProfile tag.
+func (p *IFD) ICCProfileData() ([]byte, bool) {
+	return p.TagGetter().GetICCProfile()
+}
+
+// ICCProfile returns the parsed ICC profile. It returns nil and no error
+// if the image has no ICCProfile tag.
+func (p *IFD) ICCProfile() (*ICCProfile, error) {
+	data, ok := p.ICCProfileData()
+	if !ok {
+		return nil, nil
+	}
+	return ParseICCProfile(data)
+}
+
+// UpdateICCProfile replaces the ICC profile of the i-th image of the file
+// f, or adds one if it has none. A nil profile removes the ICCProfile tag.
+// The color space of the profile must match the photometric
+// interpretation of the image, as for Options.ICCProfile. The file is
+// edited as by UpdateXMP.
+func UpdateICCProfile(f io.ReadWriteSeeker, i int, profile []byte) error {
+	if profile == nil {
+		return editIFD(f, i, nil, TagType_ICCProfile)
+	}
+	icc, err := ParseICCProfile(profile)
+	if err != nil {
+		return err
+	}
+	_, ifd, _, err := findIFD(f, i)
+	if err != nil {
+		return err
+	}
+	photometric, _ := ifd.TagGetter().GetPhotometricInterpretation()
+	if cs, ok := iccColorSpace(photometric); !ok || cs != icc.ColorSpace {
+		return fmt.Errorf("tiff: UpdateICCProfile, %v ICC profile for a %v image", icc.ColorSpace, photometric)
+	}
+	return editIFD(f, i, []ifdEntry{{TagType_ICCProfile, DataType_Undefined, bytesData(profile)}})
+}
+
+// iccColorSpace returns the color space an ICC profile must have to
+// describe the samples of the given photometric interpretation.
+func iccColorSpace(photometric TagValue_PhotometricType) (ICCColorSpace, bool) {
+	switch photometric {
+	case TagValue_PhotometricType_RGB, TagValue_PhotometricType_Paletted:
+		return ICCColorSpace_RGB, true
+	case TagValue_PhotometricType_BlackIsZero, TagValue_PhotometricType_WhiteIsZero:
+		return ICCColorSpace_Gray, true
+	case TagValue_PhotometricType_CMYK:
+		return ICCColorSpace_CMYK, true
+	case TagValue_PhotometricType_YCbCr:
+		return ICCColorSpace_YCbCr, true
+	case TagValue_PhotometricType_CIELab:
+		return ICCColorSpace_Lab, true
+	}
+	return 0, false
+}
@@ -0,0 +1,170 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"io/ioutil"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+// testICCProfile builds a minimal ICC profile with a description and a
+// copyright tag, of the ICC v2 or v4 tag types.
+func testICCProfile(cs ICCColorSpace, v4 bool, desc, cprt string) []byte {
+	be := binary.BigEndian
+	var tags [][]byte
+	if v4 {
+		for _, s := range []string{desc, cprt} {
+			u := utf16.Encode([]rune(s))
+			b := make([]byte, 28+2*len(u))
+			copy(b, "mluc")
+			be.PutUint32(b[8:], 1)
+			be.PutUint32(b[12:], 12)
+			copy(b[16:], "enUS")
+			be.PutUint32(b[20:], uint32(2*len(u)))
+			be.PutUint32(b[24:], 28)
+			for k, c := range u {
+				be.PutUint16(b[28+2*k:], c)
+			}
+			tags = append(tags, b)
+		}
+	} else {
+		b := make([]byte, 12+len(desc)+1+67+12)
+		copy(b, "desc")
+		be.PutUint32(b[8:], uint32(len(desc)+1))
+		copy(b[12:], desc)
+		tags = append(tags, b, append([]byte("text\x00\x00\x00\x00"), cprt+"\x00"...))
+	}
+
+	data := make([]byte, 128+4+12*len(tags))
+	be.PutUint32(data[128:], uint32(len(tags)))
+	for k, sig := range []string{"desc", "cprt"} {
+		for len(data)%4 != 0 {
+			data = append(data, 0)
+		}
+		e := data[132+12*k:]
+		copy(e, sig)
+		be.PutUint32(e[4:], uint32(len(data)))
+		be.PutUint32(e[8:], uint32(len(tags[k])))
+		data = append(data, tags[k]...)
+	}
+	be.PutUint32(data[0:], uint32(len(data)))
+	copy(data[4:], "none")
+	data[8], data[9] = 2, 0x10
+	if v4 {
+		data[8], data[9] = 4, 0x30
+	}
+	copy(data[12:], "mntr")
+	be.PutUint32(data[16:], uint32(cs))
+	copy(data[20:], "XYZ ")
+	for k, v := range []uint16{2015, 6, 1, 12, 30, 0} {
+		be.PutUint16(data[24+2*k:], v)
+	}
+	copy(data[36:], "acsp")
+	be.PutUint32(data[64:], uint32(ICCRenderingIntent_RelativeColorimetric))
+	return data
+}
+
+func TestParseICCProfile(t *testing.T) {
+	for _, v4 := range []bool{false, true} {
+		data := testICCProfile(ICCColorSpace_RGB, v4, "sRGB IEC61966-2.1", "No copyright")
+		p, err := ParseICCProfile(data)
+		if err != nil {
+			t.Fatalf("v4=%v: %v", v4, err)
+		}
+		version := "2.1.0"
+		if v4 {
+			version = "4.3.0"
+		}
+		if p.Version != version {
+			t.Errorf("v4=%v: Version = %q", v4, p.Version)
+		}
+		if p.DeviceClass != ICCProfileClass_Display || p.ColorSpace != ICCColorSpace_RGB || p.PCS != ICCColorSpace_XYZ {
+			t.Errorf("v4=%v: class %v, color space %v, PCS %v", v4, p.DeviceClass, p.ColorSpace, p.PCS)
+		}
+		if p.RenderingIntent != ICCRenderingIntent_RelativeColorimetric {
+			t.Errorf("v4=%v: RenderingIntent = %v", v4, p.RenderingIntent)
+		}
+		if !p.Created.Equal(time.Date(2015, 6, 1, 12, 30, 0, 0, time.UTC)) {
+			t.Errorf("v4=%v: Created = %v", v4, p.Created)
+		}
+		if p.Description != "sRGB IEC61966-2.1" || p.Copyright != "No copyright" {
+			t.Errorf("v4=%v: Description = %q, Copyright = %q", v4, p.Description, p.Copyright)
+		}
+	}
+
+	data := testICCProfile(ICCColorSpace_Gray, false, "Gray", "")
+	if s := ICCColorSpace(0x61626364).String(); s != `ICCColorSpace_Unknown("abcd")` {
+		t.Errorf("unknown color space = %s", s)
+	}
+	bad := map[string][]byte{
+		"short":     data[:100],
+		"signature": append(append([]byte{}, data[:36]...), append([]byte("xxxx"), data[40:]...)...),
+		"size":      data[:len(data)-1],
+		"tag count": append(append([]byte{}, data[:128]...), 0, 0, 1, 0),
+	}
+	for name, b := range bad {
+		if _, err := ParseICCProfile(b); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
+
+func TestICCProfile_encode(t *testing.T) {
+	profile := testICCProfile(ICCColorSpace_Gray, true, "Gray Gamma 2.2", "")
+	m := image.NewGray(image.Rect(0, 0, 8, 8))
+	var out bytes.Buffer
+	if err := Encode(&out, m, &Options{ICCProfile: profile}); err != nil {
+		t.Fatal(err)
+	}
+	p, err := OpenReader(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	got, err := p.ImageICCProfile(0, 0)
+	if err != nil || got == nil {
+		t.Fatalf("ICCProfile = %v, %v", got, err)
+	}
+	if !bytes.Equal(got.Data, profile) || got.Description != "Gray Gamma 2.2" {
+		t.Errorf("ICCProfile = %q, %d bytes", got.Description, len(got.Data))
+	}
+
+	rgb := testICCProfile(ICCColorSpace_RGB, false, "RGB", "")
+	if err := Encode(ioutil.Discard, m, &Options{ICCProfile: rgb}); err == nil {
+		t.Errorf("RGB profile for a gray image: no error")
+	}
+	if err := Encode(ioutil.Discard, m, &Options{ICCProfile: []byte("bad")}); err == nil {
+		t.Errorf("bad profile: no error")
+	}
+}
+
+func TestUpdateICCProfile(t *testing.T) {
+	var out bytes.Buffer
+	if err := Encode(&out, image.NewRGBA(image.Rect(0, 0, 8, 8)), nil); err != nil {
+		t.Fatal(err)
+	}
+	f := &memFile{data: out.Bytes()}
+	if err := UpdateICCProfile(f, 0, []byte("bad")); err == nil {
+		t.Errorf("bad profile: no error")
+	}
+	if err := UpdateICCProfile(f, 0, testICCProfile(ICCColorSpace_Gray, true, "Gray", "")); err == nil {
+		t.Errorf("gray profile for an RGB image: no error")
+	}
+	profile := testICCProfile(ICCColorSpace_RGB, false, "Display", "")
+	if err := UpdateICCProfile(f, 0, profile); err != nil {
+		t.Fatal(err)
+	}
+
+	p := f.open(t)
+	defer p.Close()
+	if got, ok := p.Ifd[0][0].ICCProfileData(); !ok || !bytes.Equal(got, profile) {
+		t.Errorf("ICCProfileData = %d bytes, %v", len(got), ok)
+	}
+}
@@ -10,6 +10,9 @@ import (
 )
 
 func newImageWithIFD(r image.Rectangle, ifd *IFD) (m image.Image, err error) {
+	if err = ifd.opt.checkPixels(r); err != nil {
+		return
+	}
 	switch ifd.ImageType() {
 	case ImageType_Bilevel, ImageType_BilevelInvert:
 		m = image.NewGray(r)
@@ -40,3 +43,17 @@ func newImageWithIFD(r image.Rectangle, ifd *IFD) (m image.Image, err error) {
 	}
 	return
 }
+
+// copyPixels copies the rectangle r of src into dst, which are images of
+// the same type returned by newImageWithIFD.
+func copyPixels(dst, src image.Image, r image.Rectangle) {
+	dstPix, dstStride, size, _ := pixLayout(dst)
+	srcPix, srcStride, _, _ := pixLayout(src)
+	db, sb := dst.Bounds(), src.Bounds()
+	n := r.Dx() * size
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		d := (y-db.Min.Y)*dstStride + (r.Min.X-db.Min.X)*size
+		s := (y-sb.Min.Y)*srcStride + (r.Min.X-sb.Min.X)*size
+		copy(dstPix[d:d+n], srcPix[s:s+n])
+	}
+}
@@ -0,0 +1,372 @@
+// Package ljpeg supports lossless JPEG images, the process 14 of
+// ITU-T Recommendation T.81, as used by the raw data of DNG files.
+// See https://www.w3.org/Graphics/JPEG/itu-t81.pdf
+package ljpeg
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Markers of T.81 Table B.1.
+const (
+	markerSOF3 = 0xC3 // Start of frame, lossless, Huffman coding.
+	markerDHT  = 0xC4 // Define Huffman tables.
+	markerRST0 = 0xD0 // Restart markers are RST0 through RST7.
+	markerRST7 = 0xD7
+	markerSOI  = 0xD8 // Start of image.
+	markerEOI  = 0xD9 // End of image.
+	markerSOS  = 0xDA // Start of scan.
+	markerDRI  = 0xDD // Define restart interval.
+)
+
+var errTruncated = errors.New("ljpeg: truncated data")
+
+// An Image is a decoded lossless JPEG image. Its samples are stored in
+// Pix row by row, with the components of each column interleaved.
+type Image struct {
+	Width, Height int
+	Components    int
+	Precision     int // The number of bits of a sample, 2 to 16.
+	Pix           []uint16
+}
+
+// huffman is a Huffman table, in the form of T.81 Figure F.15.
+type huffman struct {
+	maxCode [17]int32 // The largest code of each length, or -1.
+	valPtr  [17]int32 // The index in values of the first code of each length.
+	minCode [17]int32
+	values  []uint8
+}
+
+func newHuffman(counts [16]uint8, values []uint8) *huffman {
+	h := &huffman{values: values}
+	code, k := int32(0), int32(0)
+	for l := 1; l <= 16; l++ {
+		n := int32(counts[l-1])
+		h.valPtr[l], h.minCode[l] = k, code
+		code += n
+		k += n
+		h.maxCode[l] = code - 1
+		if n == 0 {
+			h.maxCode[l] = -1
+		}
+		code <<= 1
+	}
+	return h
+}
+
+type component struct {
+	id    uint8
+	table *huffman
+}
+
+type decoder struct {
+	data       []byte
+	off        int
+	precision  int
+	width      int
+	height     int
+	components []component
+	huffman    [4]*huffman
+	restart    int // The restart interval in columns, a multiple of the width, or 0.
+
+	// The entropy coded data is read through bits, nbits of which are
+	// valid. A marker ends the data until readRestart is called.
+	bits   uint32
+	nbits  uint
+	marker bool
+}
+
+// Decode decodes the lossless JPEG image in data. Only images with a
+// single interleaved scan whose components are not subsampled, as
+// written by DNG encoders, are supported.
+func Decode(data []byte) (*Image, error) {
+	return decode(data, false)
+}
+
+// DecodeConfig returns the size, components and precision of the lossless
+// JPEG image in data, with a nil Pix, without decoding the samples.
+func DecodeConfig(data []byte) (*Image, error) {
+	return decode(data, true)
+}
+
+func decode(data []byte, config bool) (*Image, error) {
+	d := &decoder{data: data}
+	if len(data) < 2 || data[0] != 0xff || data[1] != markerSOI {
+		return nil, errors.New("ljpeg: missing SOI marker")
+	}
+	d.off = 2
+	for {
+		marker, segment, err := d.readSegment()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case marker == markerSOF3:
+			if err = d.parseSOF(segment); err != nil {
+				return nil, err
+			}
+			if config {
+				return &Image{Width: d.width, Height: d.height, Components: len(d.components), Precision: d.precision}, nil
+			}
+		case marker == markerDHT:
+			if err = d.parseDHT(segment); err != nil {
+				return nil, err
+			}
+		case marker == markerDRI:
+			if len(segment) != 2 {
+				return nil, errors.New("ljpeg: bad DRI segment")
+			}
+			d.restart = int(segment[0])<<8 | int(segment[1])
+		case marker == markerSOS:
+			return d.decodeScan(segment)
+		case marker == markerEOI:
+			return nil, errors.New("ljpeg: missing SOS marker")
+		case marker >= 0xC0 && marker <= 0xCF && marker != 0xC4 && marker != 0xC8 && marker != 0xCC:
+			return nil, fmt.Errorf("ljpeg: unsupported SOF%d frame, not lossless Huffman", marker-0xC0)
+		}
+	}
+}
+
+// readSegment returns the next marker and the data of its segment.
+func (d *decoder) readSegment() (marker uint8, segment []byte, err error) {
+	// Fill bytes may precede a marker.
+	for d.off < len(d.data) && d.data[d.off] == 0xff {
+		d.off++
+	}
+	if d.off < 2 || d.data[d.off-1] != 0xff || d.off+2 >= len(d.data) {
+		return 0, nil, errTruncated
+	}
+	marker = d.data[d.off]
+	n := int(d.data[d.off+1])<<8 | int(d.data[d.off+2])
+	if n < 2 || d.off+1+n > len(d.data) {
+		return 0, nil, errTruncated
+	}
+	segment = d.data[d.off+3 : d.off+1+n]
+	d.off += 1 + n
+	return marker, segment, nil
+}
+
+func (d *decoder) parseSOF(b []byte) error {
+	if len(b) < 6 || len(b) != 6+3*int(b[5]) {
+		return errors.New("ljpeg: bad SOF3 segment")
+	}
+	d.precision = int(b[0])
+	d.height = int(b[1])<<8 | int(b[2])
+	d.width = int(b[3])<<8 | int(b[4])
+	if d.precision < 2 || d.precision > 16 || d.width == 0 || d.height == 0 || b[5] == 0 {
+		return fmt.Errorf("ljpeg: bad frame, %d bits, %dx%d", d.precision, d.width, d.height)
+	}
+	d.components = make([]component, b[5])
+	for i := range d.components {
+		c := b[6+3*i:]
+		if c[1] != 0x11 {
+			return errors.New("ljpeg: subsampled components are not supported")
+		}
+		d.components[i].id = c[0]
+	}
+	return nil
+}
+
+func (d *decoder) parseDHT(b []byte) error {
+	for len(b) > 0 {
+		if len(b) < 17 || b[0]>>4 != 0 || b[0]&0x0f > 3 {
+			return errors.New("ljpeg: bad DHT segment")
+		}
+		var counts [16]uint8
+		n := 0
+		for i := range counts {
+			counts[i] = b[1+i]
+			n += int(counts[i])
+		}
+		if n > 256 || len(b) < 17+n {
+			return errors.New("ljpeg: bad DHT segment")
+		}
+		d.huffman[b[0]&0x0f] = newHuffman(counts, b[17:17+n])
+		b = b[17+n:]
+	}
+	return nil
+}
+
+func (d *decoder) decodeScan(b []byte) (*Image, error) {
+	if d.components == nil {
+		return nil, errors.New("ljpeg: missing SOF3 marker")
+	}
+	if len(b) < 1 || len(b) != 4+2*int(b[0]) || int(b[0]) != len(d.components) {
+		return nil, errors.New("ljpeg: only single interleaved scans are supported")
+	}
+	for i := range d.components {
+		c := &d.components[i]
+		if b[1+2*i] != c.id {
+			return nil, errors.New("ljpeg: scan components differ from the frame")
+		}
+		if c.table = d.huffman[b[2+2*i]>>4&3]; c.table == nil {
+			return nil, errors.New("ljpeg: missing Huffman table")
+		}
+	}
+	tail := b[1+2*len(d.components):]
+	predictor, pt := int(tail[0]), uint(tail[2]&0x0f)
+	if predictor < 1 || predictor > 7 || int(pt) >= d.precision {
+		return nil, fmt.Errorf("ljpeg: bad predictor %d or point transform %d", predictor, pt)
+	}
+
+	// Every sample takes at least one bit, which bounds the size of the
+	// image by that of the data.
+	nc := len(d.components)
+	if d.width*d.height*nc > 8*len(d.data) {
+		return nil, errTruncated
+	}
+	m := &Image{
+		Width:      d.width,
+		Height:     d.height,
+		Components: nc,
+		Precision:  d.precision,
+		Pix:        make([]uint16, d.width*d.height*nc),
+	}
+	stride := d.width * nc
+	initial := int32(1) << uint(d.precision-int(pt)-1)
+	first := true // The first row of the image or after a restart.
+	left := d.restart
+	for y := 0; y < d.height; y++ {
+		row := m.Pix[y*stride : (y+1)*stride]
+		for x := 0; x < d.width; x++ {
+			if d.restart > 0 && left == 0 {
+				if err := d.readRestart(); err != nil {
+					return nil, err
+				}
+				left, first = d.restart, true
+			}
+			for i := range d.components {
+				k := x*nc + i
+				var pred int32
+				switch {
+				case first && x == 0:
+					pred = initial
+				case first:
+					pred = int32(row[k-nc])
+				case x == 0:
+					pred = int32(m.Pix[(y-1)*stride+k])
+				default:
+					ra, rb, rc := int32(row[k-nc]), int32(m.Pix[(y-1)*stride+k]), int32(m.Pix[(y-1)*stride+k-nc])
+					switch predictor {
+					case 1:
+						pred = ra
+					case 2:
+						pred = rb
+					case 3:
+						pred = rc
+					case 4:
+						pred = ra + rb - rc
+					case 5:
+						pred = ra + (rb-rc)>>1
+					case 6:
+						pred = rb + (ra-rc)>>1
+					case 7:
+						pred = (ra + rb) >> 1
+					}
+				}
+				diff, err := d.decodeDiff(d.components[i].table)
+				if err != nil {
+					return nil, err
+				}
+				row[k] = uint16(pred + diff)
+			}
+			left--
+		}
+		first = false
+	}
+	if pt > 0 {
+		for i, v := range m.Pix {
+			m.Pix[i] = v << pt
+		}
+	}
+	return m, nil
+}
+
+// decodeDiff decodes the difference of a sample and its prediction.
+func (d *decoder) decodeDiff(h *huffman) (int32, error) {
+	code, l := int32(0), 1
+	for ; ; l++ {
+		if l > 16 {
+			return 0, errors.New("ljpeg: bad Huffman code")
+		}
+		bit, err := d.readBits(1)
+		if err != nil {
+			return 0, err
+		}
+		code = code<<1 | int32(bit)
+		if code <= h.maxCode[l] {
+			break
+		}
+	}
+	k := h.valPtr[l] + code - h.minCode[l]
+	if int(k) >= len(h.values) {
+		return 0, errors.New("ljpeg: bad Huffman code")
+	}
+	ssss := uint(h.values[k])
+	switch {
+	case ssss == 0:
+		return 0, nil
+	case ssss == 16:
+		return 32768, nil
+	case ssss > 16:
+		return 0, errors.New("ljpeg: bad difference category")
+	}
+	v, err := d.readBits(ssss)
+	if err != nil {
+		return 0, err
+	}
+	diff := int32(v)
+	if diff < 1<<(ssss-1) {
+		diff -= 1<<ssss - 1
+	}
+	return diff, nil
+}
+
+// readBits reads n bits of the entropy coded data, undoing byte stuffing.
+func (d *decoder) readBits(n uint) (uint32, error) {
+	for d.nbits < n {
+		var c byte
+		if !d.marker {
+			if d.off >= len(d.data) {
+				return 0, errTruncated
+			}
+			c = d.data[d.off]
+			if c == 0xff {
+				if d.off+1 < len(d.data) && d.data[d.off+1] == 0 {
+					d.off++
+				} else {
+					// A marker ends the data: pad with ones, as the
+					// encoder pads the last byte.
+					d.marker, c = true, 0xff
+					d.off--
+				}
+			}
+			d.off++
+		} else {
+			c = 0xff
+		}
+		d.bits = d.bits<<8 | uint32(c)
+		d.nbits += 8
+	}
+	d.nbits -= n
+	v := d.bits >> d.nbits & (1<<n - 1)
+	return v, nil
+}
+
+// readRestart discards the remaining bits and reads a RST marker.
+func (d *decoder) readRestart() error {
+	d.bits, d.nbits, d.marker = 0, 0, false
+	for d.off < len(d.data) && d.data[d.off] != 0xff {
+		d.off++
+	}
+	for d.off < len(d.data) && d.data[d.off] == 0xff {
+		d.off++
+	}
+	if d.off >= len(d.data) || d.data[d.off] < markerRST0 || d.data[d.off] > markerRST7 {
+		return errors.New("ljpeg: missing RST marker")
+	}
+	d.off++
+	return nil
+}
@@ -0,0 +1,112 @@
+package ljpeg
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func testImage(width, height, components, precision int) *Image {
+	m := &Image{Width: width, Height: height, Components: components, Precision: precision}
+	m.Pix = make([]uint16, width*height*components)
+	rnd := rand.New(rand.NewSource(1))
+	max := 1 << uint(precision)
+	for i := range m.Pix {
+		// A smooth gradient with noise, and some extreme values.
+		v := (i/components%width)*max/width/2 + rnd.Intn(max/8+1)
+		if i%97 == 0 {
+			v = max - 1
+		}
+		m.Pix[i] = uint16(v % max)
+	}
+	return m
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for _, tt := range []struct{ width, height, components, precision int }{
+		{16, 8, 1, 8},
+		{33, 17, 2, 12},
+		{20, 20, 3, 14},
+		{7, 5, 1, 16},
+		{1, 1, 1, 16},
+	} {
+		m := testImage(tt.width, tt.height, tt.components, tt.precision)
+		for predictor := 1; predictor <= 7; predictor++ {
+			data, err := Encode(m, predictor)
+			if err != nil {
+				t.Fatalf("%+v, predictor %d: %v", tt, predictor, err)
+			}
+			got, err := Decode(data)
+			if err != nil {
+				t.Fatalf("%+v, predictor %d: %v", tt, predictor, err)
+			}
+			if !reflect.DeepEqual(got, m) {
+				t.Errorf("%+v, predictor %d: roundtrip differs", tt, predictor)
+			}
+			cfg, err := DecodeConfig(data)
+			if err != nil || cfg.Width != m.Width || cfg.Height != m.Height || cfg.Components != m.Components || cfg.Precision != m.Precision || cfg.Pix != nil {
+				t.Errorf("%+v, predictor %d: DecodeConfig = %+v, %v", tt, predictor, cfg, err)
+			}
+		}
+	}
+}
+
+// joinRestart returns the single row image a, encoded by Encode, twice
+// over: two rows joined by a restart marker.
+func joinRestart(a []byte, width int) []byte {
+	sofLen := int(a[4])<<8 | int(a[5])
+	sof := append([]byte{}, a[2:4+sofLen]...)
+	sof[6] = 2 // The low byte of the height.
+	rest := a[4+sofLen:]
+	sos := bytes.Index(rest, []byte{0xff, markerSOS})
+	sosLen := int(rest[sos+2])<<8 | int(rest[sos+3])
+	entropy := rest[sos+2+sosLen : len(rest)-2]
+
+	data := []byte{0xff, markerSOI, 0xff, markerDRI, 0, 4, uint8(width >> 8), uint8(width)}
+	data = append(data, sof...)
+	data = append(data, rest[:sos+2+sosLen]...)
+	data = append(data, entropy...)
+	data = append(data, 0xff, markerRST0)
+	data = append(data, entropy...)
+	return append(data, 0xff, markerEOI)
+}
+
+func TestDecodeRestart(t *testing.T) {
+	// The row after the restart marker is predicted like the first one.
+	row := testImage(9, 1, 2, 12)
+	a, err := Encode(row, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := Decode(joinRestart(a, row.Width))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(row.Pix); m.Height != 2 || !reflect.DeepEqual(m.Pix[:n], row.Pix) || !reflect.DeepEqual(m.Pix[n:], row.Pix) {
+		t.Errorf("restart: got %v, want %v twice", m.Pix, row.Pix)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	m := testImage(8, 8, 1, 8)
+	data, err := Encode(m, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseline := append([]byte{}, data...)
+	baseline[3] = 0xC0
+	for name, b := range map[string][]byte{
+		"empty":     nil,
+		"no SOI":    data[2:],
+		"truncated": data[:len(data)/2],
+		"baseline":  baseline,
+	} {
+		if _, err := Decode(b); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+	if _, err := Encode(m, 8); err == nil {
+		t.Errorf("predictor 8: no error")
+	}
+}
@@ -0,0 +1,158 @@
+package ljpeg
+
+import (
+	"errors"
+	"sort"
+)
+
+// Encode encodes m as a lossless JPEG image with the given predictor,
+// 1 through 7 of T.81 Table H.1. The Huffman table is fitted to the
+// differences of m.
+func Encode(m *Image, predictor int) ([]byte, error) {
+	nc := m.Components
+	switch {
+	case predictor < 1 || predictor > 7:
+		return nil, errors.New("ljpeg: bad predictor")
+	case m.Precision < 2 || m.Precision > 16:
+		return nil, errors.New("ljpeg: bad precision")
+	case m.Width <= 0 || m.Height <= 0 || m.Width > 0xffff || m.Height > 0xffff:
+		return nil, errors.New("ljpeg: bad image size")
+	case nc <= 0 || nc > 4 || len(m.Pix) != m.Width*m.Height*nc:
+		return nil, errors.New("ljpeg: bad number of samples")
+	}
+
+	// The differences are computed first, to build the Huffman table from
+	// the frequencies of their categories.
+	stride := m.Width * nc
+	diffs := make([]int32, len(m.Pix))
+	var freq [17]int
+	mask := uint16(1)<<uint(m.Precision) - 1
+	sample := func(i int) int32 { return int32(m.Pix[i] & mask) }
+	for y := 0; y < m.Height; y++ {
+		for k := 0; k < stride; k++ {
+			i := y*stride + k
+			var pred int32
+			switch {
+			case y == 0 && k < nc:
+				pred = 1 << uint(m.Precision-1)
+			case y == 0:
+				pred = sample(i - nc)
+			case k < nc:
+				pred = sample(i - stride)
+			default:
+				ra, rb, rc := sample(i-nc), sample(i-stride), sample(i-stride-nc)
+				switch predictor {
+				case 1:
+					pred = ra
+				case 2:
+					pred = rb
+				case 3:
+					pred = rc
+				case 4:
+					pred = ra + rb - rc
+				case 5:
+					pred = ra + (rb-rc)>>1
+				case 6:
+					pred = rb + (ra-rc)>>1
+				case 7:
+					pred = (ra + rb) >> 1
+				}
+			}
+			// Differences are taken modulo 2^16, in -32767 to 32768.
+			d := int32(int16(uint16(sample(i) - pred)))
+			if d == -32768 {
+				d = 32768
+			}
+			diffs[i] = d
+			freq[category(d)]++
+		}
+	}
+
+	// The 17 categories get codes of 2 to 16 bits, the most frequent the
+	// shortest. The all ones code of 16 bits is left unused, as required.
+	counts := [16]uint8{0, 3, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}
+	values := make([]uint8, 17)
+	for i := range values {
+		values[i] = uint8(i)
+	}
+	sort.SliceStable(values, func(a, b int) bool { return freq[values[a]] > freq[values[b]] })
+	var codes [17]uint32
+	var lengths [17]uint
+	code, k := uint32(0), 0
+	for l := 1; l <= 16; l++ {
+		for n := 0; n < int(counts[l-1]); n++ {
+			codes[values[k]], lengths[values[k]] = code, uint(l)
+			code++
+			k++
+		}
+		code <<= 1
+	}
+
+	w := &writer{}
+	w.buf = append(w.buf, 0xff, markerSOI)
+	sof := []byte{uint8(m.Precision), uint8(m.Height >> 8), uint8(m.Height), uint8(m.Width >> 8), uint8(m.Width), uint8(nc)}
+	for i := 0; i < nc; i++ {
+		sof = append(sof, uint8(i+1), 0x11, 0)
+	}
+	w.writeSegment(markerSOF3, sof)
+	w.writeSegment(markerDHT, append(append([]byte{0}, counts[:]...), values...))
+	sos := []byte{uint8(nc)}
+	for i := 0; i < nc; i++ {
+		sos = append(sos, uint8(i+1), 0)
+	}
+	w.writeSegment(markerSOS, append(sos, uint8(predictor), 0, 0))
+	for _, d := range diffs {
+		c := category(d)
+		w.writeBits(codes[c], lengths[c])
+		if c > 0 && c < 16 {
+			if d < 0 {
+				d += 1<<c - 1
+			}
+			w.writeBits(uint32(d), c)
+		}
+	}
+	w.writeBits(0x7f, 7) // Pad the last byte with ones.
+	w.buf = append(w.buf, 0xff, markerEOI)
+	return w.buf, nil
+}
+
+// category returns the number of bits of the difference d, its SSSS
+// value of T.81 Table H.2.
+func category(d int32) uint {
+	if d < 0 {
+		d = -d
+	}
+	n := uint(0)
+	for ; d > 0; d >>= 1 {
+		n++
+	}
+	return n
+}
+
+type writer struct {
+	buf   []byte
+	bits  uint32
+	nbits uint
+}
+
+func (w *writer) writeSegment(marker uint8, b []byte) {
+	n := len(b) + 2
+	w.buf = append(w.buf, 0xff, marker, uint8(n>>8), uint8(n))
+	w.buf = append(w.buf, b...)
+}
+
+// writeBits writes the n low bits of v, stuffing a zero after 0xff bytes.
+// Bits that do not fill a byte are discarded.
+func (w *writer) writeBits(v uint32, n uint) {
+	w.bits = w.bits<<n | v&(1<<n-1)
+	w.nbits += n
+	for w.nbits >= 8 {
+		w.nbits -= 8
+		c := uint8(w.bits >> w.nbits)
+		w.buf = append(w.buf, c)
+		if c == 0xff {
+			w.buf = append(w.buf, 0)
+		}
+	}
+	w.bits &= 1<<w.nbits - 1
+}
@@ -0,0 +1,339 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// An IPTCTag identifies an IPTC-IIM dataset by its record number, in the
+// high byte, and its dataset number, in the low byte.
+type IPTCTag uint16
+
+const (
+	IPTCTag_CodedCharacterSet IPTCTag = 0x015A // 1:90
+
+	IPTCTag_ApplicationRecordVersion      IPTCTag = 0x0200 // 2:00
+	IPTCTag_ObjectName                    IPTCTag = 0x0205 // 2:05
+	IPTCTag_Urgency                       IPTCTag = 0x020A // 2:10
+	IPTCTag_Category                      IPTCTag = 0x020F // 2:15
+	IPTCTag_SupplementalCategory          IPTCTag = 0x0214 // 2:20, repeatable
+	IPTCTag_Keywords                      IPTCTag = 0x0219 // 2:25, repeatable
+	IPTCTag_SpecialInstructions           IPTCTag = 0x0228 // 2:40
+	IPTCTag_DateCreated                   IPTCTag = 0x0237 // 2:55, CCYYMMDD
+	IPTCTag_TimeCreated                   IPTCTag = 0x023C // 2:60, HHMMSS±HHMM
+	IPTCTag_Byline                        IPTCTag = 0x0250 // 2:80, repeatable
+	IPTCTag_BylineTitle                   IPTCTag = 0x0255 // 2:85, repeatable
+	IPTCTag_City                          IPTCTag = 0x025A // 2:90
+	IPTCTag_SubLocation                   IPTCTag = 0x025C // 2:92
+	IPTCTag_ProvinceState                 IPTCTag = 0x025F // 2:95
+	IPTCTag_CountryCode                   IPTCTag = 0x0264 // 2:100
+	IPTCTag_CountryName                   IPTCTag = 0x0265 // 2:101
+	IPTCTag_OriginalTransmissionReference IPTCTag = 0x0267 // 2:103
+	IPTCTag_Headline                      IPTCTag = 0x0269 // 2:105
+	IPTCTag_Credit                        IPTCTag = 0x026E // 2:110
+	IPTCTag_Source                        IPTCTag = 0x0273 // 2:115
+	IPTCTag_CopyrightNotice               IPTCTag = 0x0274 // 2:116
+	IPTCTag_Caption                       IPTCTag = 0x0278 // 2:120
+	IPTCTag_CaptionWriter                 IPTCTag = 0x027A // 2:122, repeatable
+)
+
+var _IPTCTagTable = map[IPTCTag]string{
+	IPTCTag_CodedCharacterSet:             `IPTCTag_CodedCharacterSet`,
+	IPTCTag_ApplicationRecordVersion:      `IPTCTag_ApplicationRecordVersion`,
+	IPTCTag_ObjectName:                    `IPTCTag_ObjectName`,
+	IPTCTag_Urgency:                       `IPTCTag_Urgency`,
+	IPTCTag_Category:                      `IPTCTag_Category`,
+	IPTCTag_SupplementalCategory:          `IPTCTag_SupplementalCategory`,
+	IPTCTag_Keywords:                      `IPTCTag_Keywords`,
+	IPTCTag_SpecialInstructions:           `IPTCTag_SpecialInstructions`,
+	IPTCTag_DateCreated:                   `IPTCTag_DateCreated`,
+	IPTCTag_TimeCreated:                   `IPTCTag_TimeCreated`,
+	IPTCTag_Byline:                        `IPTCTag_Byline`,
+	IPTCTag_BylineTitle:                   `IPTCTag_BylineTitle`,
+	IPTCTag_City:                          `IPTCTag_City`,
+	IPTCTag_SubLocation:                   `IPTCTag_SubLocation`,
+	IPTCTag_ProvinceState:                 `IPTCTag_ProvinceState`,
+	IPTCTag_CountryCode:                   `IPTCTag_CountryCode`,
+	IPTCTag_CountryName:                   `IPTCTag_CountryName`,
+	IPTCTag_OriginalTransmissionReference: `IPTCTag_OriginalTransmissionReference`,
+	IPTCTag_Headline:                      `IPTCTag_Headline`,
+	IPTCTag_Credit:                        `IPTCTag_Credit`,
+	IPTCTag_Source:                        `IPTCTag_Source`,
+	IPTCTag_CopyrightNotice:               `IPTCTag_CopyrightNotice`,
+	IPTCTag_Caption:                       `IPTCTag_Caption`,
+	IPTCTag_CaptionWriter:                 `IPTCTag_CaptionWriter`,
+}
+
+func (p IPTCTag) String() string {
+	if name, ok := _IPTCTagTable[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("IPTCTag_Unknown(%d:%02d)", p.Record(), p.DataSet())
+}
+
+// Record returns the record number of the tag.
+func (p IPTCTag) Record() int { return int(p >> 8) }
+
+// DataSet returns the dataset number of the tag within its record.
+func (p IPTCTag) DataSet() int { return int(p & 0xff) }
+
+// An IPTCDataSet is a dataset of an IPTC-IIM stream.
+type IPTCDataSet struct {
+	Tag  IPTCTag
+	Data []byte
+}
+
+// IPTC is an IPTC-IIM stream, as stored in the IPTC tag and in the
+// IPTC-NAA resource of the Photoshop tag. The datasets are kept in the
+// order of the stream; repeatable datasets have several entries.
+//
+// Text is returned as stored. Writers use UTF-8 when the stream has
+// a CodedCharacterSet of ESC % G, and mostly Latin-1 otherwise.
+type IPTC struct {
+	DataSets []IPTCDataSet
+}
+
+const iptcTagMarker = 0x1C
+
+// iptcUTF8 is the CodedCharacterSet of UTF-8 text.
+var iptcUTF8 = []byte("\x1b%G")
+
+// ParseIPTC parses an IPTC-IIM stream. Trailing zero bytes, which pad
+// the stream to a multiple of 4 bytes in TIFF files, are ignored.
+func ParseIPTC(data []byte) (*IPTC, error) {
+	x := &IPTC{}
+	for len(data) > 0 {
+		if data[0] != iptcTagMarker {
+			if len(bytes.Trim(data, "\x00")) == 0 {
+				break
+			}
+			return nil, fmt.Errorf("tiff: ParseIPTC, bad tag marker 0x%02x", data[0])
+		}
+		if len(data) < 5 {
+			return nil, fmt.Errorf("tiff: ParseIPTC, truncated dataset header")
+		}
+		tag := IPTCTag(binary.BigEndian.Uint16(data[1:]))
+		size, n := uint64(binary.BigEndian.Uint16(data[3:])), 5
+		if size&0x8000 != 0 {
+			// Extended dataset: the low bits are the length of the size.
+			k := int(size & 0x7fff)
+			if k > 8 || len(data) < n+k {
+				return nil, fmt.Errorf("tiff: ParseIPTC, bad extended size of %v", tag)
+			}
+			size = 0
+			for _, b := range data[n : n+k] {
+				size = size<<8 | uint64(b)
+			}
+			n += k
+		}
+		if uint64(len(data)-n) < size {
+			return nil, fmt.Errorf("tiff: ParseIPTC, %v exceeds the stream", tag)
+		}
+		x.DataSets = append(x.DataSets, IPTCDataSet{Tag: tag, Data: data[n : n+int(size)]})
+		data = data[n+int(size):]
+	}
+	return x, nil
+}
+
+// Bytes returns the IPTC-IIM stream of x, padded with zeros to a multiple
+// of 4 bytes as the IPTC tag requires.
+func (x *IPTC) Bytes() []byte {
+	var buf bytes.Buffer
+	for _, d := range x.DataSets {
+		buf.WriteByte(iptcTagMarker)
+		binary.Write(&buf, binary.BigEndian, uint16(d.Tag))
+		if len(d.Data) < 0x8000 {
+			binary.Write(&buf, binary.BigEndian, uint16(len(d.Data)))
+		} else {
+			binary.Write(&buf, binary.BigEndian, uint16(0x8004))
+			binary.Write(&buf, binary.BigEndian, uint32(len(d.Data)))
+		}
+		buf.Write(d.Data)
+	}
+	for buf.Len()%4 != 0 {
+		buf.WriteByte(0)
+	}
+	return buf.Bytes()
+}
+
+// Get returns the first value of the dataset tag.
+func (x *IPTC) Get(tag IPTCTag) (string, bool) {
+	for _, d := range x.DataSets {
+		if d.Tag == tag {
+			return string(d.Data), true
+		}
+	}
+	return "", false
+}
+
+// Values returns all the values of the repeatable dataset tag.
+func (x *IPTC) Values(tag IPTCTag) []string {
+	var values []string
+	for _, d := range x.DataSets {
+		if d.Tag == tag {
+			values = append(values, string(d.Data))
+		}
+	}
+	return values
+}
+
+// Set replaces the values of the dataset tag. The new datasets are placed
+// where the first old one was, or kept in the order of the records and
+// datasets if there was none.
+//
+// Set marks a new stream as UTF-8 and adds the ApplicationRecordVersion
+// when needed, as readers expect them.
+func (x *IPTC) Set(tag IPTCTag, values ...string) {
+	if tag != IPTCTag_CodedCharacterSet && tag != IPTCTag_ApplicationRecordVersion {
+		if len(x.DataSets) == 0 {
+			x.set(IPTCTag_CodedCharacterSet, iptcUTF8)
+		}
+		if _, ok := x.Get(IPTCTag_ApplicationRecordVersion); !ok && tag.Record() == 2 {
+			x.set(IPTCTag_ApplicationRecordVersion, []byte{0, 4})
+		}
+	}
+	data := make([][]byte, len(values))
+	for k, v := range values {
+		data[k] = []byte(v)
+	}
+	x.set(tag, data...)
+}
+
+func (x *IPTC) set(tag IPTCTag, values ...[]byte) {
+	at, next := -1, -1
+	var d []IPTCDataSet
+	for _, v := range x.DataSets {
+		switch {
+		case v.Tag == tag:
+			if at < 0 {
+				at = len(d)
+			}
+			continue
+		case v.Tag > tag && next < 0:
+			next = len(d)
+		}
+		d = append(d, v)
+	}
+	if at < 0 {
+		at = next
+	}
+	if at < 0 {
+		at = len(d)
+	}
+	var add []IPTCDataSet
+	for _, v := range values {
+		add = append(add, IPTCDataSet{Tag: tag, Data: v})
+	}
+	x.DataSets = append(d[:at], append(add, d[at:]...)...)
+}
+
+// Delete removes the datasets of tag.
+func (x *IPTC) Delete(tag IPTCTag) {
+	x.set(tag)
+}
+
+// ObjectName returns the ObjectName dataset, a short reference to the image.
+func (x *IPTC) ObjectName() (string, bool) { return x.Get(IPTCTag_ObjectName) }
+
+// Headline returns the Headline dataset.
+func (x *IPTC) Headline() (string, bool) { return x.Get(IPTCTag_Headline) }
+
+// Caption returns the Caption/Abstract dataset.
+func (x *IPTC) Caption() (string, bool) { return x.Get(IPTCTag_Caption) }
+
+// Byline returns the names of the creators.
+func (x *IPTC) Byline() []string { return x.Values(IPTCTag_Byline) }
+
+// Keywords returns the keywords.
+func (x *IPTC) Keywords() []string { return x.Values(IPTCTag_Keywords) }
+
+// City returns the City dataset.
+func (x *IPTC) City() (string, bool) { return x.Get(IPTCTag_City) }
+
+// CountryName returns the Country/PrimaryLocationName dataset.
+func (x *IPTC) CountryName() (string, bool) { return x.Get(IPTCTag_CountryName) }
+
+// Credit returns the Credit dataset, the provider of the image.
+func (x *IPTC) Credit() (string, bool) { return x.Get(IPTCTag_Credit) }
+
+// Source returns the Source dataset, the owner of the image.
+func (x *IPTC) Source() (string, bool) { return x.Get(IPTCTag_Source) }
+
+// CopyrightNotice returns the CopyrightNotice dataset.
+func (x *IPTC) CopyrightNotice() (string, bool) { return x.Get(IPTCTag_CopyrightNotice) }
+
+// SetCaption sets the Caption/Abstract dataset.
+func (x *IPTC) SetCaption(s string) { x.Set(IPTCTag_Caption, s) }
+
+// SetHeadline sets the Headline dataset.
+func (x *IPTC) SetHeadline(s string) { x.Set(IPTCTag_Headline, s) }
+
+// SetByline sets the names of the creators.
+func (x *IPTC) SetByline(names ...string) { x.Set(IPTCTag_Byline, names...) }
+
+// SetKeywords sets the keywords.
+func (x *IPTC) SetKeywords(keywords ...string) { x.Set(IPTCTag_Keywords, keywords...) }
+
+// DateCreated returns the DateCreated and TimeCreated datasets. Without a
+// TimeCreated, the time is midnight UTC.
+func (x *IPTC) DateCreated() (time.Time, bool) {
+	date, ok := x.Get(IPTCTag_DateCreated)
+	if !ok {
+		return time.Time{}, false
+	}
+	if clock, ok := x.Get(IPTCTag_TimeCreated); ok {
+		if t, err := time.Parse("20060102150405-0700", date+clock); err == nil {
+			return t, true
+		}
+	}
+	t, err := time.Parse("20060102", date)
+	return t, err == nil
+}
+
+// SetDateCreated sets the DateCreated and TimeCreated datasets.
+func (x *IPTC) SetDateCreated(t time.Time) {
+	x.Set(IPTCTag_DateCreated, t.Format("20060102"))
+	x.Set(IPTCTag_TimeCreated, t.Format("150405-0700"))
+}
+
+// IPTCData returns the raw IPTC-IIM stream of the IPTC tag, or else of the
+// IPTC-NAA resource of the Photoshop tag.
+func (p *IFD) IPTCData() ([]byte, bool) {
+	if data, ok := p.TagGetter().GetIPTC(); ok {
+		return data, true
+	}
+	if ps, err := p.Photoshop(); err == nil && ps != nil {
+		if r, ok := ps.Resource(PhotoshopResourceID_IPTC); ok {
+			return r.Data, true
+		}
+	}
+	return nil, false
+}
+
+// IPTC returns the parsed IPTC-IIM stream. It returns nil and no error if
+// the image has none.
+func (p *IFD) IPTC() (*IPTC, error) {
+	data, ok := p.IPTCData()
+	if !ok {
+		return nil, nil
+	}
+	return ParseIPTC(data)
+}
+
+// UpdateIPTC replaces the IPTC tag of the i-th image of the file f, or adds
+// one if it has none. A nil stream removes the IPTC tag. A copy of the
+// stream in the Photoshop tag, if any, is left unchanged. The file is
+// edited as by UpdateXMP.
+func UpdateIPTC(f io.ReadWriteSeeker, i int, data []byte) error {
+	if data == nil {
+		return editIFD(f, i, nil, TagType_IPTC)
+	}
+	return editIFD(f, i, []ifdEntry{{TagType_IPTC, DataType_Undefined, bytesData(data)}})
+}
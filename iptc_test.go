@@ -0,0 +1,124 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"image"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseIPTC(t *testing.T) {
+	data := []byte("\x1c\x01\x5a\x00\x03\x1b%G" +
+		"\x1c\x02\x00\x00\x02\x00\x04" +
+		"\x1c\x02\x19\x00\x05storm" +
+		"\x1c\x02\x19\x00\x05flood" +
+		"\x1c\x02\x37\x00\x0820150601" +
+		"\x1c\x02\x3c\x00\x0b123000+0200" +
+		"\x1c\x02\x50\x00\x08J. Smith" +
+		"\x1c\x02\x78\x80\x02\x00\x0dRiver floods." +
+		"\x00\x00")
+	x, err := ParseIPTC(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(x.DataSets) != 8 {
+		t.Fatalf("%d datasets, want 8", len(x.DataSets))
+	}
+	if v := x.Keywords(); !reflect.DeepEqual(v, []string{"storm", "flood"}) {
+		t.Errorf("Keywords = %q", v)
+	}
+	if v := x.Byline(); !reflect.DeepEqual(v, []string{"J. Smith"}) {
+		t.Errorf("Byline = %q", v)
+	}
+	if v, ok := x.Caption(); !ok || v != "River floods." {
+		t.Errorf("Caption = %q, %v", v, ok)
+	}
+	want := time.Date(2015, 6, 1, 12, 30, 0, 0, time.FixedZone("", 2*3600))
+	if v, ok := x.DateCreated(); !ok || !v.Equal(want) {
+		t.Errorf("DateCreated = %v, %v", v, ok)
+	}
+	if _, ok := x.Headline(); ok {
+		t.Errorf("Headline is present")
+	}
+	if s := IPTCTag(0x0301).String(); s != "IPTCTag_Unknown(3:01)" {
+		t.Errorf("unknown tag = %s", s)
+	}
+
+	// The extended size of the caption is written back in the short form.
+	x2, err := ParseIPTC(x.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(x, x2) {
+		t.Errorf("Bytes roundtrip:\n%v\n%v", x, x2)
+	}
+	long := &IPTC{DataSets: []IPTCDataSet{{Tag: IPTCTag_Caption, Data: []byte(strings.Repeat("a", 40000))}}}
+	if x2, err = ParseIPTC(long.Bytes()); err != nil || !reflect.DeepEqual(long, x2) {
+		t.Errorf("extended dataset roundtrip: %v", err)
+	}
+
+	for _, s := range []string{"\x1c\x02", "\x1c\x02\x19\x00\x09storm", "\x1c\x02\x19\x80\x09storm", "x"} {
+		if _, err := ParseIPTC([]byte(s)); err == nil {
+			t.Errorf("%q: no error", s)
+		}
+	}
+}
+
+func TestIPTC_set(t *testing.T) {
+	x := &IPTC{}
+	x.SetCaption("First")
+	x.SetKeywords("a", "b")
+	x.SetHeadline("News")
+	x.SetByline("Me")
+	x.SetCaption("Second")
+	x.SetDateCreated(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+	var tags []IPTCTag
+	for _, d := range x.DataSets {
+		tags = append(tags, d.Tag)
+	}
+	want := []IPTCTag{
+		IPTCTag_CodedCharacterSet, IPTCTag_ApplicationRecordVersion,
+		IPTCTag_Keywords, IPTCTag_Keywords, IPTCTag_DateCreated, IPTCTag_TimeCreated,
+		IPTCTag_Byline, IPTCTag_Headline, IPTCTag_Caption,
+	}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("datasets = %v, want %v", tags, want)
+	}
+	if v, ok := x.Caption(); !ok || v != "Second" {
+		t.Errorf("Caption = %q, %v", v, ok)
+	}
+	x.Delete(IPTCTag_Keywords)
+	if v := x.Keywords(); v != nil {
+		t.Errorf("Keywords = %q after Delete", v)
+	}
+	if v, ok := x.DateCreated(); !ok || !v.Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("DateCreated = %v, %v", v, ok)
+	}
+}
+
+func TestIPTC_encode(t *testing.T) {
+	x := &IPTC{}
+	x.SetHeadline("Encoded")
+	var out bytes.Buffer
+	if err := Encode(&out, image.NewGray(image.Rect(0, 0, 8, 8)), &Options{IPTC: x.Bytes()}); err != nil {
+		t.Fatal(err)
+	}
+	p, err := OpenReader(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	got, err := p.ImageIPTC(0, 0)
+	if err != nil || got == nil {
+		t.Fatalf("IPTC = %v, %v", got, err)
+	}
+	if !reflect.DeepEqual(got, x) {
+		t.Errorf("IPTC = %v, want %v", got, x)
+	}
+}
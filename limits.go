@@ -0,0 +1,168 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+)
+
+// DecodeOptions limits the memory used to decode a file, so that hostile
+// files cannot make the decoder allocate more than they are worth. A zero
+// field sets no limit. When a limit is exceeded, a *LimitError is returned.
+type DecodeOptions struct {
+	// MaxFileBytes is the size of the largest file read from a reader that
+	// cannot seek, which is held in memory.
+	MaxFileBytes int64
+
+	// MaxEntryBytes is the size of the largest data of an IFD entry.
+	MaxEntryBytes int64
+
+	// MaxIFDs is the largest number of IFDs of a file, SubIFDs and EXIF,
+	// GPS and interoperability IFDs included.
+	MaxIFDs int
+
+	// MaxPixels is the largest number of pixels of a decoded image,
+	// region, band or block.
+	MaxPixels int64
+
+	// MaxBlockBytes is the largest size of the decompressed data of a
+	// strip or tile.
+	MaxBlockBytes int64
+
+	fileSize int64 // The size of the file, set by openReader, or 0.
+}
+
+// A LimitError reports a file that exceeds a limit of DecodeOptions.
+type LimitError struct {
+	Limit string // The name of the field of DecodeOptions, such as "MaxPixels".
+	Max   int64  // The value of the limit.
+	Size  int64  // The size required by the file, or 0 if it is unknown.
+}
+
+func (e *LimitError) Error() string {
+	if e.Size > 0 {
+		return fmt.Sprintf("tiff: %s limit exceeded, %d > %d", e.Limit, e.Size, e.Max)
+	}
+	return fmt.Sprintf("tiff: %s limit exceeded, more than %d", e.Limit, e.Max)
+}
+
+// OpenReaderWithOptions is like OpenReader, within the limits of opt,
+// which may be nil. The limits also apply to the images decoded by the
+// returned Reader.
+func OpenReaderWithOptions(r io.Reader, opt *DecodeOptions) (p *Reader, err error) {
+	if _, ok := r.(io.Seeker); !ok && opt != nil && opt.MaxFileBytes > 0 {
+		var data []byte
+		if data, err = io.ReadAll(io.LimitReader(r, opt.MaxFileBytes+1)); err != nil {
+			return
+		}
+		if int64(len(data)) > opt.MaxFileBytes {
+			err = &LimitError{Limit: "MaxFileBytes", Max: opt.MaxFileBytes}
+			return
+		}
+		r = bytes.NewReader(data)
+	}
+	return openReader(openSeekioReader(r, -1), opt)
+}
+
+// OpenReaderAtWithOptions is like OpenReaderAt, within the limits of opt,
+// which may be nil.
+func OpenReaderAtWithOptions(r io.ReaderAt, size int64, opt *DecodeOptions) (p *Reader, err error) {
+	return openReader(&seekioReader{rs: newRangeReader(r, size)}, opt)
+}
+
+// DecodeWithOptions is like Decode, within the limits of opt, which may
+// be nil.
+func DecodeWithOptions(r io.Reader, opt *DecodeOptions) (m image.Image, err error) {
+	var p *Reader
+	if p, err = OpenReaderWithOptions(r, opt); err != nil {
+		p.Close()
+		return
+	}
+	defer p.Close()
+
+	m, err = p.DecodeImage(0, 0)
+	return
+}
+
+// withFileSize returns a copy of p, which may be nil, that records the
+// size of the file, so that it is not looked up for every entry and block.
+func (p *DecodeOptions) withFileSize(size int64) *DecodeOptions {
+	var opt DecodeOptions
+	if p != nil {
+		opt = *p
+	}
+	opt.fileSize = size
+	return &opt
+}
+
+// size returns the size of the file read from r: the recorded one, or else
+// the end of r.
+func (p *DecodeOptions) size(r io.Seeker) (int64, error) {
+	if p != nil && p.fileSize > 0 {
+		return p.fileSize, nil
+	}
+	return r.Seek(0, io.SeekEnd)
+}
+
+// checkPixels checks the number of pixels of an image of bounds r.
+func (p *DecodeOptions) checkPixels(r image.Rectangle) error {
+	if p == nil || p.MaxPixels <= 0 {
+		return nil
+	}
+	if n := int64(r.Dx()) * int64(r.Dy()); n > p.MaxPixels {
+		return &LimitError{Limit: "MaxPixels", Max: p.MaxPixels, Size: n}
+	}
+	return nil
+}
+
+// checkEntry checks the size of the data of an IFD entry.
+func (p *DecodeOptions) checkEntry(n int64) error {
+	if p == nil || p.MaxEntryBytes <= 0 || n <= p.MaxEntryBytes {
+		return nil
+	}
+	return &LimitError{Limit: "MaxEntryBytes", Max: p.MaxEntryBytes, Size: n}
+}
+
+// checkIFDs checks the number of IFDs of a file.
+func (p *DecodeOptions) checkIFDs(n int) error {
+	if p == nil || p.MaxIFDs <= 0 || n <= p.MaxIFDs {
+		return nil
+	}
+	return &LimitError{Limit: "MaxIFDs", Max: int64(p.MaxIFDs), Size: int64(n)}
+}
+
+// maxBlockBytes returns MaxBlockBytes, or 0 if p is nil.
+func (p *DecodeOptions) maxBlockBytes() int64 {
+	if p == nil {
+		return 0
+	}
+	return p.MaxBlockBytes
+}
+
+// checkBlock checks the size of the decompressed data of a block.
+func checkBlock(n, max int64) error {
+	if max <= 0 || n <= max {
+		return nil
+	}
+	return &LimitError{Limit: "MaxBlockBytes", Max: max, Size: n}
+}
+
+// readAll reads r to the end, but fails with a *LimitError as soon as it
+// has read more than max bytes, if max is positive.
+func readAll(r io.Reader, max int64) (data []byte, err error) {
+	if max <= 0 {
+		return io.ReadAll(r)
+	}
+	if data, err = io.ReadAll(io.LimitReader(r, max+1)); err != nil {
+		return
+	}
+	if int64(len(data)) > max {
+		return nil, &LimitError{Limit: "MaxBlockBytes", Max: max}
+	}
+	return
+}
@@ -0,0 +1,146 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestDecodeOptions(t *testing.T) {
+	var buf bytes.Buffer
+	m := image.NewGray(image.Rect(0, 0, 200, 100))
+	m.Pix[0] = 1 // Not bilevel.
+	opt := &Options{RowsPerStrip: 50, Compression: TagValue_CompressionType_Deflate, XMP: make([]byte, 1000)}
+	if err := Encode(&buf, m, opt); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	limit := func(err error) string {
+		var e *LimitError
+		if errors.As(err, &e) {
+			return e.Limit
+		}
+		return ""
+	}
+	decode := func(opt *DecodeOptions) error {
+		_, err := DecodeWithOptions(bytes.NewReader(data), opt)
+		return err
+	}
+	for _, tt := range []struct {
+		opt  DecodeOptions
+		want string
+	}{
+		{DecodeOptions{}, ""},
+		{DecodeOptions{MaxPixels: 20000, MaxBlockBytes: 10000, MaxEntryBytes: 1000, MaxIFDs: 1}, ""},
+		{DecodeOptions{MaxPixels: 19999}, "MaxPixels"},
+		{DecodeOptions{MaxBlockBytes: 9999}, "MaxBlockBytes"},
+		{DecodeOptions{MaxEntryBytes: 999}, "MaxEntryBytes"},
+	} {
+		if got := limit(decode(&tt.opt)); got != tt.want {
+			t.Errorf("%+v: limit %q, want %q", tt.opt, got, tt.want)
+		}
+	}
+
+	// Regions within MaxPixels can be decoded from larger images, as long
+	// as their blocks are within it too.
+	p, err := OpenReaderWithOptions(bytes.NewReader(data), &DecodeOptions{MaxPixels: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = p.DecodeRegion(0, 0, image.Rect(10, 10, 20, 20)); err != nil {
+		t.Errorf("DecodeRegion: %v", err)
+	}
+	if _, err = p.DecodeRegion(0, 0, image.Rect(0, 0, 200, 51)); limit(err) != "MaxPixels" {
+		t.Errorf("DecodeRegion: %v", err)
+	}
+
+	// Readers that cannot seek are read up to MaxFileBytes.
+	r := struct{ io.Reader }{bytes.NewReader(data)}
+	if _, err = OpenReaderWithOptions(r, &DecodeOptions{MaxFileBytes: int64(len(data) - 1)}); limit(err) != "MaxFileBytes" {
+		t.Errorf("MaxFileBytes: %v", err)
+	}
+	r = struct{ io.Reader }{bytes.NewReader(data)}
+	if _, err = OpenReaderWithOptions(r, &DecodeOptions{MaxFileBytes: int64(len(data))}); err != nil {
+		t.Errorf("MaxFileBytes: %v", err)
+	}
+
+	var pyramid bytes.Buffer
+	if err := EncodePyramid(&pyramid, m, nil, &PyramidOptions{MinSize: 50}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = OpenReaderWithOptions(bytes.NewReader(pyramid.Bytes()), &DecodeOptions{MaxIFDs: 2}); limit(err) != "MaxIFDs" {
+		t.Errorf("MaxIFDs: %v", err)
+	}
+
+	// The EXIF and GPS IFDs count as well.
+	data, err = ioutil.ReadFile("./testdata/gdal_autotest/gcore/data/exif_and_gps.tif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = OpenReaderWithOptions(bytes.NewReader(data), &DecodeOptions{MaxIFDs: 2}); limit(err) != "MaxIFDs" {
+		t.Errorf("MaxIFDs with EXIF and GPS: %v", err)
+	}
+	if _, err = OpenReaderWithOptions(bytes.NewReader(data), &DecodeOptions{MaxIFDs: 3}); err != nil {
+		t.Errorf("MaxIFDs with EXIF and GPS: %v", err)
+	}
+}
+
+// TestHostileEntry tests that entries announcing more data than the file
+// holds fail without allocating it.
+func TestHostileEntry(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, image.NewGray(image.Rect(0, 0, 8, 8)), &Options{XMP: make([]byte, 100)}); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	ifd := binary.LittleEndian.Uint32(data[4:])
+	n := int(binary.LittleEndian.Uint16(data[ifd:]))
+	for i := 0; i < n; i++ {
+		entry := data[int(ifd)+2+12*i:]
+		if TagType(binary.LittleEndian.Uint16(entry)) == TagType_XMP {
+			binary.LittleEndian.PutUint32(entry[4:], 0x7fffffff)
+		}
+	}
+	if _, err := OpenReader(bytes.NewReader(data)); err == nil {
+		t.Errorf("no error")
+	}
+}
+
+// TestHostileDNG tests that a lossless JPEG tile declaring a frame larger
+// than the limits fails before its samples are allocated.
+func TestHostileDNG(t *testing.T) {
+	data := dngTestFile(t, true)
+	// The height of the frame of the first tile is made 256 rows.
+	sof := bytes.Index(data, []byte{0xff, 0xc3})
+	data[sof+5], data[sof+6] = 1, 0
+	for _, tt := range []struct {
+		opt  DecodeOptions
+		want string
+	}{
+		{DecodeOptions{MaxBlockBytes: 16 * 16 * 2}, "MaxBlockBytes"},
+		{DecodeOptions{MaxPixels: 16 * 16 * 2}, "MaxPixels"},
+	} {
+		p, err := OpenReaderWithOptions(bytes.NewReader(data), &tt.opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dng, err := p.DNG()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var e *LimitError
+		if _, err = dng.DecodeRaw(); !errors.As(err, &e) || e.Limit != tt.want {
+			t.Errorf("%+v: DecodeRaw = %v, want a %s LimitError", tt.opt, err, tt.want)
+		}
+		p.Close()
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+/*
+The encoder follows src/pkg/compress/lzw/writer.go in the standard library,
+with the "off by one" code width transitions of TIFF, so that it writes the
+codes that lzw_reader.go reads: the code width grows one code earlier than
+in standard LZW, and the table is cleared before the 12-bit codes overflow.
+*/
+
+// lzwEncode returns data compressed with the LZW of TIFF, with 8-bit
+// literals and MSB-first codes.
+func lzwEncode(data []byte) []byte {
+	const (
+		clear = 1 << 8
+		eof   = clear + 1
+	)
+	e := &lzwEncoder{table: make(map[uint32]uint16)}
+	e.reset()
+	e.write(clear)
+	if len(data) > 0 {
+		code := uint32(data[0])
+		for _, x := range data[1:] {
+			key := code<<8 | uint32(x)
+			if c, ok := e.table[key]; ok {
+				code = uint32(c)
+				continue
+			}
+			e.write(code)
+			code = uint32(x)
+			if e.incHi() {
+				e.table[key] = e.hi
+			}
+		}
+		e.write(code)
+		e.incHi()
+	}
+	e.write(eof)
+	if e.nBits > 0 {
+		e.out = append(e.out, uint8(e.bits>>24))
+	}
+	return e.out
+}
+
+type lzwEncoder struct {
+	out []byte
+
+	// Codes are written MSB-first through bits, whose nBits high bits
+	// are pending.
+	bits  uint32
+	nBits uint
+
+	// hi is the code implied by the next code to be written, and overflow
+	// the code at which the width grows. NOTE: TIFF's LZW is "off by one".
+	width        uint
+	hi, overflow uint16
+	table        map[uint32]uint16 // From prefix code<<8 | byte to code.
+}
+
+func (e *lzwEncoder) reset() {
+	e.width = 9
+	e.hi = 1<<8 + 1
+	e.overflow = 1 << 9
+	clear(e.table)
+}
+
+func (e *lzwEncoder) write(code uint32) {
+	e.bits |= code << (32 - e.width - e.nBits)
+	e.nBits += e.width
+	for e.nBits >= 8 {
+		e.out = append(e.out, uint8(e.bits>>24))
+		e.bits <<= 8
+		e.nBits -= 8
+	}
+}
+
+// incHi increments hi after a code was written. It returns false if the
+// table was full and has been cleared, in which case no code is added.
+// As libtiff does, the table is cleared when hi reaches 4093.
+func (e *lzwEncoder) incHi() bool {
+	e.hi++
+	if e.hi >= 1<<lzwMaxWidth-3 {
+		e.write(1 << 8)
+		e.reset()
+		return false
+	}
+	if e.hi+1 >= e.overflow {
+		e.width++
+		e.overflow <<= 1
+	}
+	return true
+}
@@ -4,8 +4,80 @@
 
 package tiff
 
+import (
+	"encoding/binary"
+)
+
 type Options struct {
 	EntryMap map[TagType]*IFDEntry
+
+	// Compression is the type of compression used for the image data:
+	// None, Deflate or LZW. The zero value writes uncompressed data.
+	Compression TagValue_CompressionType
+
+	// Workers is the number of goroutines that compress the strips or
+	// tiles. If it is 0 or 1, blocks are compressed one after another; if
+	// it is negative, runtime.GOMAXPROCS(0) goroutines are used. The
+	// output does not depend on it.
+	Workers int
+
+	// Predictor determines whether the horizontal differencing predictor
+	// is applied before compression. It is ignored for images whose
+	// samples are not 8 or 16 bits deep.
+	Predictor bool
+
+	// TileWidth and TileLength select tiled output. Both must be positive
+	// multiples of 16. Tiles on the right and bottom edges of the image
+	// are padded with zeros. If both are zero, the image is written in strips.
+	TileWidth  int
+	TileLength int
+
+	// RowsPerStrip is the number of rows in each strip of striped output.
+	// If it is zero, the whole image is written as a single strip.
+	RowsPerStrip int
+
+	// Resolution is the physical resolution written to the XResolution,
+	// YResolution and ResolutionUnit tags. If it is nil, 72x72 dpi is written.
+	Resolution *Resolution
+
+	// ByteOrder is the byte order of the file, either binary.LittleEndian
+	// ("II") or binary.BigEndian ("MM"). It applies to the header, the IFD
+	// entries and the image samples. The zero value writes little-endian files.
+	ByteOrder binary.ByteOrder
+
+	// BigTiff forces BigTIFF output with 64-bit offsets. Without it,
+	// BigTIFF is only written if the file would exceed 4 GiB.
+	BigTiff bool
+
+	// GeoKeys and GeoTransform georeference the image as a GeoTIFF.
+	// GeoKeys is written to the GeoKeyDirectoryTag, GeoDoubleParamsTag and
+	// GeoAsciiParamsTag; GeoTransform to the ModelPixelScaleTag and
+	// ModelTiepointTag, or to the ModelTransformationTag if it is rotated or
+	// flipped.
+	// See NewGeoKeyDirectoryEPSG.
+	GeoKeys      *GeoKeyDirectory
+	GeoTransform *GeoTransform
+
+	// GDALMetadata and GDALNoData are written to the GDAL_METADATA and
+	// GDAL_NODATA tags, which hold the dataset and band metadata of GDAL,
+	// such as band scales and offsets, and the value of missing pixels.
+	GDALMetadata *GDALMetadata
+	GDALNoData   *float64
+
+	// XMP is an XMP packet written to the XMP tag. See XMP.Bytes.
+	XMP []byte
+
+	// IPTC is an IPTC-IIM stream written to the IPTC tag, and Photoshop
+	// the image resource blocks written to the Photoshop tag. To preserve
+	// them, pass the IPTCData and PhotoshopData of the source IFD.
+	// See IPTC.Bytes and PhotoshopResources.Bytes.
+	IPTC      []byte
+	Photoshop []byte
+
+	// ICCProfile is an ICC color profile written to the ICCProfile tag.
+	// Its color space must match the image: RGB for RGB and paletted
+	// images, GRAY for gray images.
+	ICCProfile []byte
 }
 
 func (p *Options) TagGetter() TagGetter {
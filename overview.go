@@ -0,0 +1,424 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"math"
+	"sort"
+)
+
+// newImageLike returns an empty image of the same type as m with bounds r.
+// Images of other types than those of the image package become image.RGBA.
+func newImageLike(m image.Image, r image.Rectangle) draw.Image {
+	switch m := m.(type) {
+	case *image.Gray:
+		return image.NewGray(r)
+	case *image.Gray16:
+		return image.NewGray16(r)
+	case *image.RGBA:
+		return image.NewRGBA(r)
+	case *image.RGBA64:
+		return image.NewRGBA64(r)
+	case *image.NRGBA:
+		return image.NewNRGBA(r)
+	case *image.NRGBA64:
+		return image.NewNRGBA64(r)
+	case *image.Paletted:
+		return image.NewPaletted(r, append(color.Palette(nil), m.Palette...))
+	}
+	return image.NewRGBA(r)
+}
+
+// pixLayout returns the pixel buffer of m, its stride and the number of
+// bytes of each pixel. ok is false for images without a pixel buffer.
+func pixLayout(m image.Image) (pix []byte, stride, size int, ok bool) {
+	switch m := m.(type) {
+	case *image.Gray:
+		return m.Pix, m.Stride, 1, true
+	case *image.Gray16:
+		return m.Pix, m.Stride, 2, true
+	case *image.RGBA:
+		return m.Pix, m.Stride, 4, true
+	case *image.RGBA64:
+		return m.Pix, m.Stride, 8, true
+	case *image.NRGBA:
+		return m.Pix, m.Stride, 4, true
+	case *image.NRGBA64:
+		return m.Pix, m.Stride, 8, true
+	case *image.Paletted:
+		return m.Pix, m.Stride, 1, true
+	}
+	return nil, 0, 0, false
+}
+
+// reduceNearest returns m reduced to half its width and height, rounded
+// up, by taking the upper left pixel of every 2x2 block.
+func reduceNearest(m image.Image) image.Image {
+	b := m.Bounds()
+	r := image.Rect(0, 0, (b.Dx()+1)/2, (b.Dy()+1)/2)
+	dst := newImageLike(m, r)
+	src, srcStride, size, ok := pixLayout(m)
+	if !ok {
+		for y := 0; y < r.Dy(); y++ {
+			for x := 0; x < r.Dx(); x++ {
+				dst.Set(x, y, m.At(b.Min.X+2*x, b.Min.Y+2*y))
+			}
+		}
+		return dst
+	}
+	pix, stride, _, _ := pixLayout(dst)
+	for y := 0; y < r.Dy(); y++ {
+		s := src[2*y*srcStride:]
+		d := pix[y*stride:]
+		for x := 0; x < r.Dx(); x++ {
+			copy(d[x*size:(x+1)*size], s[2*x*size:])
+		}
+	}
+	return dst
+}
+
+// A ResamplingType is the filter that reduces an image into an overview
+// of half its width and height.
+type ResamplingType int
+
+const (
+	ResamplingType_Nearest  ResamplingType = iota // The upper left pixel of every 2x2 block.
+	ResamplingType_Average                        // The mean of every 2x2 block.
+	ResamplingType_Gaussian                       // A 4x4 binomial kernel centered on every 2x2 block.
+)
+
+var _ResamplingTypeTable = map[ResamplingType]string{
+	ResamplingType_Nearest:  `ResamplingType_Nearest`,
+	ResamplingType_Average:  `ResamplingType_Average`,
+	ResamplingType_Gaussian: `ResamplingType_Gaussian`,
+}
+
+func (p ResamplingType) String() string {
+	if name, ok := _ResamplingTypeTable[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("ResamplingType_Unknown(%d)", int(p))
+}
+
+// resamplingKernels are the separable kernels of the filters, applied
+// around every 2x2 block.
+var resamplingKernels = map[ResamplingType][]int{
+	ResamplingType_Average:  {1, 1},
+	ResamplingType_Gaussian: {1, 3, 3, 1},
+}
+
+// ReduceImage returns m reduced to half its width and height, rounded
+// up, with the given filter. Paletted images are always reduced with
+// ResamplingType_Nearest, and images of other types than those of the
+// image package become image.RGBA64 for the other filters.
+func ReduceImage(m image.Image, resampling ResamplingType) image.Image {
+	kernel, ok := resamplingKernels[resampling]
+	if _, paletted := m.(*image.Paletted); !ok || paletted {
+		return reduceNearest(m)
+	}
+	src, srcStride, size, ok := pixLayout(m)
+	if !ok {
+		c := image.NewRGBA64(m.Bounds())
+		draw.Draw(c, c.Bounds(), m, c.Bounds().Min, draw.Src)
+		m = c
+		src, srcStride, size, _ = pixLayout(c)
+	}
+	depth := 1 // The bytes of a sample, big-endian.
+	switch m.(type) {
+	case *image.Gray16, *image.RGBA64, *image.NRGBA64:
+		depth = 2
+	}
+
+	b := m.Bounds()
+	r := image.Rect(0, 0, (b.Dx()+1)/2, (b.Dy()+1)/2)
+	dst := newImageLike(m, r)
+	pix, stride, _, _ := pixLayout(dst)
+	off := 1 - len(kernel)/2
+	total := 0
+	for _, w := range kernel {
+		total += w
+	}
+	total *= total
+	for y := 0; y < r.Dy(); y++ {
+		for x := 0; x < r.Dx(); x++ {
+			for s := 0; s < size; s += depth {
+				sum := 0
+				for j, wy := range kernel {
+					sy := clampInt(2*y+off+j, 0, b.Dy()-1)
+					for k, wx := range kernel {
+						sx := clampInt(2*x+off+k, 0, b.Dx()-1)
+						i := sy*srcStride + sx*size + s
+						v := int(src[i])
+						if depth == 2 {
+							v = v<<8 | int(src[i+1])
+						}
+						sum += wy * wx * v
+					}
+				}
+				v := (sum + total/2) / total
+				i := y*stride + x*size + s
+				if depth == 2 {
+					pix[i], pix[i+1] = uint8(v>>8), uint8(v)
+				} else {
+					pix[i] = uint8(v)
+				}
+			}
+		}
+	}
+	return dst
+}
+
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// Overviews returns the successive overviews of m, each reduced from the
+// previous one by ReduceImage, down to the first one that fits into
+// minSize x minSize pixels. It returns nil if m already fits.
+func Overviews(m image.Image, resampling ResamplingType, minSize int) []image.Image {
+	var levels []image.Image
+	if minSize <= 0 {
+		minSize = 1
+	}
+	for b := m.Bounds(); b.Dx() > minSize || b.Dy() > minSize; b = m.Bounds() {
+		m = ReduceImage(m, resampling)
+		levels = append(levels, m)
+	}
+	return levels
+}
+
+// encodeLevels compresses the blocks of the levels of a pyramid: the full
+// resolution image, then its overviews, which are marked as reduced and
+// only keep the GDAL nodata value and the ICC profile of the options.
+func encodeLevels(images []image.Image, o *Options) (levels []*imageEncoder, blocks [][][]byte, counts [][]uint64, err error) {
+	levels = make([]*imageEncoder, len(images))
+	blocks = make([][][]byte, len(images))
+	counts = make([][]uint64, len(images))
+	for k := range images {
+		var e *imageEncoder
+		if e, err = newImageEncoder(images[k], o); err != nil {
+			return
+		}
+		if k > 0 {
+			e.subfileType = TagValue_NewSubfileType_Reduced
+			e.geoTags = nil
+			e.gdalTags = gdalEntries(nil, o.GDALNoData)
+			e.metadataTags = nil
+		}
+		levels[k] = e
+		if blocks[k], counts[k], err = e.encodeBlocks(e.encodeBlock); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// PyramidOptions are the options of EncodePyramid and EncodeCOG.
+type PyramidOptions struct {
+	// Resampling is the filter that reduces each level into the next.
+	Resampling ResamplingType
+
+	// MinSize ends the pyramid at the first overview that fits into
+	// MinSize x MinSize pixels. If it is zero, the tile size of the
+	// Options is used, or 256.
+	MinSize int
+
+	// SubIFDs writes the overviews as SubIFDs of the full resolution IFD
+	// instead of as the IFDs that follow it.
+	SubIFDs bool
+}
+
+// EncodePyramid writes the image m to w followed by its overviews, each
+// half the width and height of the previous level, as returned by
+// Overviews. The overviews are marked with NewSubfileType=Reduced and
+// written with the same options as m, but georeferencing tags, GDAL
+// metadata, XMP, IPTC and Photoshop resources are only written to the
+// full resolution image, as by EncodeCOG.
+//
+// The data of each level is followed by its IFD. If popt is nil, the
+// overviews are reduced with ResamplingType_Nearest and follow m.
+func EncodePyramid(w io.Writer, m image.Image, opt *Options, popt *PyramidOptions) error {
+	var po PyramidOptions
+	if popt != nil {
+		po = *popt
+	}
+	if po.MinSize == 0 {
+		po.MinSize = 256
+		if opt != nil && opt.TileWidth > 0 {
+			po.MinSize = maxInt(opt.TileWidth, opt.TileLength)
+		}
+	}
+	var o Options
+	if opt != nil {
+		o = *opt
+	}
+
+	images := append([]image.Image{m}, Overviews(m, po.Resampling, po.MinSize)...)
+	levels, blocks, counts, err := encodeLevels(images, &o)
+	if err != nil {
+		return err
+	}
+
+	offsets, ifdOffsets, end := pyramidLayout(levels, counts, po.SubIFDs)
+	if !levels[0].bigTiff && end > math.MaxUint32 {
+		for _, e := range levels {
+			e.bigTiff = true
+		}
+		offsets, ifdOffsets, _ = pyramidLayout(levels, counts, po.SubIFDs)
+	}
+
+	h := levels[0].header(ifdOffsets[0])
+	if _, err := w.Write(h.Bytes()); err != nil {
+		return err
+	}
+	pos := int64(h.HeadSize())
+	for k, e := range levels {
+		for _, b := range blocks[k] {
+			if _, err := w.Write(b); err != nil {
+				return err
+			}
+			pos += int64(len(b))
+		}
+		if _, err := w.Write(make([]byte, ifdOffsets[k]-pos)); err != nil {
+			return err
+		}
+		var next int64
+		if !po.SubIFDs && k+1 < len(levels) {
+			next = ifdOffsets[k+1]
+		}
+		d := pyramidIFD(e, k, offsets[k], counts[k], po.SubIFDs, ifdOffsets)
+		b := ifdBytes(h, ifdOffsets[k], next, d)
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+		pos = ifdOffsets[k] + int64(len(b))
+	}
+	return nil
+}
+
+// pyramidIFD returns the IFD entries of level k of a pyramid. With
+// subIFDs, the full resolution IFD points to the IFDs of the overviews.
+func pyramidIFD(e *imageEncoder, k int, offsets, counts []uint64, subIFDs bool, ifdOffsets []int64) []ifdEntry {
+	d := e.ifd(offsets, counts)
+	if subIFDs && k == 0 && len(ifdOffsets) > 1 {
+		offsetType := DataType_Long
+		if e.bigTiff {
+			offsetType = DataType_IFD8
+		}
+		subOffsets := make([]uint64, len(ifdOffsets)-1)
+		for i := range subOffsets {
+			subOffsets[i] = uint64(ifdOffsets[i+1])
+		}
+		d = append(d, ifdEntry{TagType_SubIFD, offsetType, subOffsets})
+	}
+	return d
+}
+
+// pyramidLayout returns the block offsets and the IFD offsets of the
+// levels of a pyramid whose blocks have the given sizes, and the size of
+// the file. The blocks of each level are followed by its IFD.
+func pyramidLayout(levels []*imageEncoder, counts [][]uint64, subIFDs bool) (offsets [][]uint64, ifdOffsets []int64, end int64) {
+	h := levels[0].header(0)
+	off := int64(h.HeadSize())
+	offsets = make([][]uint64, len(levels))
+	ifdOffsets = make([]int64, len(levels))
+	for k, e := range levels {
+		offsets[k] = make([]uint64, len(counts[k]))
+		for i, n := range counts[k] {
+			offsets[k][i] = uint64(off)
+			off += int64(n)
+		}
+		// The IFD size does not depend on the values of the offsets.
+		off += off % 2
+		ifdOffsets[k] = off
+		off += int64(len(ifdBytes(h, off, 0, pyramidIFD(e, k, offsets[k], counts[k], subIFDs, ifdOffsets))))
+	}
+	return offsets, ifdOffsets, off
+}
+
+// EncodePyramid writes the image i of the file to w with its overviews,
+// as the function EncodePyramid. If opt is nil, the resolution and the
+// georeferencing of the image are kept.
+func (p *Reader) EncodePyramid(w io.Writer, i int, opt *Options, popt *PyramidOptions) error {
+	m, err := p.DecodeImage(i, 0)
+	if err != nil {
+		return err
+	}
+	if opt == nil {
+		opt = &Options{}
+		if res, ok := p.ImageResolution(i, 0); ok {
+			opt.Resolution = &res
+		}
+		if keys, err := p.ImageGeoKeyDirectory(i, 0); err == nil {
+			opt.GeoKeys = keys
+		}
+		if gt, ok := p.ImageGeoTransform(i, 0); ok {
+			opt.GeoTransform = &gt
+		}
+		if nodata, ok := p.ImageGDALNoData(i, 0); ok {
+			opt.GDALNoData = &nodata
+		}
+	}
+	return EncodePyramid(w, m, opt, popt)
+}
+
+// Overviews returns the image i and its overviews as (i, j) indexes, from
+// the largest to the smallest: the reduced resolution SubIFDs of image i
+// and the reduced resolution images that follow it.
+func (p *Reader) Overviews(i int) [][2]int {
+	levels := [][2]int{{i, 0}}
+	reduced := func(ifd *IFD) bool {
+		if ifd == nil {
+			return false
+		}
+		subfile, _ := ifd.TagGetter().GetNewSubfileType()
+		return TagValue_NewSubfileType(subfile)&TagValue_NewSubfileType_Reduced != 0
+	}
+	for j := 1; j < len(p.Ifd[i]); j++ {
+		if reduced(p.Ifd[i][j]) {
+			levels = append(levels, [2]int{i, j})
+		}
+	}
+	for k := i + 1; k < len(p.Ifd) && reduced(p.Ifd[k][0]); k++ {
+		levels = append(levels, [2]int{k, 0})
+	}
+	width := func(level [2]int) int {
+		cfg, _ := p.ImageConfig(level[0], level[1])
+		return cfg.Width
+	}
+	sort.SliceStable(levels, func(a, b int) bool { return width(levels[a]) > width(levels[b]) })
+	return levels
+}
+
+// BestOverview returns the smallest level of Overviews(i) that is at
+// least scale times as large as image i, to be resampled to that scale:
+// 0.25 asks for a quarter of its width and height. A scale of 1 or more
+// returns image i itself.
+func (p *Reader) BestOverview(i int, scale float64) (ii, j int) {
+	full, _ := p.ImageConfig(i, 0)
+	best := [2]int{i, 0}
+	for _, level := range p.Overviews(i) {
+		cfg, err := p.ImageConfig(level[0], level[1])
+		if err != nil {
+			continue
+		}
+		// Overview sizes are rounded up.
+		if float64(cfg.Width) >= math.Floor(float64(full.Width)*scale) && float64(cfg.Height) >= math.Floor(float64(full.Height)*scale) {
+			best = level
+		}
+	}
+	return best[0], best[1]
+}
@@ -0,0 +1,163 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+func TestReduceImage(t *testing.T) {
+	m := image.NewGray(image.Rect(0, 0, 5, 3))
+	copy(m.Pix, []uint8{
+		0, 40, 80, 120, 160,
+		20, 60, 100, 140, 180,
+		240, 240, 240, 240, 240,
+	})
+	for _, tt := range []struct {
+		resampling ResamplingType
+		want       []uint8
+	}{
+		{ResamplingType_Nearest, []uint8{0, 80, 160, 240, 240, 240}},
+		{ResamplingType_Average, []uint8{30, 110, 170, 240, 240, 240}},
+		{ResamplingType_Gaussian, []uint8{59, 125, 173, 216, 225, 232}},
+	} {
+		got := ReduceImage(m, tt.resampling).(*image.Gray)
+		if got.Bounds() != image.Rect(0, 0, 3, 2) || !reflect.DeepEqual(got.Pix, tt.want) {
+			t.Errorf("%v: got %v, want %v", tt.resampling, got.Pix, tt.want)
+		}
+	}
+
+	// Samples of 16 bits keep their precision, in every channel.
+	m16 := image.NewRGBA64(image.Rect(10, 10, 12, 12))
+	m16.SetRGBA64(10, 10, color.RGBA64{0x0101, 0xffff, 0, 0xffff})
+	m16.SetRGBA64(11, 10, color.RGBA64{0x0102, 0xffff, 0, 0xffff})
+	m16.SetRGBA64(10, 11, color.RGBA64{0x0103, 0, 0, 0xffff})
+	m16.SetRGBA64(11, 11, color.RGBA64{0x0104, 0, 0x1000, 0xffff})
+	got := ReduceImage(m16, ResamplingType_Average).(*image.RGBA64)
+	if c := got.RGBA64At(0, 0); c != (color.RGBA64{0x0103, 0x8000, 0x0400, 0xffff}) {
+		t.Errorf("RGBA64: got %v", c)
+	}
+
+	// Paletted images are not averaged.
+	p := image.NewPaletted(image.Rect(0, 0, 2, 2), color.Palette{color.Black, color.White})
+	p.Pix[3] = 1
+	if got := ReduceImage(p, ResamplingType_Gaussian).(*image.Paletted); got.Pix[0] != 0 {
+		t.Errorf("Paletted: got %v", got.Pix)
+	}
+	if s := ResamplingType_Gaussian.String(); s != "ResamplingType_Gaussian" {
+		t.Errorf("String = %q", s)
+	}
+}
+
+func TestEncodePyramid(t *testing.T) {
+	m := image.NewRGBA(image.Rect(0, 0, 200, 90))
+	for y := 0; y < 90; y++ {
+		for x := 0; x < 200; x++ {
+			m.Set(x, y, color.RGBA{uint8(x), uint8(y), uint8(x + y), 0xff})
+		}
+	}
+	for _, subIFDs := range []bool{false, true} {
+		popt := &PyramidOptions{Resampling: ResamplingType_Average, MinSize: 32, SubIFDs: subIFDs}
+		var buf bytes.Buffer
+		opt := &Options{TileWidth: 32, TileLength: 32, Compression: TagValue_CompressionType_Deflate}
+		if err := EncodePyramid(&buf, m, opt, popt); err != nil {
+			t.Fatalf("SubIFDs %v: %v", subIFDs, err)
+		}
+		p, err := OpenReader(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("SubIFDs %v: %v", subIFDs, err)
+		}
+
+		var want [][2]int
+		for k := 0; k < 4; k++ {
+			if subIFDs {
+				want = append(want, [2]int{0, k})
+			} else {
+				want = append(want, [2]int{k, 0})
+			}
+		}
+		levels := p.Overviews(0)
+		if !reflect.DeepEqual(levels, want) {
+			t.Fatalf("SubIFDs %v: Overviews = %v, want %v", subIFDs, levels, want)
+		}
+		wantLevel := image.Image(m)
+		for k, level := range levels {
+			if k > 0 {
+				wantLevel = ReduceImage(wantLevel, ResamplingType_Average)
+			}
+			got, err := p.DecodeImage(level[0], level[1])
+			if err != nil {
+				t.Fatalf("SubIFDs %v, level %d: %v", subIFDs, k, err)
+			}
+			if b := got.Bounds(); b != wantLevel.Bounds() || !imageEqual(got, wantLevel) {
+				t.Errorf("SubIFDs %v, level %d: image differs, bounds %v", subIFDs, k, b)
+			}
+		}
+
+		for _, tt := range []struct {
+			scale float64
+			want  [2]int
+		}{
+			{2, levels[0]},
+			{1, levels[0]},
+			{0.5, levels[1]},
+			{0.3, levels[1]},
+			{0.25, levels[2]},
+			{0.01, levels[3]},
+		} {
+			if i, j := p.BestOverview(0, tt.scale); [2]int{i, j} != tt.want {
+				t.Errorf("SubIFDs %v: BestOverview(%v) = %v, %v, want %v", subIFDs, tt.scale, i, j, tt.want)
+			}
+		}
+	}
+}
+
+func imageEqual(a, b image.Image) bool {
+	r := a.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			r0, g0, b0, a0 := a.At(x, y).RGBA()
+			r1, g1, b1, a1 := b.At(x, y).RGBA()
+			if r0 != r1 || g0 != g1 || b0 != b1 || a0 != a1 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestReader_EncodePyramid(t *testing.T) {
+	var src bytes.Buffer
+	gt := GeoTransform{0, 10, 0, 0, 0, -10}
+	if err := Encode(&src, image.NewGray16(image.Rect(0, 0, 300, 100)), &Options{GeoTransform: &gt}); err != nil {
+		t.Fatal(err)
+	}
+	p, err := OpenReader(bytes.NewReader(src.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := p.EncodePyramid(&out, 0, nil, &PyramidOptions{Resampling: ResamplingType_Gaussian}); err != nil {
+		t.Fatal(err)
+	}
+	p2, err := OpenReader(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The georeferencing is kept on the full resolution image only.
+	if got, ok := p2.ImageGeoTransform(0, 0); !ok || got != gt {
+		t.Errorf("GeoTransform = %v, %v", got, ok)
+	}
+	if _, ok := p2.ImageGeoTransform(1, 0); ok {
+		t.Errorf("GeoTransform of an overview")
+	}
+	if levels := p2.Overviews(0); len(levels) != 2 {
+		t.Errorf("Overviews = %v", levels)
+	}
+}
@@ -0,0 +1,407 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// A PhotoshopResourceID is the identifier of a Photoshop image resource.
+type PhotoshopResourceID uint16
+
+const (
+	PhotoshopResourceID_ResolutionInfo   PhotoshopResourceID = 0x03ED
+	PhotoshopResourceID_AlphaNames       PhotoshopResourceID = 0x03EE
+	PhotoshopResourceID_IPTC             PhotoshopResourceID = 0x0404 // IPTC-NAA record, see IPTC.
+	PhotoshopResourceID_JPEGQuality      PhotoshopResourceID = 0x0406
+	PhotoshopResourceID_GridGuides       PhotoshopResourceID = 0x0408
+	PhotoshopResourceID_ThumbnailBGR     PhotoshopResourceID = 0x0409 // Photoshop 4.0, in BGR order.
+	PhotoshopResourceID_CopyrightFlag    PhotoshopResourceID = 0x040A
+	PhotoshopResourceID_URL              PhotoshopResourceID = 0x040B
+	PhotoshopResourceID_Thumbnail        PhotoshopResourceID = 0x040C
+	PhotoshopResourceID_ICCProfile       PhotoshopResourceID = 0x040F
+	PhotoshopResourceID_VersionInfo      PhotoshopResourceID = 0x0421
+	PhotoshopResourceID_XMP              PhotoshopResourceID = 0x0424
+	PhotoshopResourceID_PathFirst        PhotoshopResourceID = 0x07D0 // Saved paths are 2000 through 2997.
+	PhotoshopResourceID_PathLast         PhotoshopResourceID = 0x0BB5
+	PhotoshopResourceID_ClippingPathName PhotoshopResourceID = 0x0BB7
+)
+
+var _PhotoshopResourceIDTable = map[PhotoshopResourceID]string{
+	PhotoshopResourceID_ResolutionInfo:   `PhotoshopResourceID_ResolutionInfo`,
+	PhotoshopResourceID_AlphaNames:       `PhotoshopResourceID_AlphaNames`,
+	PhotoshopResourceID_IPTC:             `PhotoshopResourceID_IPTC`,
+	PhotoshopResourceID_JPEGQuality:      `PhotoshopResourceID_JPEGQuality`,
+	PhotoshopResourceID_GridGuides:       `PhotoshopResourceID_GridGuides`,
+	PhotoshopResourceID_ThumbnailBGR:     `PhotoshopResourceID_ThumbnailBGR`,
+	PhotoshopResourceID_CopyrightFlag:    `PhotoshopResourceID_CopyrightFlag`,
+	PhotoshopResourceID_URL:              `PhotoshopResourceID_URL`,
+	PhotoshopResourceID_Thumbnail:        `PhotoshopResourceID_Thumbnail`,
+	PhotoshopResourceID_ICCProfile:       `PhotoshopResourceID_ICCProfile`,
+	PhotoshopResourceID_VersionInfo:      `PhotoshopResourceID_VersionInfo`,
+	PhotoshopResourceID_XMP:              `PhotoshopResourceID_XMP`,
+	PhotoshopResourceID_ClippingPathName: `PhotoshopResourceID_ClippingPathName`,
+}
+
+func (p PhotoshopResourceID) String() string {
+	if name, ok := _PhotoshopResourceIDTable[p]; ok {
+		return name
+	}
+	if p.IsPath() {
+		return fmt.Sprintf("PhotoshopResourceID_Path(%d)", uint16(p))
+	}
+	return fmt.Sprintf("PhotoshopResourceID_Unknown(%d)", uint16(p))
+}
+
+// IsPath reports whether the resource is a saved path.
+func (p PhotoshopResourceID) IsPath() bool {
+	return p >= PhotoshopResourceID_PathFirst && p <= PhotoshopResourceID_PathLast
+}
+
+// A PhotoshopResource is an image resource block of the Photoshop tag.
+type PhotoshopResource struct {
+	ID   PhotoshopResourceID
+	Name string
+	Data []byte
+}
+
+// PhotoshopResources are the image resource blocks of the Photoshop tag,
+// in the order of the file.
+type PhotoshopResources struct {
+	Resources []PhotoshopResource
+}
+
+// photoshopSignatures are the signatures of image resource blocks. All
+// but 8BIM come from old or third party software.
+var photoshopSignatures = map[string]bool{
+	"8BIM": true, "MeSa": true, "AgHg": true, "PHUT": true, "DCSR": true,
+}
+
+// ParsePhotoshop parses the image resource blocks of the Photoshop tag.
+// Trailing zero bytes are ignored.
+func ParsePhotoshop(data []byte) (*PhotoshopResources, error) {
+	p := &PhotoshopResources{}
+	for len(bytes.Trim(data, "\x00")) != 0 {
+		if len(data) < 8 || !photoshopSignatures[string(data[:4])] {
+			return nil, fmt.Errorf("tiff: ParsePhotoshop, bad resource signature %.4q", data)
+		}
+		id := PhotoshopResourceID(binary.BigEndian.Uint16(data[4:]))
+		n := 7 + int(data[6]) // The Pascal name is padded to an even size.
+		n += n % 2
+		if len(data) < n+4 {
+			return nil, fmt.Errorf("tiff: ParsePhotoshop, truncated resource %v", id)
+		}
+		name := string(data[7 : 7+int(data[6])])
+		size := binary.BigEndian.Uint32(data[n:])
+		n += 4
+		if uint64(size) > uint64(len(data)-n) {
+			return nil, fmt.Errorf("tiff: ParsePhotoshop, resource %v exceeds the tag", id)
+		}
+		p.Resources = append(p.Resources, PhotoshopResource{ID: id, Name: name, Data: data[n : n+int(size)]})
+		n += int(size) + int(size)%2
+		if n > len(data) {
+			n = len(data)
+		}
+		data = data[n:]
+	}
+	return p, nil
+}
+
+// Bytes returns the image resource blocks of p, with the 8BIM signature.
+func (p *PhotoshopResources) Bytes() []byte {
+	var buf bytes.Buffer
+	for _, r := range p.Resources {
+		name := r.Name
+		if len(name) > 255 {
+			name = name[:255]
+		}
+		buf.WriteString("8BIM")
+		binary.Write(&buf, binary.BigEndian, uint16(r.ID))
+		buf.WriteByte(byte(len(name)))
+		buf.WriteString(name)
+		if len(name)%2 == 0 {
+			buf.WriteByte(0)
+		}
+		binary.Write(&buf, binary.BigEndian, uint32(len(r.Data)))
+		buf.Write(r.Data)
+		if len(r.Data)%2 != 0 {
+			buf.WriteByte(0)
+		}
+	}
+	return buf.Bytes()
+}
+
+// Resource returns the first resource of the given ID.
+func (p *PhotoshopResources) Resource(id PhotoshopResourceID) (*PhotoshopResource, bool) {
+	for k := range p.Resources {
+		if p.Resources[k].ID == id {
+			return &p.Resources[k], true
+		}
+	}
+	return nil, false
+}
+
+// Set replaces the data of the resource of the given ID, or appends a new
+// resource if there is none.
+func (p *PhotoshopResources) Set(id PhotoshopResourceID, data []byte) {
+	if r, ok := p.Resource(id); ok {
+		r.Data = data
+		return
+	}
+	p.Resources = append(p.Resources, PhotoshopResource{ID: id, Data: data})
+}
+
+// Delete removes the resources of the given ID.
+func (p *PhotoshopResources) Delete(id PhotoshopResourceID) {
+	var d []PhotoshopResource
+	for _, r := range p.Resources {
+		if r.ID != id {
+			d = append(d, r)
+		}
+	}
+	p.Resources = d
+}
+
+// IPTC returns the parsed IPTC-NAA resource. It returns nil and no error
+// if there is none.
+func (p *PhotoshopResources) IPTC() (*IPTC, error) {
+	r, ok := p.Resource(PhotoshopResourceID_IPTC)
+	if !ok {
+		return nil, nil
+	}
+	return ParseIPTC(r.Data)
+}
+
+// SetIPTC replaces the IPTC-NAA resource.
+func (p *PhotoshopResources) SetIPTC(x *IPTC) {
+	p.Set(PhotoshopResourceID_IPTC, x.Bytes())
+}
+
+// ResolutionInfo returns the resolution of the ResolutionInfo resource,
+// in pixels per inch or per centimeter.
+func (p *PhotoshopResources) ResolutionInfo() (Resolution, bool) {
+	r, ok := p.Resource(PhotoshopResourceID_ResolutionInfo)
+	if !ok || len(r.Data) < 16 {
+		return Resolution{}, false
+	}
+	be := binary.BigEndian
+	x := float64(be.Uint32(r.Data[0:])) / 65536
+	y := float64(be.Uint32(r.Data[8:])) / 65536
+	unit := TagValue_ResolutionUnitType_PerInch
+	if be.Uint16(r.Data[4:]) == 2 {
+		unit = TagValue_ResolutionUnitType_PerCM
+	}
+	return NewResolution(x, y, unit), true
+}
+
+// SetResolutionInfo replaces the ResolutionInfo resource. Photoshop shows
+// the width and height in the unit of the resolution.
+func (p *PhotoshopResources) SetResolutionInfo(res Resolution) {
+	fixed := func(r [2]int64) uint32 {
+		if r[1] == 0 {
+			return 0
+		}
+		return uint32(math.Round(float64(r[0]) / float64(r[1]) * 65536))
+	}
+	unit, sizeUnit := uint16(1), uint16(2) // Pixels per inch, inches.
+	if res.Unit == TagValue_ResolutionUnitType_PerCM {
+		unit, sizeUnit = 2, 3 // Pixels per centimeter, centimeters.
+	}
+	b := make([]byte, 16)
+	be := binary.BigEndian
+	be.PutUint32(b[0:], fixed(res.X))
+	be.PutUint16(b[4:], unit)
+	be.PutUint16(b[6:], sizeUnit)
+	be.PutUint32(b[8:], fixed(res.Y))
+	be.PutUint16(b[12:], unit)
+	be.PutUint16(b[14:], sizeUnit)
+	p.Set(PhotoshopResourceID_ResolutionInfo, b)
+}
+
+// A PhotoshopThumbnail is the JPEG thumbnail of the Thumbnail resource.
+type PhotoshopThumbnail struct {
+	Width, Height int
+	JPEG          []byte
+	BGR           bool // The JPEG is in BGR order, as written by Photoshop 4.0.
+}
+
+// Thumbnail returns the thumbnail of the Thumbnail resource, or else of
+// the older ThumbnailBGR resource.
+func (p *PhotoshopResources) Thumbnail() (PhotoshopThumbnail, bool) {
+	r, ok := p.Resource(PhotoshopResourceID_Thumbnail)
+	if !ok {
+		r, ok = p.Resource(PhotoshopResourceID_ThumbnailBGR)
+	}
+	// The header is format, width, height, row size, total size, JPEG size,
+	// bits per pixel and planes. Only the JPEG format (1) is used.
+	be := binary.BigEndian
+	if !ok || len(r.Data) < 28 || be.Uint32(r.Data) != 1 {
+		return PhotoshopThumbnail{}, false
+	}
+	size := be.Uint32(r.Data[20:])
+	if uint64(size) > uint64(len(r.Data)-28) {
+		return PhotoshopThumbnail{}, false
+	}
+	return PhotoshopThumbnail{
+		Width:  int(be.Uint32(r.Data[4:])),
+		Height: int(be.Uint32(r.Data[8:])),
+		JPEG:   r.Data[28 : 28+size],
+		BGR:    r.ID == PhotoshopResourceID_ThumbnailBGR,
+	}, true
+}
+
+// A PhotoshopKnot is a Bezier knot of a Photoshop path. The points are
+// x, y pairs in fractions of the image width and height.
+type PhotoshopKnot struct {
+	Linked    bool // The control points move together.
+	Preceding [2]float64
+	Anchor    [2]float64
+	Leaving   [2]float64
+}
+
+// A PhotoshopSubpath is a closed or open sequence of knots.
+type PhotoshopSubpath struct {
+	Closed bool
+	Knots  []PhotoshopKnot
+}
+
+// A PhotoshopPath is a saved path, such as a clipping path.
+type PhotoshopPath struct {
+	ID       PhotoshopResourceID
+	Name     string
+	Subpaths []PhotoshopSubpath
+}
+
+// Paths returns the saved paths.
+func (p *PhotoshopResources) Paths() []PhotoshopPath {
+	var paths []PhotoshopPath
+	for _, r := range p.Resources {
+		if r.ID.IsPath() {
+			paths = append(paths, PhotoshopPath{ID: r.ID, Name: r.Name, Subpaths: parsePhotoshopPath(r.Data)})
+		}
+	}
+	return paths
+}
+
+// ClippingPathName returns the name of the path that clips the image.
+func (p *PhotoshopResources) ClippingPathName() (string, bool) {
+	r, ok := p.Resource(PhotoshopResourceID_ClippingPathName)
+	if !ok || len(r.Data) < 1 || len(r.Data) < 1+int(r.Data[0]) {
+		return "", false
+	}
+	return string(r.Data[1 : 1+int(r.Data[0])]), true
+}
+
+// ClippingPath returns the path that clips the image.
+func (p *PhotoshopResources) ClippingPath() (PhotoshopPath, bool) {
+	name, ok := p.ClippingPathName()
+	if !ok {
+		return PhotoshopPath{}, false
+	}
+	for _, path := range p.Paths() {
+		if path.Name == name {
+			return path, true
+		}
+	}
+	return PhotoshopPath{}, false
+}
+
+// parsePhotoshopPath parses the 26-byte records of a path resource.
+func parsePhotoshopPath(data []byte) []PhotoshopSubpath {
+	// point reads a vertical, horizontal pair of 8.24 fixed point numbers.
+	point := func(b []byte) [2]float64 {
+		y := float64(int32(binary.BigEndian.Uint32(b[0:]))) / (1 << 24)
+		x := float64(int32(binary.BigEndian.Uint32(b[4:]))) / (1 << 24)
+		return [2]float64{x, y}
+	}
+	var subpaths []PhotoshopSubpath
+	for ; len(data) >= 26; data = data[26:] {
+		switch selector := binary.BigEndian.Uint16(data); selector {
+		case 0, 3: // Closed and open subpath length records.
+			subpaths = append(subpaths, PhotoshopSubpath{Closed: selector == 0})
+		case 1, 2, 4, 5: // Linked and unlinked knots.
+			if len(subpaths) == 0 {
+				continue
+			}
+			s := &subpaths[len(subpaths)-1]
+			s.Knots = append(s.Knots, PhotoshopKnot{
+				Linked:    selector == 1 || selector == 4,
+				Preceding: point(data[2:]),
+				Anchor:    point(data[10:]),
+				Leaving:   point(data[18:]),
+			})
+		}
+	}
+	return subpaths
+}
+
+// photoshopFixed returns the 8.24 fixed point number of v.
+func photoshopFixed(v float64) uint32 {
+	return uint32(int32(math.Round(v * (1 << 24))))
+}
+
+// Bytes returns the records of the path resource of p.
+func (p *PhotoshopPath) Bytes() []byte {
+	var buf bytes.Buffer
+	be := binary.BigEndian
+	// The path fill rule record comes first.
+	record := make([]byte, 26)
+	be.PutUint16(record, 6)
+	buf.Write(record)
+	for _, s := range p.Subpaths {
+		record = make([]byte, 26)
+		be.PutUint16(record, 3)
+		if s.Closed {
+			be.PutUint16(record, 0)
+		}
+		be.PutUint16(record[2:], uint16(len(s.Knots)))
+		buf.Write(record)
+		for _, k := range s.Knots {
+			selector := uint16(2)
+			if k.Linked {
+				selector = 1
+			}
+			if !s.Closed {
+				selector += 3
+			}
+			be.PutUint16(record, selector)
+			for i, pt := range [][2]float64{k.Preceding, k.Anchor, k.Leaving} {
+				be.PutUint32(record[2+8*i:], photoshopFixed(pt[1]))
+				be.PutUint32(record[6+8*i:], photoshopFixed(pt[0]))
+			}
+			buf.Write(record)
+		}
+	}
+	return buf.Bytes()
+}
+
+// PhotoshopData returns the raw image resource blocks of the Photoshop tag.
+func (p *IFD) PhotoshopData() ([]byte, bool) {
+	return p.TagGetter().GetPhotoshop()
+}
+
+// Photoshop returns the parsed image resource blocks of the Photoshop tag.
+// It returns nil and no error if the image has no Photoshop tag.
+func (p *IFD) Photoshop() (*PhotoshopResources, error) {
+	data, ok := p.PhotoshopData()
+	if !ok {
+		return nil, nil
+	}
+	return ParsePhotoshop(data)
+}
+
+// UpdatePhotoshop replaces the Photoshop tag of the i-th image of the file
+// f, or adds one if it has none. A nil data removes the Photoshop tag.
+// The file is edited as by UpdateXMP.
+func UpdatePhotoshop(f io.ReadWriteSeeker, i int, data []byte) error {
+	if data == nil {
+		return editIFD(f, i, nil, TagType_Photoshop)
+	}
+	return editIFD(f, i, []ifdEntry{{TagType_Photoshop, DataType_Byte, bytesData(data)}})
+}
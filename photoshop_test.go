@@ -0,0 +1,129 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"image"
+	"reflect"
+	"testing"
+)
+
+func TestPhotoshop_read(t *testing.T) {
+	p := openGeoTiff(t, "./testdata/www.fileformat.info/GMARBLES.TIF")
+	defer p.Close()
+	ps, err := p.ImagePhotoshop(0, 0)
+	if err != nil || ps == nil {
+		t.Fatalf("Photoshop = %v, %v", ps, err)
+	}
+	var ids []PhotoshopResourceID
+	for _, r := range ps.Resources {
+		ids = append(ids, r.ID)
+	}
+	want := []PhotoshopResourceID{0x03E9, PhotoshopResourceID_ResolutionInfo, 0x03F3, 0x2710, 0x03F4, 0x03F7}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("resources = %v, want %v", ids, want)
+	}
+	if res, ok := ps.ResolutionInfo(); !ok || res != NewResolutionDPI(300, 300) {
+		t.Errorf("ResolutionInfo = %v, %v", res, ok)
+	}
+	if x, err := p.ImageIPTC(0, 0); x != nil || err != nil {
+		t.Errorf("IPTC = %v, %v", x, err)
+	}
+
+	data, _ := p.Ifd[0][0].PhotoshopData()
+	if b := ps.Bytes(); !bytes.Equal(b, data) {
+		t.Errorf("Bytes roundtrip:\n%q\n%q", b, data)
+	}
+}
+
+func TestPhotoshop_resources(t *testing.T) {
+	ps := &PhotoshopResources{}
+	ps.SetResolutionInfo(NewResolutionDPCM(118, 59))
+	x := &IPTC{}
+	x.SetCaption("In a resource")
+	ps.SetIPTC(x)
+	ps.Set(PhotoshopResourceID_Thumbnail, append([]byte{
+		0, 0, 0, 1, 0, 0, 0, 16, 0, 0, 0, 8, 0, 0, 0, 48, 0, 0, 1, 128, 0, 0, 0, 3, 0, 24, 0, 1,
+	}, 0xff, 0xd8, 0xff))
+	path := PhotoshopPath{Subpaths: []PhotoshopSubpath{{
+		Closed: true,
+		Knots: []PhotoshopKnot{
+			{Linked: true, Preceding: [2]float64{0.25, 0.25}, Anchor: [2]float64{0.25, 0.25}, Leaving: [2]float64{0.25, 0.25}},
+			{Preceding: [2]float64{0.75, 0.5}, Anchor: [2]float64{0.75, 0.5}, Leaving: [2]float64{0.75, 0.5}},
+		},
+	}}}
+	ps.Resources = append(ps.Resources,
+		PhotoshopResource{ID: PhotoshopResourceID_PathFirst, Name: "Outline", Data: path.Bytes()},
+		PhotoshopResource{ID: PhotoshopResourceID_ClippingPathName, Data: []byte("\x07Outline\x00\x00")},
+	)
+
+	ps2, err := ParsePhotoshop(ps.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ps, ps2) {
+		t.Errorf("Bytes roundtrip:\n%v\n%v", ps, ps2)
+	}
+	if res, ok := ps2.ResolutionInfo(); !ok || res != NewResolutionDPCM(118, 59) {
+		t.Errorf("ResolutionInfo = %v, %v", res, ok)
+	}
+	if got, err := ps2.IPTC(); err != nil || !reflect.DeepEqual(got, x) {
+		t.Errorf("IPTC = %v, %v", got, err)
+	}
+	if th, ok := ps2.Thumbnail(); !ok || th.Width != 16 || th.Height != 8 || len(th.JPEG) != 3 || th.BGR {
+		t.Errorf("Thumbnail = %+v, %v", th, ok)
+	}
+	clip, ok := ps2.ClippingPath()
+	path.ID, path.Name = PhotoshopResourceID_PathFirst, "Outline"
+	if !ok || !reflect.DeepEqual(clip, path) {
+		t.Errorf("ClippingPath = %+v, %v", clip, ok)
+	}
+
+	ps2.Delete(PhotoshopResourceID_Thumbnail)
+	if _, ok := ps2.Thumbnail(); ok {
+		t.Errorf("Thumbnail after Delete")
+	}
+	for _, s := range []string{"8BIM\x04", "8BIM\x04\x04\x00\x00\x00\x00\x00\x09", "XXXX\x04\x04\x00\x00\x00\x00\x00\x00"} {
+		if _, err := ParsePhotoshop([]byte(s)); err == nil {
+			t.Errorf("%q: no error", s)
+		}
+	}
+}
+
+func TestUpdatePhotoshop(t *testing.T) {
+	var out bytes.Buffer
+	if err := Encode(&out, image.NewGray(image.Rect(0, 0, 8, 8)), nil); err != nil {
+		t.Fatal(err)
+	}
+	f := &memFile{data: out.Bytes()}
+
+	// The IPTC stream is read from the Photoshop tag without an IPTC tag.
+	x := &IPTC{}
+	x.SetByline("Photographer")
+	ps := &PhotoshopResources{}
+	ps.SetIPTC(x)
+	if err := UpdatePhotoshop(f, 0, ps.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	p := f.open(t)
+	defer p.Close()
+	got, err := p.ImageIPTC(0, 0)
+	if err != nil || !reflect.DeepEqual(got, x) {
+		t.Errorf("IPTC = %v, %v", got, err)
+	}
+
+	// An IPTC tag takes precedence.
+	x2 := &IPTC{}
+	x2.SetByline("Editor")
+	if err = UpdateIPTC(f, 0, x2.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	p2 := f.open(t)
+	defer p2.Close()
+	if got, err := p2.ImageIPTC(0, 0); err != nil || !reflect.DeepEqual(got.Byline(), []string{"Editor"}) {
+		t.Errorf("IPTC = %v, %v", got, err)
+	}
+}
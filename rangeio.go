@@ -0,0 +1,152 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"container/list"
+	"fmt"
+	"io"
+)
+
+const (
+	// rangeBlockSize is the unit in which a rangeReader reads and caches
+	// data. It is large enough to hold the header and the IFDs of most
+	// cloud optimized files in the first block.
+	rangeBlockSize = 16 << 10
+
+	// rangeCacheBlocks is the number of blocks a rangeReader keeps.
+	rangeCacheBlocks = 256
+)
+
+// A rangeReader is an io.ReadSeeker over an io.ReaderAt, such as a remote
+// file read with HTTP range requests. It caches the data it has read in
+// blocks, and reads each run of missing blocks that a Read needs with a
+// single ReadAt call.
+type rangeReader struct {
+	r    io.ReaderAt
+	size int64
+	off  int64
+
+	blocks map[int64]*list.Element // Of *rangeBlock, by block index.
+	lru    *list.List              // Most recently used first.
+}
+
+type rangeBlock struct {
+	index int64
+	data  []byte
+}
+
+func newRangeReader(r io.ReaderAt, size int64) *rangeReader {
+	return &rangeReader{
+		r:      r,
+		size:   size,
+		blocks: make(map[int64]*list.Element),
+		lru:    list.New(),
+	}
+}
+
+func (p *rangeReader) Read(data []byte) (n int, err error) {
+	if p.off >= p.size {
+		return 0, io.EOF
+	}
+	if int64(len(data)) > p.size-p.off {
+		data = data[:p.size-p.off]
+	}
+	if len(data) == 0 {
+		return 0, nil
+	}
+
+	first := p.off / rangeBlockSize
+	last := (p.off + int64(len(data)) - 1) / rangeBlockSize
+	for i := first; i <= last; {
+		// Find the run of blocks [i, j] that are all cached or all missing.
+		_, cached := p.blocks[i]
+		j := i
+		for j < last {
+			if _, ok := p.blocks[j+1]; ok != cached {
+				break
+			}
+			j++
+		}
+		start := i * rangeBlockSize
+		var run []byte
+		if cached {
+			for k := i; k <= j; k++ {
+				e := p.blocks[k]
+				p.lru.MoveToFront(e)
+				run = append(run, e.Value.(*rangeBlock).data...)
+			}
+		} else if run, err = p.fetch(i, j); err != nil {
+			return
+		}
+		skip := int64(0)
+		if p.off > start {
+			skip = p.off - start
+		}
+		m := copy(data[n:], run[skip:])
+		n += m
+		p.off += int64(m)
+		i = j + 1
+	}
+	return
+}
+
+// fetch reads the blocks [first, last] with a single ReadAt and caches them.
+func (p *rangeReader) fetch(first, last int64) (data []byte, err error) {
+	start, end := first*rangeBlockSize, (last+1)*rangeBlockSize
+	if end > p.size {
+		end = p.size
+	}
+	data = make([]byte, end-start)
+	n, err := p.r.ReadAt(data, start)
+	if n == len(data) {
+		err = nil
+	} else if err == nil || err == io.EOF {
+		err = fmt.Errorf("tiff: short read at offset %d: %d of %d bytes", start, n, len(data))
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := first; i <= last; i++ {
+		b := data[(i-first)*rangeBlockSize:]
+		if len(b) > rangeBlockSize {
+			b = b[:rangeBlockSize]
+		}
+		p.blocks[i] = p.lru.PushFront(&rangeBlock{index: i, data: b})
+	}
+	for p.lru.Len() > rangeCacheBlocks {
+		e := p.lru.Back()
+		p.lru.Remove(e)
+		delete(p.blocks, e.Value.(*rangeBlock).index)
+	}
+	return data, nil
+}
+
+func (p *rangeReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += p.off
+	case io.SeekEnd:
+		offset += p.size
+	default:
+		return p.off, ErrInvalidWhence
+	}
+	if offset < 0 {
+		return p.off, ErrNegativeOffset
+	}
+	p.off = offset
+	return offset, nil
+}
+
+// Close drops the cache and closes the underlying reader if it is an io.Closer.
+func (p *rangeReader) Close() error {
+	p.blocks = make(map[int64]*list.Element)
+	p.lru.Init()
+	if closer, ok := p.r.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
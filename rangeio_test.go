@@ -0,0 +1,172 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// httpReaderAt reads a remote file with HTTP range requests.
+type httpReaderAt struct {
+	url string
+}
+
+func (p *httpReaderAt) ReadAt(data []byte, off int64) (int, error) {
+	req, err := http.NewRequest("GET", p.url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", off, off+int64(len(data))-1))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0, fmt.Errorf("%s: %s", p.url, resp.Status)
+	}
+	return io.ReadFull(resp.Body, data)
+}
+
+// rangeServer serves a file and records the ranges requested from it.
+type rangeServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	ranges []string
+}
+
+func newRangeServer(data []byte) *rangeServer {
+	s := &rangeServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.ranges = append(s.ranges, r.Header.Get("Range"))
+		s.mu.Unlock()
+		http.ServeContent(w, r, "test.tif", time.Time{}, bytes.NewReader(data))
+	}))
+	return s
+}
+
+// requests returns the ranges requested since the last call.
+func (s *rangeServer) requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ranges := s.ranges
+	s.ranges = nil
+	return ranges
+}
+
+func TestOpenReaderAt_http(t *testing.T) {
+	m := image.NewRGBA(image.Rect(0, 0, 1100, 700))
+	for i := range m.Pix {
+		m.Pix[i] = uint8(i * i / 7)
+	}
+	var file bytes.Buffer
+	if err := EncodeCOG(&file, m, &Options{TileWidth: 256, TileLength: 256}, nil); err != nil {
+		t.Fatal(err)
+	}
+	s := newRangeServer(file.Bytes())
+	defer s.Close()
+
+	p, err := OpenReaderAt(&httpReaderAt{s.URL}, int64(file.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	if r := s.requests(); len(r) != 1 || r[0] != fmt.Sprintf("bytes=0-%d", rangeBlockSize-1) {
+		t.Fatalf("opening the file requested %q", r)
+	}
+	if len(p.Ifd) != 4 {
+		t.Fatalf("got %d IFDs, want 4", len(p.Ifd))
+	}
+
+	local, err := OpenReader(bytes.NewReader(file.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer local.Close()
+
+	const col, row = 2, 1
+	got, err := p.DecodeImageBlock(0, 0, col, row)
+	if err != nil {
+		t.Fatal(err)
+	}
+	offset := p.Ifd[0][0].BlockOffset(col, row)
+	count := p.Ifd[0][0].BlockCount(col, row)
+	r := s.requests()
+	if len(r) != 1 {
+		t.Fatalf("decoding a tile requested %q", r)
+	}
+	var start, end int64
+	if _, err := fmt.Sscanf(r[0], "bytes=%d-%d", &start, &end); err != nil {
+		t.Fatal(err)
+	}
+	if start > offset || end < offset+count-1 || end-start+1 > count+2*rangeBlockSize {
+		t.Fatalf("decoding tile [%d, %d) requested %s", offset, offset+count, r[0])
+	}
+	want, err := local.DecodeImageBlock(0, 0, col, row)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compare(t, want, got)
+
+	// Cached blocks are not requested again.
+	if _, err = p.DecodeImageBlock(0, 0, col, row); err != nil {
+		t.Fatal(err)
+	}
+	if r := s.requests(); len(r) != 0 {
+		t.Fatalf("decoding a cached tile requested %q", r)
+	}
+
+	for i := range p.Ifd {
+		got, err := p.DecodeImage(i, 0)
+		if err != nil {
+			t.Fatalf("IFD %d: %v", i, err)
+		}
+		want, err := local.DecodeImage(i, 0)
+		if err != nil {
+			t.Fatalf("IFD %d: %v", i, err)
+		}
+		compare(t, want, got)
+	}
+}
+
+func TestRangeReader(t *testing.T) {
+	data := make([]byte, 5*rangeBlockSize+123)
+	for i := range data {
+		data[i] = byte(i * 31)
+	}
+	r := newRangeReader(bytes.NewReader(data), int64(len(data)))
+	for _, tt := range []struct{ off, n int64 }{
+		{0, 10},
+		{rangeBlockSize - 5, 10},
+		{3, 3 * rangeBlockSize},
+		{int64(len(data)) - 50, 100},
+		{0, int64(len(data))},
+	} {
+		if _, err := r.Seek(tt.off, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		buf := make([]byte, tt.n)
+		n, err := io.ReadFull(r, buf)
+		want := data[tt.off:]
+		if int64(len(want)) > tt.n {
+			want = want[:tt.n]
+		}
+		if !bytes.Equal(buf[:n], want) {
+			t.Errorf("read %d bytes at %d: got wrong data (err %v)", tt.n, tt.off, err)
+		}
+	}
+	if n, err := r.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Errorf("read at EOF = %d, %v", n, err)
+	}
+}
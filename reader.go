@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"image"
 	"io"
+	"runtime"
+	"sync"
 )
 
 type Reader struct {
@@ -15,18 +17,46 @@ type Reader struct {
 	Header *Header
 	Ifd    [][]*IFD
 
+	// Workers is the number of goroutines that decompress the blocks of
+	// DecodeImage and DecodeRegion. The compressed data is still read one
+	// block at a time. If it is 0 or 1, blocks are decoded one after
+	// another; if it is negative, runtime.GOMAXPROCS(0) goroutines are
+	// used. The decoded image does not depend on it.
+	Workers int
+
 	rs *seekioReader
 }
 
+// OpenReader reads the header and the IFDs of the file read from r. If a
+// link between IFDs is broken, it returns the first *IFDError together with
+// a Reader of the IFDs that could be read, which has to be closed.
 func OpenReader(r io.Reader) (p *Reader, err error) {
-	rs := openSeekioReader(r, -1)
+	return openReader(openSeekioReader(r, -1), nil)
+}
+
+// OpenReaderAt opens a file of the given size that is read from r, such as
+// a remote file accessed with HTTP range requests. Unlike OpenReader, it
+// never reads the whole file: the data is fetched in blocks as the header,
+// the IFDs and the image blocks are decoded, and kept in a bounded cache.
+// Opening a cloud optimized file thus reads only its first blocks, and
+// DecodeImageBlock fetches a single tile with one ReadAt call.
+func OpenReaderAt(r io.ReaderAt, size int64) (p *Reader, err error) {
+	return openReader(&seekioReader{rs: newRangeReader(r, size)}, nil)
+}
+
+// openReader reads the header and the IFDs of a file, within the limits of
+// opt, which may be nil. A broken SubIFD link is skipped and a broken next
+// IFD link ends the chain; the Reader is then returned with the first
+// *IFDError and the IFDs that could be read.
+func openReader(rs *seekioReader, opt *DecodeOptions) (p *Reader, err error) {
 	defer func() {
-		if err != nil && rs != nil {
+		if _, broken := err.(*IFDError); err != nil && !broken {
 			rs.Close()
+			p = nil
 		}
 	}()
 
-	p = &Reader{}
+	p = &Reader{Reader: rs, rs: rs}
 	if p.Header, err = ReadHeader(rs); err != nil {
 		return
 	}
@@ -34,30 +64,103 @@ func OpenReader(r io.Reader) (p *Reader, err error) {
 		err = fmt.Errorf("tiff: OpenReader, invalid header: %v", p.Header)
 		return
 	}
+	size, err := rs.Seek(0, io.SeekEnd)
+	if err != nil {
+		return
+	}
+	opt = opt.withFileSize(size)
+
+	// Every IFD is read once, so that IFDs linking back to an IFD already
+	// read do not make us loop forever.
+	visited := make(map[int64]bool)
+	checkIFD := func(offset, parent int64, subIFD bool) error {
+		if visited[offset] || offset < int64(p.Header.HeadSize()) || offset >= size {
+			return &IFDError{Offset: offset, Parent: parent, SubIFD: subIFD, Cycle: visited[offset]}
+		}
+		visited[offset] = true
+		return nil
+	}
 
-	for offset := p.Header.FirstIFD; offset != 0; {
+	var ifdNum int
+	var broken error
+	defer func() {
+		if err == nil {
+			err = broken
+		}
+	}()
+	for offset, parent := p.Header.FirstIFD, int64(0); offset != 0; {
 		var ifd *IFD
 		var ifdList []*IFD
 
-		if ifd, err = ReadIFD(rs, p.Header, offset); err != nil {
+		ifdNum++
+		if err = opt.checkIFDs(ifdNum); err != nil {
+			return
+		}
+		if e := checkIFD(offset, parent, false); e != nil {
+			if broken == nil {
+				broken = e
+			}
 			return
 		}
+		if ifd, err = readIFDLimited(rs, p.Header, offset, opt); err != nil {
+			return
+		}
+		ifdNum += ifd.readPrivateIFDs(rs)
 		ifdList = append(ifdList, ifd)
-		offset = ifd.NextIFD
+		offset, parent = ifd.NextIFD, offset
 
 		subIfdOffsets, _ := ifd.TagGetter().GetSubIFD()
+		ifdNum += len(subIfdOffsets)
+		if err = opt.checkIFDs(ifdNum); err != nil {
+			return
+		}
 		for _, subOffset := range subIfdOffsets {
-			ifd, _ = ReadIFD(rs, p.Header, subOffset)
+			if e := checkIFD(subOffset, parent, true); e != nil {
+				// The SubIFD is skipped, but the next IFDs are still read.
+				if broken == nil {
+					broken = e
+				}
+				continue
+			}
+			ifd, _ = readIFDLimited(rs, p.Header, subOffset, opt)
+			if ifd != nil {
+				ifdNum += ifd.readPrivateIFDs(rs)
+				if err = opt.checkIFDs(ifdNum); err != nil {
+					return
+				}
+			}
 			ifdList = append(ifdList, ifd)
 		}
 		p.Ifd = append(p.Ifd, ifdList)
 	}
-
-	p.Reader = rs
-	p.rs = rs
 	return
 }
 
+// An IFDError reports a link to an IFD that cannot be followed, because it
+// lies outside the file or leads back to an IFD already read. OpenReader
+// returns it with a Reader holding the IFDs that could be read.
+type IFDError struct {
+	Offset int64 // The offset of the IFD.
+	Parent int64 // The offset of the IFD that links to it, or 0 for the header.
+	SubIFD bool  // Whether it is a SubIFD of Parent, rather than its next IFD.
+	Cycle  bool  // Whether it was already read, rather than outside the file.
+}
+
+func (e *IFDError) Error() string {
+	kind := "IFD"
+	if e.SubIFD {
+		kind = "SubIFD"
+	}
+	from := fmt.Sprintf("IFD %d", e.Parent)
+	if e.Parent == 0 {
+		from = "the header"
+	}
+	if e.Cycle {
+		return fmt.Sprintf("tiff: OpenReader, %s %d linked from %s was already read", kind, e.Offset, from)
+	}
+	return fmt.Sprintf("tiff: OpenReader, %s %d linked from %s is out of range", kind, e.Offset, from)
+}
+
 func (p *Reader) ImageNum() int {
 	return len(p.Ifd)
 }
@@ -69,6 +172,61 @@ func (p *Reader) ImageConfig(i, j int) (image.Config, error) {
 	return p.Ifd[i][j].ImageConfig()
 }
 
+// ImageResolution returns the physical resolution of the image, which
+// complements the pixel dimensions returned by ImageConfig.
+func (p *Reader) ImageResolution(i, j int) (Resolution, bool) {
+	return p.Ifd[i][j].Resolution()
+}
+
+// ImageGeoKeyDirectory returns the GeoKey directory of a GeoTIFF image,
+// or nil if the image has none.
+func (p *Reader) ImageGeoKeyDirectory(i, j int) (*GeoKeyDirectory, error) {
+	return p.Ifd[i][j].GeoKeyDirectory()
+}
+
+// ImageGeoTransform returns the affine transform from raster to model
+// space of a GeoTIFF image.
+func (p *Reader) ImageGeoTransform(i, j int) (GeoTransform, bool) {
+	return p.Ifd[i][j].GeoTransform()
+}
+
+// ImageGDALMetadata returns the GDAL metadata of an image, or nil.
+func (p *Reader) ImageGDALMetadata(i, j int) (*GDALMetadata, error) {
+	return p.Ifd[i][j].GDALMetadata()
+}
+
+// ImageGDALNoData returns the GDAL nodata value of an image.
+func (p *Reader) ImageGDALNoData(i, j int) (float64, bool) {
+	return p.Ifd[i][j].GDALNoData()
+}
+
+// ImageExif returns the EXIF IFD of an image, or nil.
+func (p *Reader) ImageExif(i, j int) *ExifIFD {
+	return p.Ifd[i][j].Exif
+}
+
+// ImageGPS returns the GPS IFD of an image, or nil.
+func (p *Reader) ImageGPS(i, j int) *GPSIFD {
+	return p.Ifd[i][j].GPS
+}
+
+// ImageXMP returns the parsed XMP packet of an image, or nil.
+func (p *Reader) ImageXMP(i, j int) (*XMP, error) {
+	return p.Ifd[i][j].XMP()
+}
+
+func (p *Reader) ImageIPTC(i, j int) (*IPTC, error) {
+	return p.Ifd[i][j].IPTC()
+}
+
+func (p *Reader) ImagePhotoshop(i, j int) (*PhotoshopResources, error) {
+	return p.Ifd[i][j].Photoshop()
+}
+
+func (p *Reader) ImageICCProfile(i, j int) (*ICCProfile, error) {
+	return p.Ifd[i][j].ICCProfile()
+}
+
 func (p *Reader) ImageBlocksAcross(i, j int) int {
 	return p.Ifd[i][j].BlocksAcross()
 }
@@ -91,16 +249,16 @@ func (p *Reader) DecodeImage(i, j int) (m image.Image, err error) {
 		return
 	}
 
-	blocksAcross := p.ImageBlocksAcross(i, j)
-	blocksDown := p.ImageBlocksDown(i, j)
-
-	for col := 0; col < blocksAcross; col++ {
-		for row := 0; row < blocksDown; row++ {
-			if err = p.Ifd[i][j].DecodeBlock(p.rs, col, row, m); err != nil {
-				return
-			}
+	ifd := p.Ifd[i][j]
+	var blocks []image.Point
+	for row := 0; row < ifd.BlocksDown(); row++ {
+		for col := 0; col < ifd.BlocksAcross(); col++ {
+			blocks = append(blocks, image.Pt(col, row))
 		}
 	}
+	err = p.decodeBlocks(ifd, blocks, func(col, row int, data []byte) error {
+		return ifd.decodeBlockData(data, col, row, m)
+	})
 	return
 }
 
@@ -115,6 +273,123 @@ func (p *Reader) DecodeImageBlock(i, j, col, row int) (m image.Image, err error)
 	return
 }
 
+// DecodeRegion decodes the rectangle r of the image, which is clipped to
+// the image bounds. Only the strips or tiles that intersect r are read and
+// decoded, so that small regions of very large images are cheap to read.
+// The returned image has the bounds of the clipped rectangle.
+func (p *Reader) DecodeRegion(i, j int, r image.Rectangle) (m image.Image, err error) {
+	cfg, err := p.ImageConfig(i, j)
+	if err != nil {
+		return
+	}
+	ifd := p.Ifd[i][j]
+	if r = r.Intersect(image.Rect(0, 0, cfg.Width, cfg.Height)); r.Empty() {
+		err = fmt.Errorf("tiff: Reader.DecodeRegion, empty region")
+		return
+	}
+	if m, err = newImageWithIFD(r, ifd); err != nil {
+		return
+	}
+
+	// Blocks all have the size of the first one, but the last strip.
+	size := ifd.BlockBounds(0, 0).Size()
+	if size.X <= 0 || size.Y <= 0 {
+		err = fmt.Errorf("tiff: Reader.DecodeRegion, bad block size %v", size)
+		return
+	}
+	col1 := minInt((r.Max.X-1)/size.X, ifd.BlocksAcross()-1)
+	row1 := minInt((r.Max.Y-1)/size.Y, ifd.BlocksDown()-1)
+	var blocks []image.Point
+	for row := r.Min.Y / size.Y; row <= row1; row++ {
+		for col := r.Min.X / size.X; col <= col1; col++ {
+			blocks = append(blocks, image.Pt(col, row))
+		}
+	}
+	err = p.decodeBlocks(ifd, blocks, func(col, row int, data []byte) error {
+		b := ifd.BlockBounds(col, row)
+		if b.Intersect(image.Rect(0, 0, cfg.Width, cfg.Height)).In(r) {
+			return ifd.decodeBlockData(data, col, row, m)
+		}
+		// Blocks that cross the border of the region are decoded on
+		// their own and cropped.
+		block, err := newImageWithIFD(b, ifd)
+		if err != nil {
+			return err
+		}
+		if err = ifd.decodeBlockData(data, col, row, block); err != nil {
+			return err
+		}
+		copyPixels(m, block, b.Intersect(r))
+		return nil
+	})
+	return
+}
+
+// decodeBlocks calls decode with the compressed data of the given blocks
+// of ifd, on p.Workers goroutines. The data is read under a lock, as the
+// blocks share the seeker of the file, and decode must only write the
+// pixels of its block. The error of the first failing block in the order
+// of blocks is returned, whatever the number of workers.
+func (p *Reader) decodeBlocks(ifd *IFD, blocks []image.Point, decode func(col, row int, data []byte) error) error {
+	workers := p.Workers
+	if workers < 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers <= 1 || len(blocks) <= 1 {
+		for _, b := range blocks {
+			data, err := ifd.readBlock(p.rs, b.X, b.Y)
+			if err != nil {
+				return err
+			}
+			if err = decode(b.X, b.Y, data); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var (
+		mu     sync.Mutex // Guards p.rs and failed.
+		wg     sync.WaitGroup
+		failed = len(blocks) // The index of the first failing block.
+		errs   = make([]error, len(blocks))
+		next   = make(chan int)
+	)
+	for w := 0; w < minInt(workers, len(blocks)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range next {
+				mu.Lock()
+				if k > failed {
+					// The blocks after a failing one are not needed.
+					mu.Unlock()
+					continue
+				}
+				data, err := ifd.readBlock(p.rs, blocks[k].X, blocks[k].Y)
+				mu.Unlock()
+				if err == nil {
+					err = decode(blocks[k].X, blocks[k].Y, data)
+				}
+				if err != nil {
+					mu.Lock()
+					errs[k], failed = err, minInt(failed, k)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for k := range blocks {
+		next <- k
+	}
+	close(next)
+	wg.Wait()
+	if failed < len(blocks) {
+		return errs[failed]
+	}
+	return nil
+}
+
 func (p *Reader) Close() (err error) {
 	if p != nil {
 		if p.rs != nil {
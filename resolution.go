@@ -0,0 +1,149 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"fmt"
+	"math"
+)
+
+// A Resolution is the number of pixels per Unit in the X and Y directions
+// of an image. X and Y are kept as the numerator and denominator of the
+// XResolution and YResolution tags, so no precision is lost on a round trip.
+type Resolution struct {
+	X    [2]int64
+	Y    [2]int64
+	Unit TagValue_ResolutionUnitType
+}
+
+// NewResolution returns the resolution of x by y pixels per unit,
+// approximating both values by the closest 32-bit rationals.
+func NewResolution(x, y float64, unit TagValue_ResolutionUnitType) Resolution {
+	return Resolution{
+		X:    floatToRational(x),
+		Y:    floatToRational(y),
+		Unit: unit,
+	}
+}
+
+// NewResolutionDPI returns a resolution in dots per inch.
+func NewResolutionDPI(x, y float64) Resolution {
+	return NewResolution(x, y, TagValue_ResolutionUnitType_PerInch)
+}
+
+// NewResolutionDPCM returns a resolution in dots per centimeter.
+func NewResolutionDPCM(x, y float64) Resolution {
+	return NewResolution(x, y, TagValue_ResolutionUnitType_PerCM)
+}
+
+func (r Resolution) Valid() bool {
+	for _, v := range [][2]int64{r.X, r.Y} {
+		if v[0] < 0 || v[0] > math.MaxUint32 || v[1] <= 0 || v[1] > math.MaxUint32 {
+			return false
+		}
+	}
+	switch r.Unit {
+	case TagValue_ResolutionUnitType_None, TagValue_ResolutionUnitType_PerInch, TagValue_ResolutionUnitType_PerCM:
+		return true
+	}
+	return false
+}
+
+// PixelsPerUnit returns the resolution as floating point values.
+func (r Resolution) PixelsPerUnit() (x, y float64) {
+	if !r.Valid() {
+		return
+	}
+	x = float64(r.X[0]) / float64(r.X[1])
+	y = float64(r.Y[0]) / float64(r.Y[1])
+	return
+}
+
+// DPI returns the resolution in dots per inch. ok is false if the
+// resolution has no absolute unit.
+func (r Resolution) DPI() (x, y float64, ok bool) {
+	x, y = r.PixelsPerUnit()
+	switch {
+	case !r.Valid():
+		return 0, 0, false
+	case r.Unit == TagValue_ResolutionUnitType_PerCM:
+		return x * 2.54, y * 2.54, true
+	case r.Unit == TagValue_ResolutionUnitType_PerInch:
+		return x, y, true
+	}
+	return 0, 0, false
+}
+
+// DPCM returns the resolution in dots per centimeter. ok is false if the
+// resolution has no absolute unit.
+func (r Resolution) DPCM() (x, y float64, ok bool) {
+	if x, y, ok = r.DPI(); ok {
+		x, y = x/2.54, y/2.54
+	}
+	return
+}
+
+// PhysicalSize returns the size of a width x height image in Unit.
+// The size is zero in a direction whose resolution is zero.
+func (r Resolution) PhysicalSize(width, height int) (w, h float64) {
+	x, y := r.PixelsPerUnit()
+	if x > 0 {
+		w = float64(width) / x
+	}
+	if y > 0 {
+		h = float64(height) / y
+	}
+	return
+}
+
+func (r Resolution) String() string {
+	return fmt.Sprintf("tiff.Resolution{X: %d/%d, Y: %d/%d, Unit: %v}",
+		r.X[0], r.X[1], r.Y[0], r.Y[1], r.Unit,
+	)
+}
+
+// Resolution returns the resolution stored in the XResolution, YResolution
+// and ResolutionUnit tags. ok is false if either resolution tag is missing.
+func (p *IFD) Resolution() (r Resolution, ok bool) {
+	if r.X, ok = p.TagGetter().GetXResolution(); !ok {
+		return
+	}
+	if r.Y, ok = p.TagGetter().GetYResolution(); !ok {
+		return
+	}
+	if r.Unit, ok = p.TagGetter().GetResolutionUnit(); !ok {
+		return
+	}
+	ok = r.Valid()
+	return
+}
+
+// floatToRational returns the fraction closest to v whose numerator and
+// denominator fit into the 32-bit fields of the RATIONAL data type,
+// using the continued fraction expansion of v.
+func floatToRational(v float64) [2]int64 {
+	switch {
+	case !(v > 0):
+		return [2]int64{0, 1}
+	case v >= math.MaxUint32:
+		return [2]int64{math.MaxUint32, 1}
+	}
+	h0, h1 := int64(0), int64(1)
+	k0, k1 := int64(1), int64(0)
+	for x := v; ; {
+		a := math.Floor(x)
+		if a*float64(h1)+float64(h0) > math.MaxUint32 || a*float64(k1)+float64(k0) > math.MaxUint32 {
+			break
+		}
+		h0, h1 = h1, int64(a)*h1+h0
+		k0, k1 = k1, int64(a)*k1+k0
+		if f := x - a; f < 1e-12 || float64(h1)/float64(k1) == v {
+			break
+		} else {
+			x = 1 / f
+		}
+	}
+	return [2]int64{h1, k1}
+}
@@ -0,0 +1,89 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"image"
+	"math"
+	"os"
+	"testing"
+)
+
+func TestFloatToRational(t *testing.T) {
+	tests := []struct {
+		v    float64
+		want [2]int64
+	}{
+		{0, [2]int64{0, 1}},
+		{-1, [2]int64{0, 1}},
+		{72, [2]int64{72, 1}},
+		{0.5, [2]int64{1, 2}},
+		{300.25, [2]int64{1201, 4}},
+		{118.11, [2]int64{11811, 100}},
+		{1e10, [2]int64{math.MaxUint32, 1}},
+	}
+	for _, tt := range tests {
+		if got := floatToRational(tt.v); got != tt.want {
+			t.Errorf("floatToRational(%v) = %v, want %v", tt.v, got, tt.want)
+		}
+	}
+	if v := floatToRational(math.Pi); math.Abs(float64(v[0])/float64(v[1])-math.Pi) > 1e-15 {
+		t.Errorf("floatToRational(Pi) = %v", v)
+	}
+}
+
+func TestResolution_read(t *testing.T) {
+	f, err := os.Open(testdataDir + "bw-deflate.tiff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	p, err := OpenReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	r, ok := p.ImageResolution(0, 0)
+	if !ok {
+		t.Fatal("no resolution")
+	}
+	if x, y, ok := r.DPI(); !ok || x != 72 || y != 72 {
+		t.Fatalf("DPI = %v, %v, %v, want 72, 72, true", x, y, ok)
+	}
+	if w, h := r.PhysicalSize(153, 55); w != 153.0/72 || h != 55.0/72 {
+		t.Fatalf("PhysicalSize = %v, %v", w, h)
+	}
+}
+
+func TestResolution_roundtrip(t *testing.T) {
+	resolutions := []Resolution{
+		NewResolutionDPI(300, 600),
+		NewResolutionDPCM(118.11, 47.25),
+		{X: [2]int64{2400001, 7}, Y: [2]int64{1, 3}, Unit: TagValue_ResolutionUnitType_None},
+	}
+	m := image.NewGray(image.Rect(0, 0, 4, 4))
+	for i, want := range resolutions {
+		var buf bytes.Buffer
+		if err := Encode(&buf, m, &Options{Resolution: &want}); err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		p, err := OpenReader(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		got, ok := p.ImageResolution(0, 0)
+		if !ok || got != want {
+			t.Fatalf("%d: got %v, want %v", i, got, want)
+		}
+		p.Close()
+	}
+
+	bad := Resolution{X: [2]int64{1, 0}, Y: [2]int64{1, 1}, Unit: TagValue_ResolutionUnitType_PerInch}
+	if err := Encode(new(bytes.Buffer), m, &Options{Resolution: &bad}); err == nil {
+		t.Fatal("got nil error for bad resolution, want non-nil")
+	}
+}
@@ -0,0 +1,341 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"math"
+	"reflect"
+)
+
+// ScanlineReader decodes the rows of an image from top to bottom, one
+// band at a time: a band is a strip, or a row of tiles. Only the current
+// band is held in memory, so that images larger than the memory can be
+// converted row by row.
+type ScanlineReader struct {
+	p   *Reader
+	ifd *IFD
+	cfg image.Config
+
+	band image.Image // The current band, or nil.
+	row  int         // The index of the next band.
+	y    int         // The next row to be returned.
+	err  error
+}
+
+// ScanlineReader returns a ScanlineReader of the image, whose first band is
+// decoded by the first call of ReadRow or ReadBand.
+func (p *Reader) ScanlineReader(i, j int) (s *ScanlineReader, err error) {
+	ifd := p.Ifd[i][j]
+	cfg, err := ifd.ImageConfig()
+	if err != nil {
+		return
+	}
+	if _, err = newImageWithIFD(image.Rectangle{}, ifd); err != nil {
+		return
+	}
+	if ifd.BlockBounds(0, 0).Dy() <= 0 {
+		err = fmt.Errorf("tiff: Reader.ScanlineReader, bad block size %v", ifd.BlockBounds(0, 0).Size())
+		return
+	}
+	s = &ScanlineReader{p: p, ifd: ifd, cfg: cfg}
+	return
+}
+
+// Bounds returns the bounds of the image.
+func (s *ScanlineReader) Bounds() image.Rectangle {
+	return image.Rect(0, 0, s.cfg.Width, s.cfg.Height)
+}
+
+// Y returns the next row returned by ReadRow.
+func (s *ScanlineReader) Y() int {
+	return s.y
+}
+
+// ReadRow returns the next row of the image, as an image one pixel high
+// whose bounds are those of the row in the image. Its pixels are shared
+// with the current band. At the end of the image, ReadRow returns io.EOF.
+func (s *ScanlineReader) ReadRow() (m image.Image, err error) {
+	if err = s.fill(); err != nil {
+		return
+	}
+	r := image.Rect(0, s.y, s.cfg.Width, s.y+1)
+	s.y++
+	m = s.band.(interface {
+		SubImage(image.Rectangle) image.Image
+	}).SubImage(r)
+	return
+}
+
+// ReadBand returns the rows of the current band that were not returned
+// yet, decoding the next band if they all were. At the end of the image,
+// ReadBand returns io.EOF.
+func (s *ScanlineReader) ReadBand() (m image.Image, err error) {
+	if err = s.fill(); err != nil {
+		return
+	}
+	r := s.band.Bounds()
+	r.Min.Y = s.y
+	s.y = r.Max.Y
+	m = s.band.(interface {
+		SubImage(image.Rectangle) image.Image
+	}).SubImage(r)
+	return
+}
+
+// fill decodes the next band when the rows of the current one have all
+// been returned.
+func (s *ScanlineReader) fill() error {
+	if s.err != nil {
+		return s.err
+	}
+	if s.band != nil && s.y < s.band.Bounds().Max.Y {
+		return nil
+	}
+	if s.y >= s.cfg.Height || s.row >= s.ifd.BlocksDown() {
+		s.band, s.err = nil, io.EOF
+		return s.err
+	}
+
+	b := s.ifd.BlockBounds(0, s.row)
+	r := image.Rect(0, b.Min.Y, s.cfg.Width, minInt(b.Max.Y, s.cfg.Height))
+	band, err := newImageWithIFD(r, s.ifd)
+	if err != nil {
+		s.err = err
+		return err
+	}
+	var blocks []image.Point
+	for col := 0; col < s.ifd.BlocksAcross(); col++ {
+		blocks = append(blocks, image.Pt(col, s.row))
+	}
+	if err = s.p.decodeBlocks(s.ifd, blocks, func(col, row int, data []byte) error {
+		return s.ifd.decodeBlockData(data, col, row, band)
+	}); err != nil {
+		s.band, s.err = nil, err
+		return err
+	}
+	s.band, s.row, s.y = band, s.row+1, r.Min.Y
+	return nil
+}
+
+// ScanlineWriter writes an image to a file row by row, so that images
+// larger than the memory can be written. Rows are gathered into a band,
+// a strip or a row of tiles, which is compressed and written as soon as
+// it is full; the IFD is written by Close, after the image data.
+type ScanlineWriter struct {
+	w    io.WriteSeeker
+	base int64 // The offset of the header in w.
+	end  int64 // The offset of the end of the data, relative to base.
+
+	e       *imageEncoder
+	model   color.Model
+	opaque  bool
+	offsets []uint64
+	counts  []uint64
+
+	band image.Image // The current band.
+	row  int         // The index of the current band.
+	y    int         // The next row to be written.
+	err  error
+}
+
+// NewScanlineWriter returns a ScanlineWriter that writes an image of the
+// width and height of cfg to w, from its current position. The
+// format of the samples is set by cfg.ColorModel, which is one of:
+//
+//   - color.GrayModel and color.Gray16Model, for gray images of 8 and
+//     16 bits per sample;
+//   - color.RGBAModel and color.RGBA64Model, for opaque RGB images of 8
+//     and 16 bits per sample, which are written without alpha;
+//   - color.NRGBAModel and color.NRGBA64Model, for RGB images with an
+//     unassociated alpha channel;
+//   - a color.Palette, for paletted images.
+//
+// The options are those of Encode. As the size of the compressed data is
+// not known in advance, a BigTIFF file is written if opt.BigTiff is set or
+// if the uncompressed image data would not fit into a classic TIFF file.
+func NewScanlineWriter(w io.WriteSeeker, cfg image.Config, opt *Options) (s *ScanlineWriter, err error) {
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		err = fmt.Errorf("tiff: NewScanlineWriter, bad size %dx%d", cfg.Width, cfg.Height)
+		return
+	}
+	s = &ScanlineWriter{w: w, model: cfg.ColorModel}
+	proto, opaque, err := newModelImage(cfg.ColorModel, image.Rectangle{})
+	if err != nil {
+		return nil, err
+	}
+	s.opaque = opaque
+	s.e, err = newSizedEncoder(image.Pt(cfg.Width, cfg.Height), opt, func(order binary.ByteOrder) *pixelEncoder {
+		return newFormatEncoder(proto, order, opaque, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.offsets = make([]uint64, s.e.blockNum())
+	s.counts = make([]uint64, s.e.blockNum())
+
+	size := int64(s.e.header(0).HeadSize())
+	for i := 0; i < s.e.blockNum(); i++ {
+		size += int64(s.e.blockSize(i))
+	}
+	if size > math.MaxUint32 {
+		s.e.bigTiff = true
+	}
+
+	if s.base, err = w.Seek(0, io.SeekCurrent); err != nil {
+		return nil, err
+	}
+	// The header is left blank until Close knows the offset of the IFD.
+	s.end = int64(s.e.header(0).HeadSize())
+	if _, err = w.Write(make([]byte, s.end)); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// Y returns the next row written by WriteRows.
+func (s *ScanlineWriter) Y() int {
+	return s.y
+}
+
+// WriteRows writes the rows of m, which must be as wide as the image, as
+// the next rows of the image, whatever the vertical position of m.
+func (s *ScanlineWriter) WriteRows(m image.Image) error {
+	if s.err != nil {
+		return s.err
+	}
+	b := m.Bounds()
+	if b.Dx() != s.e.size.X {
+		return fmt.Errorf("tiff: ScanlineWriter.WriteRows, bad width %d, want %d", b.Dx(), s.e.size.X)
+	}
+	if s.y+b.Dy() > s.e.size.Y {
+		return fmt.Errorf("tiff: ScanlineWriter.WriteRows, %d rows past the end of the image", s.y+b.Dy()-s.e.size.Y)
+	}
+	for sy := b.Min.Y; sy < b.Max.Y; {
+		if s.band == nil {
+			r := s.e.blockBounds(s.row * s.e.blocksAcross)
+			r = image.Rect(0, r.Min.Y, s.e.size.X, minInt(r.Max.Y, s.e.size.Y))
+			s.band, _, _ = newModelImage(s.model, r)
+		}
+		n := minInt(b.Max.Y-sy, s.band.Bounds().Max.Y-s.y)
+		drawRows(s.band, s.y, m, image.Rect(b.Min.X, sy, b.Max.X, sy+n))
+		sy += n
+		s.y += n
+		if s.y == s.band.Bounds().Max.Y {
+			if err := s.flush(); err != nil {
+				s.err = err
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// flush compresses and writes the blocks of the current band.
+func (s *ScanlineWriter) flush() error {
+	band := newFormatEncoder(s.band, s.e.order, s.opaque, false)
+	y0 := s.band.Bounds().Min.Y
+	s.e.putRow = func(dst []byte, x0, x1, y int) {
+		band.putRow(dst, x0, x1, y-y0)
+	}
+	i0 := s.row * s.e.blocksAcross
+	blocks, counts, err := s.e.encodeBlockRange(i0, i0+s.e.blocksAcross, s.e.encodeBlock)
+	if err != nil {
+		return err
+	}
+	for k := range blocks {
+		if _, err = s.w.Write(blocks[k]); err != nil {
+			return err
+		}
+		s.offsets[i0+k], s.counts[i0+k] = uint64(s.end), counts[k]
+		s.end += int64(counts[k])
+	}
+	s.band = nil
+	s.row++
+	return nil
+}
+
+// Close writes the IFD of the image, then the header. It fails if
+// some rows of the image were not written. It does not close the
+// underlying writer.
+func (s *ScanlineWriter) Close() (err error) {
+	if s.err != nil {
+		return s.err
+	}
+	s.err = fmt.Errorf("tiff: ScanlineWriter, closed")
+	if s.y < s.e.size.Y {
+		return fmt.Errorf("tiff: ScanlineWriter.Close, %d of %d rows written", s.y, s.e.size.Y)
+	}
+
+	// The IFD has to begin on a word boundary.
+	ifdOffset := s.end + s.end%2
+	ifd := ifdBytes(s.e.header(ifdOffset), ifdOffset, 0, s.e.ifd(s.offsets, s.counts))
+	if !s.e.bigTiff && ifdOffset+int64(len(ifd)) > math.MaxUint32 {
+		return fmt.Errorf("tiff: ScanlineWriter.Close, file larger than 4 GiB, use Options.BigTiff")
+	}
+	if _, err = s.w.Write(make([]byte, ifdOffset-s.end)); err != nil {
+		return
+	}
+	if _, err = s.w.Write(ifd); err != nil {
+		return
+	}
+	if _, err = s.w.Seek(s.base, io.SeekStart); err != nil {
+		return
+	}
+	if _, err = s.w.Write(s.e.header(ifdOffset).Bytes()); err != nil {
+		return
+	}
+	_, err = s.w.Seek(s.base+ifdOffset+int64(len(ifd)), io.SeekStart)
+	return
+}
+
+// newModelImage returns an image of the rectangle r whose pixels have the
+// color model of a ScanlineWriter, and whether it is written as opaque.
+func newModelImage(model color.Model, r image.Rectangle) (m image.Image, opaque bool, err error) {
+	if p, ok := model.(color.Palette); ok {
+		return image.NewPaletted(r, p), false, nil
+	}
+	switch model {
+	case color.GrayModel:
+		return image.NewGray(r), false, nil
+	case color.Gray16Model:
+		return image.NewGray16(r), false, nil
+	case color.RGBAModel:
+		return image.NewRGBA(r), true, nil
+	case color.RGBA64Model:
+		return image.NewRGBA64(r), true, nil
+	case color.NRGBAModel:
+		return image.NewNRGBA(r), false, nil
+	case color.NRGBA64Model:
+		return image.NewNRGBA64(r), false, nil
+	}
+	err = fmt.Errorf("tiff: NewScanlineWriter, unsupported color model")
+	return
+}
+
+// drawRows copies the rectangle r of src into dst, from row y and column 0.
+func drawRows(dst image.Image, y int, src image.Image, r image.Rectangle) {
+	dstPix, dstStride, size, _ := pixLayout(dst)
+	srcPix, srcStride, _, ok := pixLayout(src)
+	_, paletted := dst.(*image.Paletted)
+	if !ok || paletted || reflect.TypeOf(dst) != reflect.TypeOf(src) {
+		// Colors are converted, and mapped to the palette.
+		dr := image.Rect(0, y, r.Dx(), y+r.Dy())
+		draw.Draw(dst.(draw.Image), dr, src, r.Min, draw.Src)
+		return
+	}
+	db, sb := dst.Bounds(), src.Bounds()
+	n := r.Dx() * size
+	for k := 0; k < r.Dy(); k++ {
+		d := (y+k-db.Min.Y)*dstStride - db.Min.X*size
+		s := (r.Min.Y+k-sb.Min.Y)*srcStride + (r.Min.X-sb.Min.X)*size
+		copy(dstPix[d:d+n], srcPix[s:s+n])
+	}
+}
@@ -52,15 +52,20 @@ func NewSeekReader(r io.Reader, maxBufferSize int) io.ReadSeekCloser {
 		return &seekioReader{rs: rs}
 	}
 
-	// Otherwise, read all data into memory
-	data, err := io.ReadAll(r)
+	// Otherwise, read all data into memory, but no more than needed to
+	// know that maxBufferSize is exceeded.
+	lr := r
+	if maxBufferSize > 0 {
+		lr = io.LimitReader(r, int64(maxBufferSize)+1)
+	}
+	data, err := io.ReadAll(lr)
 	if err != nil {
 		return &seekioReader{err: err}
 	}
 
 	// If maxBufferSize is specified and exceeded, return an error
 	if maxBufferSize > 0 && len(data) > maxBufferSize {
-		return &seekioReader{err: fmt.Errorf("seekio: buffer size %d exceeds maximum %d", len(data), maxBufferSize)}
+		return &seekioReader{err: fmt.Errorf("seekio: data exceeds the maximum buffer size %d", maxBufferSize)}
 	}
 
 	return &seekioReader{r: r, buf: data}
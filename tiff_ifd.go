@@ -9,6 +9,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 	"reflect"
 )
 
@@ -17,6 +18,13 @@ type IFD struct {
 	EntryMap map[TagType]*IFDEntry
 	ThisIFD  int64
 	NextIFD  int64
+
+	// Exif and GPS are the private IFDs that the ExifIFD and GPSIFD tags
+	// point to, or nil. They are read by OpenReader.
+	Exif *ExifIFD
+	GPS  *GPSIFD
+
+	opt *DecodeOptions // The limits of the Reader, or nil.
 }
 
 func NewIFD(hdr *Header, width, height, depth, channels int, kind reflect.Kind) (ifd *IFD) {
@@ -24,6 +32,11 @@ func NewIFD(hdr *Header, width, height, depth, channels int, kind reflect.Kind)
 }
 
 func ReadIFD(r io.Reader, h *Header, offset int64) (ifd *IFD, err error) {
+	return readIFDLimited(r, h, offset, nil)
+}
+
+// readIFDLimited is like ReadIFD, within the limits of opt, which may be nil.
+func readIFDLimited(r io.Reader, h *Header, offset int64, opt *DecodeOptions) (ifd *IFD, err error) {
 	var rs io.ReadSeeker
 	if rs, _ = r.(io.ReadSeeker); rs == nil {
 		seekioReader := openSeekioReader(r, 0)
@@ -37,15 +50,15 @@ func ReadIFD(r io.Reader, h *Header, offset int64) (ifd *IFD, err error) {
 	}
 
 	if h.TiffType == TiffType_ClassicTIFF {
-		ifd, err = readIFD(rs, h, offset)
+		ifd, err = readIFD(rs, h, offset, opt)
 		return
 	} else {
-		ifd, err = readIFD8(rs, h, offset)
+		ifd, err = readIFD8(rs, h, offset, opt)
 		return
 	}
 }
 
-func readIFD(r io.ReadSeeker, h *Header, offset int64) (p *IFD, err error) {
+func readIFD(r io.ReadSeeker, h *Header, offset int64, opt *DecodeOptions) (p *IFD, err error) {
 	if offset == 0 {
 		return
 	}
@@ -57,6 +70,7 @@ func readIFD(r io.ReadSeeker, h *Header, offset int64) (p *IFD, err error) {
 		Header:   h,
 		EntryMap: make(map[TagType]*IFDEntry),
 		ThisIFD:  offset,
+		opt:      opt,
 	}
 
 	// read IFDEntry
@@ -79,14 +93,14 @@ func readIFD(r io.ReadSeeker, h *Header, offset int64) (p *IFD, err error) {
 
 	// read IFDEntry Data
 	for _, entry := range p.EntryMap {
-		if entry.Data, err = readIFDEntryData(r, entry); err != nil {
+		if entry.Data, err = readIFDEntryData(r, entry, opt); err != nil {
 			return
 		}
 	}
 	return
 }
 
-func readIFD8(r io.ReadSeeker, h *Header, offset int64) (p *IFD, err error) {
+func readIFD8(r io.ReadSeeker, h *Header, offset int64, opt *DecodeOptions) (p *IFD, err error) {
 	if offset == 0 {
 		return
 	}
@@ -98,6 +112,7 @@ func readIFD8(r io.ReadSeeker, h *Header, offset int64) (p *IFD, err error) {
 		Header:   h,
 		EntryMap: make(map[TagType]*IFDEntry),
 		ThisIFD:  offset,
+		opt:      opt,
 	}
 
 	// read IFDEntry
@@ -120,7 +135,7 @@ func readIFD8(r io.ReadSeeker, h *Header, offset int64) (p *IFD, err error) {
 
 	// read IFDEntry8 Data
 	for _, entry := range p.EntryMap {
-		if entry.Data, err = readIFDEntry8Data(r, entry); err != nil {
+		if entry.Data, err = readIFDEntry8Data(r, entry, opt); err != nil {
 			return
 		}
 	}
@@ -186,8 +201,11 @@ func readIFDEntry8(r io.ReadSeeker, h *Header) (entry *IFDEntry, err error) {
 	return
 }
 
-func readIFDEntryData(r io.ReadSeeker, entry *IFDEntry) (data []byte, err error) {
-	valSize := entry.DataType.ByteSize() * entry.Count
+func readIFDEntryData(r io.ReadSeeker, entry *IFDEntry, opt *DecodeOptions) (data []byte, err error) {
+	valSize, err := entryDataSize(entry, opt)
+	if err != nil {
+		return
+	}
 	if valSize <= 4 {
 		var buf bytes.Buffer
 		var offset = uint32(entry.Offset)
@@ -200,6 +218,9 @@ func readIFDEntryData(r io.ReadSeeker, entry *IFDEntry) (data []byte, err error)
 		err = fmt.Errorf("tiff: readIFDEntryData, bad offset %v", entry.Offset)
 		return
 	}
+	if err = checkEntryData(r, entry.Offset, valSize, opt); err != nil {
+		return
+	}
 	if _, err = r.Seek(entry.Offset, 0); err != nil {
 		return
 	}
@@ -210,8 +231,11 @@ func readIFDEntryData(r io.ReadSeeker, entry *IFDEntry) (data []byte, err error)
 	return
 }
 
-func readIFDEntry8Data(r io.ReadSeeker, entry *IFDEntry) (data []byte, err error) {
-	valSize := entry.DataType.ByteSize() * entry.Count
+func readIFDEntry8Data(r io.ReadSeeker, entry *IFDEntry, opt *DecodeOptions) (data []byte, err error) {
+	valSize, err := entryDataSize(entry, opt)
+	if err != nil {
+		return
+	}
 	if valSize <= 8 {
 		var buf bytes.Buffer
 		var offset = uint64(entry.Offset)
@@ -224,6 +248,9 @@ func readIFDEntry8Data(r io.ReadSeeker, entry *IFDEntry) (data []byte, err error
 		err = fmt.Errorf("tiff: readIFDEntryData, bad offset %v", entry.Offset)
 		return
 	}
+	if err = checkEntryData(r, entry.Offset, valSize, opt); err != nil {
+		return
+	}
 	if _, err = r.Seek(entry.Offset, 0); err != nil {
 		return
 	}
@@ -233,3 +260,29 @@ func readIFDEntry8Data(r io.ReadSeeker, entry *IFDEntry) (data []byte, err error
 	}
 	return
 }
+
+// entryDataSize returns the size of the data of an entry, which is checked
+// against the limits of opt.
+func entryDataSize(entry *IFDEntry, opt *DecodeOptions) (n int, err error) {
+	size := entry.DataType.ByteSize()
+	if entry.Count < 0 || size > 0 && entry.Count > math.MaxInt32/size {
+		err = fmt.Errorf("tiff: readIFDEntryData, bad count %d", entry.Count)
+		return
+	}
+	n = size * entry.Count
+	err = opt.checkEntry(int64(n))
+	return
+}
+
+// checkEntryData checks that the n bytes of data at offset lie within the
+// file, so that entries do not allocate more than the file holds.
+func checkEntryData(r io.Seeker, offset int64, n int, opt *DecodeOptions) error {
+	end, err := opt.size(r)
+	if err != nil {
+		return err
+	}
+	if offset > end || int64(n) > end-offset {
+		return fmt.Errorf("tiff: readIFDEntryData, %d bytes at offset %d past the end of the file", n, offset)
+	}
+	return nil
+}
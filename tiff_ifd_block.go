@@ -5,6 +5,7 @@
 package tiff
 
 import (
+	"bytes"
 	"fmt"
 	"image"
 	"image/color"
@@ -131,17 +132,23 @@ func (p *IFD) DecodeBlock(r io.ReadSeeker, col, row int, dst image.Image) (err e
 		return
 	}
 
-	bounds := p.BlockBounds(col, row)
-	offset := p.BlockOffset(col, row)
-	count := p.BlockCount(col, row)
-
-	if _, err = r.Seek(offset, 0); err != nil {
+	data, err := p.readBlock(r, col, row)
+	if err != nil {
 		return
 	}
-	limitReader := io.LimitReader(r, count)
+	return p.decodeBlockData(data, col, row, dst)
+}
 
-	var data []byte
-	if data, err = p.Compression().Decode(limitReader, bounds.Dx(), bounds.Dy()); err != nil {
+// decodeBlockData decodes the compressed data of a block into dst.
+func (p *IFD) decodeBlockData(data []byte, col, row int, dst image.Image) (err error) {
+	bounds := p.BlockBounds(col, row)
+	// Blocks whose tags announce too much data fail before decompression.
+	max := p.opt.maxBlockBytes()
+	rowSize := (int64(bounds.Dx())*int64(p.Depth()*p.Channels()) + 7) / 8
+	if err = checkBlock(rowSize*int64(bounds.Dy()), max); err != nil {
+		return
+	}
+	if data, err = p.Compression().decode(bytes.NewReader(data), bounds.Dx(), bounds.Dy(), max); err != nil {
 		return
 	}
 
@@ -156,6 +163,33 @@ func (p *IFD) DecodeBlock(r io.ReadSeeker, col, row int, dst image.Image) (err e
 	return
 }
 
+// readBlock returns the compressed data of a block.
+func (p *IFD) readBlock(r io.ReadSeeker, col, row int) (data []byte, err error) {
+	offset := p.BlockOffset(col, row)
+	count := p.BlockCount(col, row)
+
+	// The block is read with a single call, so that readers which fetch
+	// their data remotely can do so with a single request. Blocks that
+	// extend past the end of the file are cut short.
+	size, err := p.opt.size(r)
+	if err != nil {
+		return
+	}
+	if offset < 0 || count < 0 || offset > size {
+		err = fmt.Errorf("tiff: IFD.DecodeBlock, block %d/%d out of range", col, row)
+		return
+	}
+	if count > size-offset {
+		count = size - offset
+	}
+	if _, err = r.Seek(offset, 0); err != nil {
+		return
+	}
+	data = make([]byte, count)
+	_, err = io.ReadFull(r, data)
+	return
+}
+
 func (p *IFD) decodePredictor(data []byte, r image.Rectangle) (out []byte, err error) {
 	bpp := p.Depth()
 	spp := p.Channels()
@@ -225,9 +259,9 @@ func (p *IFD) decodeBlock(buf []byte, dst image.Image, r image.Rectangle) (err e
 		}
 
 		if p.Depth() == 16 {
-			var off int
 			img := dst.(*image.Gray16)
 			for y := ymin; y < rMaxY; y++ {
+				off := (y - ymin) * (xmax - xmin) * 2
 				for x := xmin; x < rMaxX; x++ {
 					if off+2 > len(buf) {
 						err = fmt.Errorf("tiff: IFD.decodeBlock, not enough pixel data")
@@ -243,10 +277,15 @@ func (p *IFD) decodeBlock(buf []byte, dst image.Image, r image.Rectangle) (err e
 			}
 		} else {
 			bpp := uint(p.Depth())
-			bitReader := newBitsReader(buf)
+			rowSize := ((xmax-xmin)*int(bpp) + 7) / 8
 			img := dst.(*image.Gray)
 			max := uint32((1 << uint(p.Depth())) - 1)
 			for y := ymin; y < rMaxY; y++ {
+				if (y-ymin)*rowSize > len(buf) {
+					err = fmt.Errorf("tiff: IFD.decodeBlock, not enough pixel data")
+					return
+				}
+				bitReader := newBitsReader(buf[(y-ymin)*rowSize:])
 				for x := xmin; x < rMaxX; x++ {
 					v, ok := bitReader.ReadBits(bpp)
 					if !ok {
@@ -259,14 +298,18 @@ func (p *IFD) decodeBlock(buf []byte, dst image.Image, r image.Rectangle) (err e
 					}
 					img.SetGray(x, y, color.Gray{uint8(v)})
 				}
-				bitReader.flushBits()
 			}
 		}
 	case ImageType_Paletted:
 		bpp := uint(p.Depth())
-		bitReader := newBitsReader(buf)
+		rowSize := ((xmax-xmin)*int(bpp) + 7) / 8
 		img := dst.(*image.Paletted)
 		for y := ymin; y < rMaxY; y++ {
+			if (y-ymin)*rowSize > len(buf) {
+				err = fmt.Errorf("tiff: IFD.decodeBlock, not enough pixel data")
+				return
+			}
+			bitReader := newBitsReader(buf[(y-ymin)*rowSize:])
 			for x := xmin; x < rMaxX; x++ {
 				v, ok := bitReader.ReadBits(bpp)
 				if !ok {
@@ -275,13 +318,12 @@ func (p *IFD) decodeBlock(buf []byte, dst image.Image, r image.Rectangle) (err e
 				}
 				img.SetColorIndex(x, y, uint8(v))
 			}
-			bitReader.flushBits()
 		}
 	case ImageType_RGB:
 		if p.Depth() == 16 {
-			var off int
 			img := dst.(*image.RGBA64)
 			for y := ymin; y < rMaxY; y++ {
+				off := (y - ymin) * (xmax - xmin) * 6
 				for x := xmin; x < rMaxX; x++ {
 					if off+6 > len(buf) {
 						err = fmt.Errorf("tiff: IFD.decodeBlock, not enough pixel data")
@@ -315,9 +357,9 @@ func (p *IFD) decodeBlock(buf []byte, dst image.Image, r image.Rectangle) (err e
 		}
 	case ImageType_NRGBA:
 		if p.Depth() == 16 {
-			var off int
 			img := dst.(*image.NRGBA64)
 			for y := ymin; y < rMaxY; y++ {
+				off := (y - ymin) * (xmax - xmin) * 8
 				for x := xmin; x < rMaxX; x++ {
 					if off+8 > len(buf) {
 						err = fmt.Errorf("tiff: IFD.decodeBlock, not enough pixel data")
@@ -346,9 +388,9 @@ func (p *IFD) decodeBlock(buf []byte, dst image.Image, r image.Rectangle) (err e
 		}
 	case ImageType_RGBA:
 		if p.Depth() == 16 {
-			var off int
 			img := dst.(*image.RGBA64)
 			for y := ymin; y < rMaxY; y++ {
+				off := (y - ymin) * (xmax - xmin) * 8
 				for x := xmin; x < rMaxX; x++ {
 					if off+8 > len(buf) {
 						err = fmt.Errorf("tiff: IFD.decodeBlock, not enough pixel data")
@@ -90,13 +90,13 @@ func (p *IFDEntry) GetInts() []int64 {
 	case DataType_Byte:
 		dst := make([]int64, p.Count)
 		for i := 0; i < p.Count; i++ {
-			dst[i] = int64(int8(p.Data[i]))
+			dst[i] = int64(uint8(p.Data[i]))
 		}
 		return dst
 	case DataType_SByte:
 		dst := make([]int64, p.Count)
 		for i := 0; i < p.Count; i++ {
-			dst[i] = int64(uint8(p.Data[i]))
+			dst[i] = int64(int8(p.Data[i]))
 		}
 		return dst
 	case DataType_Short:
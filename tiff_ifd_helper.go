@@ -288,19 +288,19 @@ func (p *IFD) Bytes() []byte {
 
 	var buf bytes.Buffer
 	if p.Header.TiffType == TiffType_ClassicTIFF {
-		binary.Write(&buf, p.Header.ByteOrder, uint32(len(tagList)))
+		binary.Write(&buf, p.Header.ByteOrder, uint16(len(tagList)))
 		for i := 0; i < len(tagList); i++ {
 			entryBytes, _ := tagList[i].Bytes()
 			buf.Write(entryBytes)
 		}
 		binary.Write(&buf, p.Header.ByteOrder, uint32(p.NextIFD))
 	} else {
-		binary.Write(&buf, p.Header.ByteOrder, uint32(len(tagList)))
+		binary.Write(&buf, p.Header.ByteOrder, uint64(len(tagList)))
 		for i := 0; i < len(tagList); i++ {
 			entryBytes, _ := tagList[i].Bytes()
 			buf.Write(entryBytes)
 		}
-		binary.Write(&buf, p.Header.ByteOrder, uint32(p.NextIFD))
+		binary.Write(&buf, p.Header.ByteOrder, uint64(p.NextIFD))
 	}
 	return buf.Bytes()
 }
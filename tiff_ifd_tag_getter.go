@@ -286,8 +286,8 @@ func (p *tifTagGetter) GetXResolution() (value [2]int64, ok bool) {
 	if entry, ok = p.EntryMap[TagType_XResolution]; !ok {
 		return
 	}
-	if v := entry.GetInts(); len(v) == 2 {
-		value = [2]int64{v[0], v[1]}
+	if v := entry.GetRationals(); len(v) == 1 {
+		value = v[0]
 	} else {
 		ok = false
 	}
@@ -299,8 +299,8 @@ func (p *tifTagGetter) GetYResolution() (value [2]int64, ok bool) {
 	if entry, ok = p.EntryMap[TagType_YResolution]; !ok {
 		return
 	}
-	if v := entry.GetInts(); len(v) == 2 {
-		value = [2]int64{v[0], v[1]}
+	if v := entry.GetRationals(); len(v) == 1 {
+		value = v[0]
 	} else {
 		ok = false
 	}
@@ -336,8 +336,8 @@ func (p *tifTagGetter) GetXPosition() (value [2]int64, ok bool) {
 	if entry, ok = p.EntryMap[TagType_XPosition]; !ok {
 		return
 	}
-	if v := entry.GetInts(); len(v) == 2 {
-		value = [2]int64{v[0], v[1]}
+	if v := entry.GetRationals(); len(v) == 1 {
+		value = v[0]
 	} else {
 		ok = false
 	}
@@ -349,8 +349,8 @@ func (p *tifTagGetter) GetYPosition() (value [2]int64, ok bool) {
 	if entry, ok = p.EntryMap[TagType_YPosition]; !ok {
 		return
 	}
-	if v := entry.GetInts(); len(v) == 2 {
-		value = [2]int64{v[0], v[1]}
+	if v := entry.GetRationals(); len(v) == 1 {
+		value = v[0]
 	} else {
 		ok = false
 	}
@@ -868,6 +868,33 @@ func (p *tifTagGetter) GetReferenceBlackWhite() (value []int64, ok bool) {
 	return
 }
 
+func (p *tifTagGetter) GetXMP() (value []byte, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_XMP]; !ok {
+		return
+	}
+	value = entry.Data
+	return
+}
+
+func (p *tifTagGetter) GetCFARepeatPatternDim() (value []int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_CFARepeatPatternDim]; !ok {
+		return
+	}
+	value = entry.GetInts()
+	return
+}
+
+func (p *tifTagGetter) GetCFAPattern() (value []int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_CFAPattern]; !ok {
+		return
+	}
+	value = entry.GetInts()
+	return
+}
+
 func (p *tifTagGetter) GetCopyright() (value string, ok bool) {
 	var entry *IFDEntry
 	if entry, ok = p.EntryMap[TagType_Copyright]; !ok {
@@ -886,6 +913,15 @@ func (p *tifTagGetter) GetModelPixelScaleTag() (value []float64, ok bool) {
 	return
 }
 
+func (p *tifTagGetter) GetIPTC() (value []byte, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_IPTC]; !ok {
+		return
+	}
+	value = entry.Data
+	return
+}
+
 func (p *tifTagGetter) GetIrasBTransformationMatrix() (value []float64, ok bool) {
 	var entry *IFDEntry
 	if entry, ok = p.EntryMap[TagType_IrasBTransformationMatrix]; !ok {
@@ -913,6 +949,15 @@ func (p *tifTagGetter) GetModelTransformationTag() (value []float64, ok bool) {
 	return
 }
 
+func (p *tifTagGetter) GetPhotoshop() (value []byte, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_Photoshop]; !ok {
+		return
+	}
+	value = entry.Data
+	return
+}
+
 func (p *tifTagGetter) GetExifIFD() (value []int64, ok bool) {
 	var entry *IFDEntry
 	if entry, ok = p.EntryMap[TagType_ExifIFD]; !ok {
@@ -922,6 +967,15 @@ func (p *tifTagGetter) GetExifIFD() (value []int64, ok bool) {
 	return
 }
 
+func (p *tifTagGetter) GetICCProfile() (value []byte, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_ICCProfile]; !ok {
+		return
+	}
+	value = entry.Data
+	return
+}
+
 func (p *tifTagGetter) GetGeoKeyDirectoryTag() (value []int64, ok bool) {
 	var entry *IFDEntry
 	if entry, ok = p.EntryMap[TagType_GeoKeyDirectoryTag]; !ok {
@@ -967,6 +1021,425 @@ func (p *tifTagGetter) GetInteroperabilityIFD() (value []int64, ok bool) {
 	return
 }
 
+func (p *tifTagGetter) GetGDAL_METADATA() (value string, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_GDAL_METADATA]; !ok {
+		return
+	}
+	value = entry.GetString()
+	return
+}
+
+func (p *tifTagGetter) GetGDAL_NODATA() (value string, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_GDAL_NODATA]; !ok {
+		return
+	}
+	value = entry.GetString()
+	return
+}
+
+func (p *tifTagGetter) GetDNGVersion() (value []int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_DNGVersion]; !ok {
+		return
+	}
+	value = entry.GetInts()
+	return
+}
+
+func (p *tifTagGetter) GetDNGBackwardVersion() (value []int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_DNGBackwardVersion]; !ok {
+		return
+	}
+	value = entry.GetInts()
+	return
+}
+
+func (p *tifTagGetter) GetUniqueCameraModel() (value string, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_UniqueCameraModel]; !ok {
+		return
+	}
+	value = entry.GetString()
+	return
+}
+
+func (p *tifTagGetter) GetCFAPlaneColor() (value []int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_CFAPlaneColor]; !ok {
+		return
+	}
+	value = entry.GetInts()
+	return
+}
+
+func (p *tifTagGetter) GetCFALayout() (value int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_CFALayout]; !ok {
+		value = 1
+		ok = true
+		return
+	}
+	if v := entry.GetInts(); len(v) == 1 {
+		value = v[0]
+	} else {
+		ok = false
+	}
+	return
+}
+
+func (p *tifTagGetter) GetLinearizationTable() (value []int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_LinearizationTable]; !ok {
+		return
+	}
+	value = entry.GetInts()
+	return
+}
+
+func (p *tifTagGetter) GetBlackLevelRepeatDim() (value []int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_BlackLevelRepeatDim]; !ok {
+		return
+	}
+	value = entry.GetInts()
+	return
+}
+
+func (p *tifTagGetter) GetBlackLevel() (value []float64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_BlackLevel]; !ok {
+		return
+	}
+	value = entry.GetFloats()
+	return
+}
+
+func (p *tifTagGetter) GetBlackLevelDeltaH() (value [][2]int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_BlackLevelDeltaH]; !ok {
+		return
+	}
+	value = entry.GetRationals()
+	return
+}
+
+func (p *tifTagGetter) GetBlackLevelDeltaV() (value [][2]int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_BlackLevelDeltaV]; !ok {
+		return
+	}
+	value = entry.GetRationals()
+	return
+}
+
+func (p *tifTagGetter) GetWhiteLevel() (value []int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_WhiteLevel]; !ok {
+		return
+	}
+	value = entry.GetInts()
+	return
+}
+
+func (p *tifTagGetter) GetDefaultScale() (value [][2]int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_DefaultScale]; !ok {
+		return
+	}
+	value = entry.GetRationals()
+	return
+}
+
+func (p *tifTagGetter) GetDefaultCropOrigin() (value []float64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_DefaultCropOrigin]; !ok {
+		return
+	}
+	value = entry.GetFloats()
+	return
+}
+
+func (p *tifTagGetter) GetDefaultCropSize() (value []float64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_DefaultCropSize]; !ok {
+		return
+	}
+	value = entry.GetFloats()
+	return
+}
+
+func (p *tifTagGetter) GetColorMatrix1() (value [][2]int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_ColorMatrix1]; !ok {
+		return
+	}
+	value = entry.GetRationals()
+	return
+}
+
+func (p *tifTagGetter) GetColorMatrix2() (value [][2]int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_ColorMatrix2]; !ok {
+		return
+	}
+	value = entry.GetRationals()
+	return
+}
+
+func (p *tifTagGetter) GetCameraCalibration1() (value [][2]int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_CameraCalibration1]; !ok {
+		return
+	}
+	value = entry.GetRationals()
+	return
+}
+
+func (p *tifTagGetter) GetCameraCalibration2() (value [][2]int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_CameraCalibration2]; !ok {
+		return
+	}
+	value = entry.GetRationals()
+	return
+}
+
+func (p *tifTagGetter) GetReductionMatrix1() (value [][2]int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_ReductionMatrix1]; !ok {
+		return
+	}
+	value = entry.GetRationals()
+	return
+}
+
+func (p *tifTagGetter) GetReductionMatrix2() (value [][2]int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_ReductionMatrix2]; !ok {
+		return
+	}
+	value = entry.GetRationals()
+	return
+}
+
+func (p *tifTagGetter) GetAnalogBalance() (value [][2]int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_AnalogBalance]; !ok {
+		return
+	}
+	value = entry.GetRationals()
+	return
+}
+
+func (p *tifTagGetter) GetAsShotNeutral() (value []float64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_AsShotNeutral]; !ok {
+		return
+	}
+	value = entry.GetFloats()
+	return
+}
+
+func (p *tifTagGetter) GetAsShotWhiteXY() (value [][2]int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_AsShotWhiteXY]; !ok {
+		return
+	}
+	value = entry.GetRationals()
+	return
+}
+
+func (p *tifTagGetter) GetBaselineExposure() (value [2]int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_BaselineExposure]; !ok {
+		return
+	}
+	if v := entry.GetRationals(); len(v) == 1 {
+		value = v[0]
+	} else {
+		ok = false
+	}
+	return
+}
+
+func (p *tifTagGetter) GetBaselineNoise() (value [2]int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_BaselineNoise]; !ok {
+		return
+	}
+	if v := entry.GetRationals(); len(v) == 1 {
+		value = v[0]
+	} else {
+		ok = false
+	}
+	return
+}
+
+func (p *tifTagGetter) GetBaselineSharpness() (value [2]int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_BaselineSharpness]; !ok {
+		return
+	}
+	if v := entry.GetRationals(); len(v) == 1 {
+		value = v[0]
+	} else {
+		ok = false
+	}
+	return
+}
+
+func (p *tifTagGetter) GetBayerGreenSplit() (value int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_BayerGreenSplit]; !ok {
+		return
+	}
+	if v := entry.GetInts(); len(v) == 1 {
+		value = v[0]
+	} else {
+		ok = false
+	}
+	return
+}
+
+func (p *tifTagGetter) GetLinearResponseLimit() (value [2]int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_LinearResponseLimit]; !ok {
+		return
+	}
+	if v := entry.GetRationals(); len(v) == 1 {
+		value = v[0]
+	} else {
+		ok = false
+	}
+	return
+}
+
+func (p *tifTagGetter) GetCameraSerialNumber() (value string, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_CameraSerialNumber]; !ok {
+		return
+	}
+	value = entry.GetString()
+	return
+}
+
+func (p *tifTagGetter) GetLensInfo() (value [][2]int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_LensInfo]; !ok {
+		return
+	}
+	value = entry.GetRationals()
+	return
+}
+
+func (p *tifTagGetter) GetChromaBlurRadius() (value [2]int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_ChromaBlurRadius]; !ok {
+		return
+	}
+	if v := entry.GetRationals(); len(v) == 1 {
+		value = v[0]
+	} else {
+		ok = false
+	}
+	return
+}
+
+func (p *tifTagGetter) GetAntiAliasStrength() (value [2]int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_AntiAliasStrength]; !ok {
+		return
+	}
+	if v := entry.GetRationals(); len(v) == 1 {
+		value = v[0]
+	} else {
+		ok = false
+	}
+	return
+}
+
+func (p *tifTagGetter) GetMakerNoteSafety() (value int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_MakerNoteSafety]; !ok {
+		return
+	}
+	if v := entry.GetInts(); len(v) == 1 {
+		value = v[0]
+	} else {
+		ok = false
+	}
+	return
+}
+
+func (p *tifTagGetter) GetCalibrationIlluminant1() (value int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_CalibrationIlluminant1]; !ok {
+		return
+	}
+	if v := entry.GetInts(); len(v) == 1 {
+		value = v[0]
+	} else {
+		ok = false
+	}
+	return
+}
+
+func (p *tifTagGetter) GetCalibrationIlluminant2() (value int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_CalibrationIlluminant2]; !ok {
+		return
+	}
+	if v := entry.GetInts(); len(v) == 1 {
+		value = v[0]
+	} else {
+		ok = false
+	}
+	return
+}
+
+func (p *tifTagGetter) GetBestQualityScale() (value [2]int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_BestQualityScale]; !ok {
+		return
+	}
+	if v := entry.GetRationals(); len(v) == 1 {
+		value = v[0]
+	} else {
+		ok = false
+	}
+	return
+}
+
+func (p *tifTagGetter) GetActiveArea() (value []int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_ActiveArea]; !ok {
+		return
+	}
+	value = entry.GetInts()
+	return
+}
+
+func (p *tifTagGetter) GetForwardMatrix1() (value [][2]int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_ForwardMatrix1]; !ok {
+		return
+	}
+	value = entry.GetRationals()
+	return
+}
+
+func (p *tifTagGetter) GetForwardMatrix2() (value [][2]int64, ok bool) {
+	var entry *IFDEntry
+	if entry, ok = p.EntryMap[TagType_ForwardMatrix2]; !ok {
+		return
+	}
+	value = entry.GetRationals()
+	return
+}
+
 func (p *tifTagGetter) GetUnknown(tag TagType) (value []byte, ok bool) {
 	var entry *IFDEntry
 	if entry, ok = p.EntryMap[tag]; !ok {
@@ -3,3 +3,24 @@
 // license that can be found in the LICENSE file.
 
 package tiff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIFDEntry_GetInts(t *testing.T) {
+	data := []byte{0x00, 0x7f, 0x80, 0xff}
+	for _, tt := range []struct {
+		dataType DataType
+		want     []int64
+	}{
+		{DataType_Byte, []int64{0, 127, 128, 255}},
+		{DataType_SByte, []int64{0, 127, -128, -1}},
+	} {
+		e := &IFDEntry{DataType: tt.dataType, Count: len(data), Data: data}
+		if got := e.GetInts(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v: GetInts = %v, want %v", tt.dataType, got, tt.want)
+		}
+	}
+}
@@ -120,6 +120,8 @@ const (
 	TagValue_PhotometricType_CMYK             TagValue_PhotometricType    = 5     //
 	TagValue_PhotometricType_YCbCr            TagValue_PhotometricType    = 6     //
 	TagValue_PhotometricType_CIELab           TagValue_PhotometricType    = 8     //
+	TagValue_PhotometricType_CFA              TagValue_PhotometricType    = 32803 // # Color filter array, used by DNG.
+	TagValue_PhotometricType_LinearRaw        TagValue_PhotometricType    = 34892 // # Used by DNG.
 	_                                                                     = 0     //
 	TagType_Threshholding                     TagType                     = 263   // SHORT, 1, # Default=1
 	TagType_CellWidth                         TagType                     = 264   // SHORT, 1,
@@ -221,9 +223,11 @@ const (
 	TagType_YCbCrPositioning                  TagType                     = 531   // SHORT, 1, # Default=1
 	TagType_ReferenceBlackWhite               TagType                     = 532   // LONG , *, # 2*SamplesPerPixel
 	TagType_StripRowCounts                    TagType                     = 559   // ingore # Defined in the Mixed Raster Content part of RFC 2301, used to replace RowsPerStrip for IFDs with variable-sized strips.
-	TagType_XMP                               TagType                     = 700   // ingore # XML packet containing XMP metadata
+	TagType_XMP                               TagType                     = 700   // BYTE/UNDEFINED # XML packet containing XMP metadata
 	TagType_ImageID                           TagType                     = 32781 // ingore # OPI-related.
 	TagType_ImageLayer                        TagType                     = 34732 // ingore # Defined in the Mixed Raster Content part of RFC 2301, used to denote the particular function of this Image in the mixed raster scheme.
+	TagType_CFARepeatPatternDim               TagType                     = 33421 // SHORT, 2, # Used in Raw IFD of DNG files.
+	TagType_CFAPattern                        TagType                     = 33422 // BYTE # Used in Raw IFD of DNG files.
 	TagType_Copyright                         TagType                     = 33432 // ASCII
 	TagType_WangAnnotation                    TagType                     = 32932 // ingore # Annotation data, as used in 'Imaging for Windows'.
 	TagType_MDFileTag                         TagType                     = 33445 // ingore # Specifies the pixel data format encoding in the Molecular Dynamics GEL file format.
@@ -235,15 +239,15 @@ const (
 	TagType_MDPrepTime                        TagType                     = 33451 // ingore # Time the sample was prepared, as used in the Molecular Dynamics GEL file format.
 	TagType_MDFileUnits                       TagType                     = 33452 // ingore # Units for data in this file, as used in the Molecular Dynamics GEL file format.
 	TagType_ModelPixelScaleTag                TagType                     = 33550 // DOUBLE # Used in interchangeable GeoTIFF files.
-	TagType_IPTC                              TagType                     = 33723 // ingore # IPTC (International Press Telecommunications Council) metadata.
+	TagType_IPTC                              TagType                     = 33723 // UNDEFINED/LONG # IPTC (International Press Telecommunications Council) metadata.
 	TagType_INGRPacketDataTag                 TagType                     = 33918 // ingore # Intergraph Application specific storage.
 	TagType_INGRFlagRegisters                 TagType                     = 33919 // ingore # Intergraph Application specific flags.
 	TagType_IrasBTransformationMatrix         TagType                     = 33920 // DOUBLE, 17 # Originally part of Intergraph's GeoTIFF tags, but likely understood by IrasB only.
 	TagType_ModelTiepointTag                  TagType                     = 33922 // DOUBLE # Originally part of Intergraph's GeoTIFF tags, but now used in interchangeable GeoTIFF files.
 	TagType_ModelTransformationTag            TagType                     = 34264 // DOUBLE, 16 # Used in interchangeable GeoTIFF files.
-	TagType_Photoshop                         TagType                     = 34377 // ingore # Collection of Photoshop 'Image Resource Blocks'.
+	TagType_Photoshop                         TagType                     = 34377 // BYTE/UNDEFINED # Collection of Photoshop 'Image Resource Blocks'.
 	TagType_ExifIFD                           TagType                     = 34665 // IFD    # A pointer to the Exif IFD.
-	TagType_ICCProfile                        TagType                     = 34675 // ingore # ICC profile data.
+	TagType_ICCProfile                        TagType                     = 34675 // UNDEFINED # ICC profile data.
 	TagType_GeoKeyDirectoryTag                TagType                     = 34735 // SHORT, *, # >= 4
 	TagType_GeoDoubleParamsTag                TagType                     = 34736 // DOUBLE
 	TagType_GeoAsciiParamsTag                 TagType                     = 34737 // ASCII
@@ -253,51 +257,54 @@ const (
 	TagType_HylaFAXFaxRecvTime                TagType                     = 34910 // ingore # Used by HylaFAX.
 	TagType_ImageSourceData                   TagType                     = 37724 // ingore # Used by Adobe Photoshop.
 	TagType_InteroperabilityIFD               TagType                     = 40965 // IFD    # A pointer to the Exif-related Interoperability IFD.
-	TagType_GDAL_METADATA                     TagType                     = 42112 // ingore # Used by the GDAL library, holds an XML list of name=value 'metadata' values about the image as a whole, and about specific samples.
-	TagType_GDAL_NODATA                       TagType                     = 42113 // ingore # Used by the GDAL library, contains an ASCII encoded nodata or background pixel value.
+	TagType_GDAL_METADATA                     TagType                     = 42112 // ASCII  # Used by the GDAL library, holds an XML list of name=value 'metadata' values about the image as a whole, and about specific samples.
+	TagType_GDAL_NODATA                       TagType                     = 42113 // ASCII  # Used by the GDAL library, contains an ASCII encoded nodata or background pixel value.
 	TagType_OceScanjobDescription             TagType                     = 50215 // ingore # Used in the Oce scanning process.
 	TagType_OceApplicationSelector            TagType                     = 50216 // ingore # Used in the Oce scanning process.
 	TagType_OceIdentificationNumber           TagType                     = 50217 // ingore # Used in the Oce scanning process.
 	TagType_OceImageLogicCharacteristics      TagType                     = 50218 // ingore # Used in the Oce scanning process.
-	TagType_DNGVersion                        TagType                     = 50706 // ingore # Used in IFD 0 of DNG files.
-	TagType_DNGBackwardVersion                TagType                     = 50707 // ingore # Used in IFD 0 of DNG files.
-	TagType_UniqueCameraModel                 TagType                     = 50708 // ingore # Used in IFD 0 of DNG files.
+	TagType_DNGVersion                        TagType                     = 50706 // BYTE, 4, # Used in IFD 0 of DNG files.
+	TagType_DNGBackwardVersion                TagType                     = 50707 // BYTE, 4, # Used in IFD 0 of DNG files.
+	TagType_UniqueCameraModel                 TagType                     = 50708 // ASCII # Used in IFD 0 of DNG files.
 	TagType_LocalizedCameraModel              TagType                     = 50709 // ingore # Used in IFD 0 of DNG files.
-	TagType_CFAPlaneColor                     TagType                     = 50710 // ingore # Used in Raw IFD of DNG files.
-	TagType_CFALayout                         TagType                     = 50711 // ingore # Used in Raw IFD of DNG files.
-	TagType_LinearizationTable                TagType                     = 50712 // ingore # Used in Raw IFD of DNG files.
-	TagType_BlackLevelRepeatDim               TagType                     = 50713 // ingore # Used in Raw IFD of DNG files.
-	TagType_BlackLevel                        TagType                     = 50714 // ingore # Used in Raw IFD of DNG files.
-	TagType_BlackLevelDeltaH                  TagType                     = 50715 // ingore # Used in Raw IFD of DNG files.
-	TagType_BlackLevelDeltaV                  TagType                     = 50716 // ingore # Used in Raw IFD of DNG files.
-	TagType_WhiteLevel                        TagType                     = 50717 // ingore # Used in Raw IFD of DNG files.
-	TagType_DefaultScale                      TagType                     = 50718 // ingore # Used in Raw IFD of DNG files.
-	TagType_DefaultCropOrigin                 TagType                     = 50719 // ingore # Used in Raw IFD of DNG files.
-	TagType_DefaultCropSize                   TagType                     = 50720 // ingore # Used in Raw IFD of DNG files.
-	TagType_ColorMatrix1                      TagType                     = 50721 // ingore # Used in IFD 0 of DNG files.
-	TagType_ColorMatrix2                      TagType                     = 50722 // ingore # Used in IFD 0 of DNG files.
-	TagType_CameraCalibration1                TagType                     = 50723 // ingore # Used in IFD 0 of DNG files.
-	TagType_CameraCalibration2                TagType                     = 50724 // ingore # Used in IFD 0 of DNG files.
-	TagType_ReductionMatrix1                  TagType                     = 50725 // ingore # Used in IFD 0 of DNG files.
-	TagType_ReductionMatrix2                  TagType                     = 50726 // ingore # Used in IFD 0 of DNG files.
-	TagType_AnalogBalance                     TagType                     = 50727 // ingore # Used in IFD 0 of DNG files.
-	TagType_AsShotNeutral                     TagType                     = 50728 // ingore # Used in IFD 0 of DNG files.
-	TagType_AsShotWhiteXY                     TagType                     = 50729 // ingore # Used in IFD 0 of DNG files.
-	TagType_BaselineExposure                  TagType                     = 50730 // ingore # Used in IFD 0 of DNG files.
-	TagType_BaselineNoise                     TagType                     = 50731 // ingore # Used in IFD 0 of DNG files.
-	TagType_BaselineSharpness                 TagType                     = 50732 // ingore # Used in IFD 0 of DNG files.
-	TagType_BayerGreenSplit                   TagType                     = 50733 // ingore # Used in Raw IFD of DNG files.
-	TagType_LinearResponseLimit               TagType                     = 50734 // ingore # Used in IFD 0 of DNG files.
-	TagType_CameraSerialNumber                TagType                     = 50735 // ingore # Used in IFD 0 of DNG files.
-	TagType_LensInfo                          TagType                     = 50736 // ingore # Used in IFD 0 of DNG files.
-	TagType_ChromaBlurRadius                  TagType                     = 50737 // ingore # Used in Raw IFD of DNG files.
-	TagType_AntiAliasStrength                 TagType                     = 50738 // ingore # Used in Raw IFD of DNG files.
+	TagType_CFAPlaneColor                     TagType                     = 50710 // BYTE # Used in Raw IFD of DNG files.
+	TagType_CFALayout                         TagType                     = 50711 // SHORT, 1, # Default=1. Used in Raw IFD of DNG files.
+	TagType_LinearizationTable                TagType                     = 50712 // SHORT # Used in Raw IFD of DNG files.
+	TagType_BlackLevelRepeatDim               TagType                     = 50713 // SHORT, 2, # Used in Raw IFD of DNG files.
+	TagType_BlackLevel                        TagType                     = 50714 // SHORT/LONG/RATIONAL # Used in Raw IFD of DNG files.
+	TagType_BlackLevelDeltaH                  TagType                     = 50715 // SRATIONAL # Used in Raw IFD of DNG files.
+	TagType_BlackLevelDeltaV                  TagType                     = 50716 // SRATIONAL # Used in Raw IFD of DNG files.
+	TagType_WhiteLevel                        TagType                     = 50717 // SHORT/LONG # Used in Raw IFD of DNG files.
+	TagType_DefaultScale                      TagType                     = 50718 // RATIONAL, 2, # Used in Raw IFD of DNG files.
+	TagType_DefaultCropOrigin                 TagType                     = 50719 // SHORT/LONG/RATIONAL, 2, # Used in Raw IFD of DNG files.
+	TagType_DefaultCropSize                   TagType                     = 50720 // SHORT/LONG/RATIONAL, 2, # Used in Raw IFD of DNG files.
+	TagType_ColorMatrix1                      TagType                     = 50721 // SRATIONAL # Used in IFD 0 of DNG files.
+	TagType_ColorMatrix2                      TagType                     = 50722 // SRATIONAL # Used in IFD 0 of DNG files.
+	TagType_CameraCalibration1                TagType                     = 50723 // SRATIONAL # Used in IFD 0 of DNG files.
+	TagType_CameraCalibration2                TagType                     = 50724 // SRATIONAL # Used in IFD 0 of DNG files.
+	TagType_ReductionMatrix1                  TagType                     = 50725 // SRATIONAL # Used in IFD 0 of DNG files.
+	TagType_ReductionMatrix2                  TagType                     = 50726 // SRATIONAL # Used in IFD 0 of DNG files.
+	TagType_AnalogBalance                     TagType                     = 50727 // RATIONAL # Used in IFD 0 of DNG files.
+	TagType_AsShotNeutral                     TagType                     = 50728 // SHORT/RATIONAL # Used in IFD 0 of DNG files.
+	TagType_AsShotWhiteXY                     TagType                     = 50729 // RATIONAL, 2, # Used in IFD 0 of DNG files.
+	TagType_BaselineExposure                  TagType                     = 50730 // SRATIONAL, 1, # Used in IFD 0 of DNG files.
+	TagType_BaselineNoise                     TagType                     = 50731 // RATIONAL, 1, # Used in IFD 0 of DNG files.
+	TagType_BaselineSharpness                 TagType                     = 50732 // RATIONAL, 1, # Used in IFD 0 of DNG files.
+	TagType_BayerGreenSplit                   TagType                     = 50733 // LONG, 1, # Used in Raw IFD of DNG files.
+	TagType_LinearResponseLimit               TagType                     = 50734 // RATIONAL, 1, # Used in IFD 0 of DNG files.
+	TagType_CameraSerialNumber                TagType                     = 50735 // ASCII # Used in IFD 0 of DNG files.
+	TagType_LensInfo                          TagType                     = 50736 // RATIONAL, 4, # Used in IFD 0 of DNG files.
+	TagType_ChromaBlurRadius                  TagType                     = 50737 // RATIONAL, 1, # Used in Raw IFD of DNG files.
+	TagType_AntiAliasStrength                 TagType                     = 50738 // RATIONAL, 1, # Used in Raw IFD of DNG files.
 	TagType_DNGPrivateData                    TagType                     = 50740 // ingore # Used in IFD 0 of DNG files.
-	TagType_MakerNoteSafety                   TagType                     = 50741 // ingore # Used in IFD 0 of DNG files.
-	TagType_CalibrationIlluminant1            TagType                     = 50778 // ingore # Used in IFD 0 of DNG files.
-	TagType_CalibrationIlluminant2            TagType                     = 50779 // ingore # Used in IFD 0 of DNG files.
-	TagType_BestQualityScale                  TagType                     = 50780 // ingore # Used in Raw IFD of DNG files.
+	TagType_MakerNoteSafety                   TagType                     = 50741 // SHORT, 1, # Used in IFD 0 of DNG files.
+	TagType_CalibrationIlluminant1            TagType                     = 50778 // SHORT, 1, # Used in IFD 0 of DNG files.
+	TagType_CalibrationIlluminant2            TagType                     = 50779 // SHORT, 1, # Used in IFD 0 of DNG files.
+	TagType_BestQualityScale                  TagType                     = 50780 // RATIONAL, 1, # Used in Raw IFD of DNG files.
 	TagType_AliasLayerMetadata                TagType                     = 50784 // ingore # Alias Sketchbook Pro layer usage description.
+	TagType_ActiveArea                        TagType                     = 50829 // SHORT/LONG, 4, # Used in Raw IFD of DNG files.
+	TagType_ForwardMatrix1                    TagType                     = 50964 // SRATIONAL # Used in IFD 0 of DNG files.
+	TagType_ForwardMatrix2                    TagType                     = 50965 // SRATIONAL # Used in IFD 0 of DNG files.
 	_                                                                     = 0     //
 )
 
@@ -305,99 +312,114 @@ const (
 type ExifIFD_TagType TagType
 
 const (
-	ExifIFD_TagType_ExposureTime             ExifIFD_TagType = 33434 // ingore # Exposure time, given in seconds.
-	ExifIFD_TagType_FNumber                  ExifIFD_TagType = 33437 // ingore # The F number.
-	ExifIFD_TagType_ExposureProgram          ExifIFD_TagType = 34850 // ingore # The class of the program used by the camera to set exposure when the picture is taken.
-	ExifIFD_TagType_SpectralSensitivity      ExifIFD_TagType = 34852 // ingore # Indicates the spectral sensitivity of each channel of the camera used.
-	ExifIFD_TagType_ISOSpeedRatings          ExifIFD_TagType = 34855 // ingore # Indicates the ISO Speed and ISO Latitude of the camera or input device as specified in ISO 12232.
-	ExifIFD_TagType_OECF                     ExifIFD_TagType = 34856 // ingore # Indicates the Opto-Electric Conversion Function (OECF) specified in ISO 14524.
-	ExifIFD_TagType_ExifVersion              ExifIFD_TagType = 36864 // ingore # The version of the supported Exif standard.
-	ExifIFD_TagType_DateTimeOriginal         ExifIFD_TagType = 36867 // ingore # The date and time when the original image data was generated.
-	ExifIFD_TagType_DateTimeDigitized        ExifIFD_TagType = 36868 // ingore # The date and time when the image was stored as digital data.
-	ExifIFD_TagType_ComponentsConfiguration  ExifIFD_TagType = 37121 // ingore # Specific to compressed data; specifies the channels and complements PhotometricInterpretation
-	ExifIFD_TagType_CompressedBitsPerPixel   ExifIFD_TagType = 37122 // ingore # Specific to compressed data; states the compressed bits per pixel.
-	ExifIFD_TagType_ShutterSpeedValue        ExifIFD_TagType = 37377 // ingore # Shutter speed.
-	ExifIFD_TagType_ApertureValue            ExifIFD_TagType = 37378 // ingore # The lens aperture.
-	ExifIFD_TagType_BrightnessValue          ExifIFD_TagType = 37379 // ingore # The value of brightness.
-	ExifIFD_TagType_ExposureBiasValue        ExifIFD_TagType = 37380 // ingore # The exposure bias.
-	ExifIFD_TagType_MaxApertureValue         ExifIFD_TagType = 37381 // ingore # The smallest F number of the lens.
-	ExifIFD_TagType_SubjectDistance          ExifIFD_TagType = 37382 // ingore # The distance to the subject, given in meters.
-	ExifIFD_TagType_MeteringMode             ExifIFD_TagType = 37383 // ingore # The metering mode.
-	ExifIFD_TagType_LightSource              ExifIFD_TagType = 37384 // ingore # The kind of light source.
-	ExifIFD_TagType_Flash                    ExifIFD_TagType = 37385 // ingore # Indicates the status of flash when the image was shot.
-	ExifIFD_TagType_FocalLength              ExifIFD_TagType = 37386 // ingore # The actual focal length of the lens, in mm.
-	ExifIFD_TagType_SubjectArea              ExifIFD_TagType = 37396 // ingore # Indicates the location and area of the main subject in the overall scene.
-	ExifIFD_TagType_MakerNote                ExifIFD_TagType = 37500 // ingore # Manufacturer specific information.
-	ExifIFD_TagType_UserComment              ExifIFD_TagType = 37510 // ingore # Keywords or comments on the image; complements ImageDescription.
-	ExifIFD_TagType_SubsecTime               ExifIFD_TagType = 37520 // ingore # A tag used to record fractions of seconds for the DateTime tag.
-	ExifIFD_TagType_SubsecTimeOriginal       ExifIFD_TagType = 37521 // ingore # A tag used to record fractions of seconds for the DateTimeOriginal tag.
-	ExifIFD_TagType_SubsecTimeDigitized      ExifIFD_TagType = 37522 // ingore # A tag used to record fractions of seconds for the DateTimeDigitized tag.
-	ExifIFD_TagType_FlashpixVersion          ExifIFD_TagType = 40960 // ingore # The Flashpix format version supported by a FPXR file.
-	ExifIFD_TagType_ColorSpace               ExifIFD_TagType = 40961 // ingore # The color space information tag is always recorded as the color space specifier.
-	ExifIFD_TagType_PixelXDimension          ExifIFD_TagType = 40962 // ingore # Specific to compressed data; the valid width of the meaningful image.
-	ExifIFD_TagType_PixelYDimension          ExifIFD_TagType = 40963 // ingore # Specific to compressed data; the valid height of the meaningful image.
-	ExifIFD_TagType_RelatedSoundFile         ExifIFD_TagType = 40964 // ingore # Used to record the name of an audio file related to the image data.
-	ExifIFD_TagType_FlashEnergy              ExifIFD_TagType = 41483 // ingore # Indicates the strobe energy at the time the image is captured, as measured in Beam Candle Power Seconds
-	ExifIFD_TagType_SpatialFrequencyResponse ExifIFD_TagType = 41484 // ingore # Records the camera or input device spatial frequency table and SFR values in the direction of image width, image height, and diagonal direction, as specified in ISO 12233.
-	ExifIFD_TagType_FocalPlaneXResolution    ExifIFD_TagType = 41486 // ingore # Indicates the number of pixels in the image width (X) direction per FocalPlaneResolutionUnit on the camera focal plane.
-	ExifIFD_TagType_FocalPlaneYResolution    ExifIFD_TagType = 41487 // ingore # Indicates the number of pixels in the image height (Y) direction per FocalPlaneResolutionUnit on the camera focal plane.
-	ExifIFD_TagType_FocalPlaneResolutionUnit ExifIFD_TagType = 41488 // ingore # Indicates the unit for measuring FocalPlaneXResolution and FocalPlaneYResolution.
-	ExifIFD_TagType_SubjectLocation          ExifIFD_TagType = 41492 // ingore # Indicates the location of the main subject in the scene.
-	ExifIFD_TagType_ExposureIndex            ExifIFD_TagType = 41493 // ingore # Indicates the exposure index selected on the camera or input device at the time the image is captured.
-	ExifIFD_TagType_SensingMethod            ExifIFD_TagType = 41495 // ingore # Indicates the image sensor type on the camera or input device.
-	ExifIFD_TagType_FileSource               ExifIFD_TagType = 41728 // ingore # Indicates the image source.
-	ExifIFD_TagType_SceneType                ExifIFD_TagType = 41729 // ingore # Indicates the type of scene.
-	ExifIFD_TagType_CFAPattern               ExifIFD_TagType = 41730 // ingore # Indicates the color filter array (CFA) geometric pattern of the image sensor when a one-chip color area sensor is used.
-	ExifIFD_TagType_CustomRendered           ExifIFD_TagType = 41985 // ingore # Indicates the use of special processing on image data, such as rendering geared to output.
-	ExifIFD_TagType_ExposureMode             ExifIFD_TagType = 41986 // ingore # Indicates the exposure mode set when the image was shot.
-	ExifIFD_TagType_WhiteBalance             ExifIFD_TagType = 41987 // ingore # Indicates the white balance mode set when the image was shot.
-	ExifIFD_TagType_DigitalZoomRatio         ExifIFD_TagType = 41988 // ingore # Indicates the digital zoom ratio when the image was shot.
-	ExifIFD_TagType_FocalLengthIn35mmFilm    ExifIFD_TagType = 41989 // ingore # Indicates the equivalent focal length assuming a 35mm film camera, in mm.
-	ExifIFD_TagType_SceneCaptureType         ExifIFD_TagType = 41990 // ingore # Indicates the type of scene that was shot.
-	ExifIFD_TagType_GainControl              ExifIFD_TagType = 41991 // ingore # Indicates the degree of overall image gain adjustment.
-	ExifIFD_TagType_Contrast                 ExifIFD_TagType = 41992 // ingore # Indicates the direction of contrast processing applied by the camera when the image was shot.
-	ExifIFD_TagType_Saturation               ExifIFD_TagType = 41993 // ingore # Indicates the direction of saturation processing applied by the camera when the image was shot.
-	ExifIFD_TagType_Sharpness                ExifIFD_TagType = 41994 // ingore # Indicates the direction of sharpness processing applied by the camera when the image was shot.
-	ExifIFD_TagType_DeviceSettingDescription ExifIFD_TagType = 41995 // ingore # This tag indicates information on the picture-taking conditions of a particular camera model.
-	ExifIFD_TagType_SubjectDistanceRange     ExifIFD_TagType = 41996 // ingore # Indicates the distance to the subject.
-	ExifIFD_TagType_ImageUniqueID            ExifIFD_TagType = 42016 // ingore # Indicates an identifier assigned uniquely to each image.
+	ExifIFD_TagType_ExposureTime              ExifIFD_TagType = 33434 // ingore # Exposure time, given in seconds.
+	ExifIFD_TagType_FNumber                   ExifIFD_TagType = 33437 // ingore # The F number.
+	ExifIFD_TagType_ExposureProgram           ExifIFD_TagType = 34850 // ingore # The class of the program used by the camera to set exposure when the picture is taken.
+	ExifIFD_TagType_SpectralSensitivity       ExifIFD_TagType = 34852 // ingore # Indicates the spectral sensitivity of each channel of the camera used.
+	ExifIFD_TagType_ISOSpeedRatings           ExifIFD_TagType = 34855 // ingore # Indicates the ISO Speed and ISO Latitude of the camera or input device as specified in ISO 12232.
+	ExifIFD_TagType_OECF                      ExifIFD_TagType = 34856 // ingore # Indicates the Opto-Electric Conversion Function (OECF) specified in ISO 14524.
+	ExifIFD_TagType_SensitivityType           ExifIFD_TagType = 34864 // ingore # Indicates which of the sensitivity parameters is recorded in ISOSpeedRatings.
+	ExifIFD_TagType_StandardOutputSensitivity ExifIFD_TagType = 34865 // ingore # The standard output sensitivity (SOS) value of ISO 12232.
+	ExifIFD_TagType_RecommendedExposureIndex  ExifIFD_TagType = 34866 // ingore # The recommended exposure index (REI) value of ISO 12232.
+	ExifIFD_TagType_ISOSpeed                  ExifIFD_TagType = 34867 // ingore # The ISO speed value of ISO 12232.
+	ExifIFD_TagType_ISOSpeedLatitudeyyy       ExifIFD_TagType = 34868 // ingore # The ISO speed latitude yyy value of ISO 12232.
+	ExifIFD_TagType_ISOSpeedLatitudezzz       ExifIFD_TagType = 34869 // ingore # The ISO speed latitude zzz value of ISO 12232.
+	ExifIFD_TagType_ExifVersion               ExifIFD_TagType = 36864 // ingore # The version of the supported Exif standard.
+	ExifIFD_TagType_DateTimeOriginal          ExifIFD_TagType = 36867 // ingore # The date and time when the original image data was generated.
+	ExifIFD_TagType_DateTimeDigitized         ExifIFD_TagType = 36868 // ingore # The date and time when the image was stored as digital data.
+	ExifIFD_TagType_ComponentsConfiguration   ExifIFD_TagType = 37121 // ingore # Specific to compressed data; specifies the channels and complements PhotometricInterpretation
+	ExifIFD_TagType_CompressedBitsPerPixel    ExifIFD_TagType = 37122 // ingore # Specific to compressed data; states the compressed bits per pixel.
+	ExifIFD_TagType_ShutterSpeedValue         ExifIFD_TagType = 37377 // ingore # Shutter speed.
+	ExifIFD_TagType_ApertureValue             ExifIFD_TagType = 37378 // ingore # The lens aperture.
+	ExifIFD_TagType_BrightnessValue           ExifIFD_TagType = 37379 // ingore # The value of brightness.
+	ExifIFD_TagType_ExposureBiasValue         ExifIFD_TagType = 37380 // ingore # The exposure bias.
+	ExifIFD_TagType_MaxApertureValue          ExifIFD_TagType = 37381 // ingore # The smallest F number of the lens.
+	ExifIFD_TagType_SubjectDistance           ExifIFD_TagType = 37382 // ingore # The distance to the subject, given in meters.
+	ExifIFD_TagType_MeteringMode              ExifIFD_TagType = 37383 // ingore # The metering mode.
+	ExifIFD_TagType_LightSource               ExifIFD_TagType = 37384 // ingore # The kind of light source.
+	ExifIFD_TagType_Flash                     ExifIFD_TagType = 37385 // ingore # Indicates the status of flash when the image was shot.
+	ExifIFD_TagType_FocalLength               ExifIFD_TagType = 37386 // ingore # The actual focal length of the lens, in mm.
+	ExifIFD_TagType_SubjectArea               ExifIFD_TagType = 37396 // ingore # Indicates the location and area of the main subject in the overall scene.
+	ExifIFD_TagType_MakerNote                 ExifIFD_TagType = 37500 // ingore # Manufacturer specific information.
+	ExifIFD_TagType_UserComment               ExifIFD_TagType = 37510 // ingore # Keywords or comments on the image; complements ImageDescription.
+	ExifIFD_TagType_SubsecTime                ExifIFD_TagType = 37520 // ingore # A tag used to record fractions of seconds for the DateTime tag.
+	ExifIFD_TagType_SubsecTimeOriginal        ExifIFD_TagType = 37521 // ingore # A tag used to record fractions of seconds for the DateTimeOriginal tag.
+	ExifIFD_TagType_SubsecTimeDigitized       ExifIFD_TagType = 37522 // ingore # A tag used to record fractions of seconds for the DateTimeDigitized tag.
+	ExifIFD_TagType_FlashpixVersion           ExifIFD_TagType = 40960 // ingore # The Flashpix format version supported by a FPXR file.
+	ExifIFD_TagType_ColorSpace                ExifIFD_TagType = 40961 // ingore # The color space information tag is always recorded as the color space specifier.
+	ExifIFD_TagType_PixelXDimension           ExifIFD_TagType = 40962 // ingore # Specific to compressed data; the valid width of the meaningful image.
+	ExifIFD_TagType_PixelYDimension           ExifIFD_TagType = 40963 // ingore # Specific to compressed data; the valid height of the meaningful image.
+	ExifIFD_TagType_RelatedSoundFile          ExifIFD_TagType = 40964 // ingore # Used to record the name of an audio file related to the image data.
+	ExifIFD_TagType_InteroperabilityIFD       ExifIFD_TagType = 40965 // ingore # A pointer to the Exif-related Interoperability IFD.
+	ExifIFD_TagType_FlashEnergy               ExifIFD_TagType = 41483 // ingore # Indicates the strobe energy at the time the image is captured, as measured in Beam Candle Power Seconds
+	ExifIFD_TagType_SpatialFrequencyResponse  ExifIFD_TagType = 41484 // ingore # Records the camera or input device spatial frequency table and SFR values in the direction of image width, image height, and diagonal direction, as specified in ISO 12233.
+	ExifIFD_TagType_FocalPlaneXResolution     ExifIFD_TagType = 41486 // ingore # Indicates the number of pixels in the image width (X) direction per FocalPlaneResolutionUnit on the camera focal plane.
+	ExifIFD_TagType_FocalPlaneYResolution     ExifIFD_TagType = 41487 // ingore # Indicates the number of pixels in the image height (Y) direction per FocalPlaneResolutionUnit on the camera focal plane.
+	ExifIFD_TagType_FocalPlaneResolutionUnit  ExifIFD_TagType = 41488 // ingore # Indicates the unit for measuring FocalPlaneXResolution and FocalPlaneYResolution.
+	ExifIFD_TagType_SubjectLocation           ExifIFD_TagType = 41492 // ingore # Indicates the location of the main subject in the scene.
+	ExifIFD_TagType_ExposureIndex             ExifIFD_TagType = 41493 // ingore # Indicates the exposure index selected on the camera or input device at the time the image is captured.
+	ExifIFD_TagType_SensingMethod             ExifIFD_TagType = 41495 // ingore # Indicates the image sensor type on the camera or input device.
+	ExifIFD_TagType_FileSource                ExifIFD_TagType = 41728 // ingore # Indicates the image source.
+	ExifIFD_TagType_SceneType                 ExifIFD_TagType = 41729 // ingore # Indicates the type of scene.
+	ExifIFD_TagType_CFAPattern                ExifIFD_TagType = 41730 // ingore # Indicates the color filter array (CFA) geometric pattern of the image sensor when a one-chip color area sensor is used.
+	ExifIFD_TagType_CustomRendered            ExifIFD_TagType = 41985 // ingore # Indicates the use of special processing on image data, such as rendering geared to output.
+	ExifIFD_TagType_ExposureMode              ExifIFD_TagType = 41986 // ingore # Indicates the exposure mode set when the image was shot.
+	ExifIFD_TagType_WhiteBalance              ExifIFD_TagType = 41987 // ingore # Indicates the white balance mode set when the image was shot.
+	ExifIFD_TagType_DigitalZoomRatio          ExifIFD_TagType = 41988 // ingore # Indicates the digital zoom ratio when the image was shot.
+	ExifIFD_TagType_FocalLengthIn35mmFilm     ExifIFD_TagType = 41989 // ingore # Indicates the equivalent focal length assuming a 35mm film camera, in mm.
+	ExifIFD_TagType_SceneCaptureType          ExifIFD_TagType = 41990 // ingore # Indicates the type of scene that was shot.
+	ExifIFD_TagType_GainControl               ExifIFD_TagType = 41991 // ingore # Indicates the degree of overall image gain adjustment.
+	ExifIFD_TagType_Contrast                  ExifIFD_TagType = 41992 // ingore # Indicates the direction of contrast processing applied by the camera when the image was shot.
+	ExifIFD_TagType_Saturation                ExifIFD_TagType = 41993 // ingore # Indicates the direction of saturation processing applied by the camera when the image was shot.
+	ExifIFD_TagType_Sharpness                 ExifIFD_TagType = 41994 // ingore # Indicates the direction of sharpness processing applied by the camera when the image was shot.
+	ExifIFD_TagType_DeviceSettingDescription  ExifIFD_TagType = 41995 // ingore # This tag indicates information on the picture-taking conditions of a particular camera model.
+	ExifIFD_TagType_SubjectDistanceRange      ExifIFD_TagType = 41996 // ingore # Indicates the distance to the subject.
+	ExifIFD_TagType_ImageUniqueID             ExifIFD_TagType = 42016 // ingore # Indicates an identifier assigned uniquely to each image.
+	ExifIFD_TagType_CameraOwnerName           ExifIFD_TagType = 42032 // ingore # The name of the camera owner.
+	ExifIFD_TagType_BodySerialNumber          ExifIFD_TagType = 42033 // ingore # The serial number of the body of the camera.
+	ExifIFD_TagType_LensSpecification         ExifIFD_TagType = 42034 // ingore # The minimum and maximum focal lengths in mm and F numbers of the lens.
+	ExifIFD_TagType_LensMake                  ExifIFD_TagType = 42035 // ingore # The lens manufacturer.
+	ExifIFD_TagType_LensModel                 ExifIFD_TagType = 42036 // ingore # The model name or model number of the lens.
+	ExifIFD_TagType_LensSerialNumber          ExifIFD_TagType = 42037 // ingore # The serial number of the interchangeable lens.
+	ExifIFD_TagType_Gamma                     ExifIFD_TagType = 42240 // ingore # Indicates the value of coefficient gamma.
 )
 
 // GPS Tags
 type GPSIFD_TagType TagType
 
 const (
-	GPSIFD_TagType_GPSVersionID        GPSIFD_TagType = 0  // ingore # Indicates the version of GPSInfoIFD.
-	GPSIFD_TagType_GPSLatitudeRef      GPSIFD_TagType = 1  // ingore # Indicates whether the latitude is north or south latitude.
-	GPSIFD_TagType_GPSLatitude         GPSIFD_TagType = 2  // ingore # Indicates the latitude.
-	GPSIFD_TagType_GPSLongitudeRef     GPSIFD_TagType = 3  // ingore # Indicates whether the longitude is east or west longitude.
-	GPSIFD_TagType_GPSLongitude        GPSIFD_TagType = 4  // ingore # Indicates the longitude.
-	GPSIFD_TagType_GPSAltitudeRef      GPSIFD_TagType = 5  // ingore # Indicates the altitude used as the reference altitude.
-	GPSIFD_TagType_GPSAltitude         GPSIFD_TagType = 6  // ingore # Indicates the altitude based on the reference in GPSAltitudeRef.
-	GPSIFD_TagType_GPSTimeStamp        GPSIFD_TagType = 7  // ingore # Indicates the time as UTC (Coordinated Universal Time).
-	GPSIFD_TagType_GPSSatellites       GPSIFD_TagType = 8  // ingore # Indicates the GPS satellites used for measurements.
-	GPSIFD_TagType_GPSStatus           GPSIFD_TagType = 9  // ingore # Indicates the status of the GPS receiver when the image is recorded.
-	GPSIFD_TagType_GPSMeasureMode      GPSIFD_TagType = 10 // ingore # Indicates the GPS measurement mode.
-	GPSIFD_TagType_GPSDOP              GPSIFD_TagType = 11 // ingore # Indicates the GPS DOP (data degree of precision).
-	GPSIFD_TagType_GPSSpeedRef         GPSIFD_TagType = 12 // ingore # Indicates the unit used to express the GPS receiver speed of movement.
-	GPSIFD_TagType_GPSSpeed            GPSIFD_TagType = 13 // ingore # Indicates the speed of GPS receiver movement.
-	GPSIFD_TagType_GPSTrackRef         GPSIFD_TagType = 14 // ingore # Indicates the reference for giving the direction of GPS receiver movement.
-	GPSIFD_TagType_GPSTrack            GPSIFD_TagType = 15 // ingore # Indicates the direction of GPS receiver movement.
-	GPSIFD_TagType_GPSImgDirectionRef  GPSIFD_TagType = 16 // ingore # Indicates the reference for giving the direction of the image when it is captured.
-	GPSIFD_TagType_GPSImgDirection     GPSIFD_TagType = 17 // ingore # Indicates the direction of the image when it was captured.
-	GPSIFD_TagType_GPSMapDatum         GPSIFD_TagType = 18 // ingore # Indicates the geodetic survey data used by the GPS receiver.
-	GPSIFD_TagType_GPSDestLatitudeRef  GPSIFD_TagType = 19 // ingore # Indicates whether the latitude of the destination point is north or south latitude.
-	GPSIFD_TagType_GPSDestLatitude     GPSIFD_TagType = 20 // ingore # Indicates the latitude of the destination point.
-	GPSIFD_TagType_GPSDestLongitudeRef GPSIFD_TagType = 21 // ingore # Indicates whether the longitude of the destination point is east or west longitude.
-	GPSIFD_TagType_GPSDestLongitude    GPSIFD_TagType = 22 // ingore # Indicates the longitude of the destination point.
-	GPSIFD_TagType_GPSDestBearingRef   GPSIFD_TagType = 23 // ingore # Indicates the reference used for giving the bearing to the destination point.
-	GPSIFD_TagType_GPSDestBearing      GPSIFD_TagType = 24 // ingore # Indicates the bearing to the destination point.
-	GPSIFD_TagType_GPSDestDistanceRef  GPSIFD_TagType = 25 // ingore # Indicates the unit used to express the distance to the destination point.
-	GPSIFD_TagType_GPSDestDistance     GPSIFD_TagType = 26 // ingore # Indicates the distance to the destination point.
-	GPSIFD_TagType_GPSProcessingMethod GPSIFD_TagType = 27 // ingore # A character string recording the name of the method used for location finding.
-	GPSIFD_TagType_GPSAreaInformation  GPSIFD_TagType = 28 // ingore # A character string recording the name of the GPS area.
-	GPSIFD_TagType_GPSDateStamp        GPSIFD_TagType = 29 // ingore # A character string recording date and time information relative to UTC (Coordinated Universal Time).
-	GPSIFD_TagType_GPSDifferential     GPSIFD_TagType = 30 // ingore # Indicates whether differential correction is applied to the GPS receiver.
+	GPSIFD_TagType_GPSVersionID         GPSIFD_TagType = 0  // ingore # Indicates the version of GPSInfoIFD.
+	GPSIFD_TagType_GPSLatitudeRef       GPSIFD_TagType = 1  // ingore # Indicates whether the latitude is north or south latitude.
+	GPSIFD_TagType_GPSLatitude          GPSIFD_TagType = 2  // ingore # Indicates the latitude.
+	GPSIFD_TagType_GPSLongitudeRef      GPSIFD_TagType = 3  // ingore # Indicates whether the longitude is east or west longitude.
+	GPSIFD_TagType_GPSLongitude         GPSIFD_TagType = 4  // ingore # Indicates the longitude.
+	GPSIFD_TagType_GPSAltitudeRef       GPSIFD_TagType = 5  // ingore # Indicates the altitude used as the reference altitude.
+	GPSIFD_TagType_GPSAltitude          GPSIFD_TagType = 6  // ingore # Indicates the altitude based on the reference in GPSAltitudeRef.
+	GPSIFD_TagType_GPSTimeStamp         GPSIFD_TagType = 7  // ingore # Indicates the time as UTC (Coordinated Universal Time).
+	GPSIFD_TagType_GPSSatellites        GPSIFD_TagType = 8  // ingore # Indicates the GPS satellites used for measurements.
+	GPSIFD_TagType_GPSStatus            GPSIFD_TagType = 9  // ingore # Indicates the status of the GPS receiver when the image is recorded.
+	GPSIFD_TagType_GPSMeasureMode       GPSIFD_TagType = 10 // ingore # Indicates the GPS measurement mode.
+	GPSIFD_TagType_GPSDOP               GPSIFD_TagType = 11 // ingore # Indicates the GPS DOP (data degree of precision).
+	GPSIFD_TagType_GPSSpeedRef          GPSIFD_TagType = 12 // ingore # Indicates the unit used to express the GPS receiver speed of movement.
+	GPSIFD_TagType_GPSSpeed             GPSIFD_TagType = 13 // ingore # Indicates the speed of GPS receiver movement.
+	GPSIFD_TagType_GPSTrackRef          GPSIFD_TagType = 14 // ingore # Indicates the reference for giving the direction of GPS receiver movement.
+	GPSIFD_TagType_GPSTrack             GPSIFD_TagType = 15 // ingore # Indicates the direction of GPS receiver movement.
+	GPSIFD_TagType_GPSImgDirectionRef   GPSIFD_TagType = 16 // ingore # Indicates the reference for giving the direction of the image when it is captured.
+	GPSIFD_TagType_GPSImgDirection      GPSIFD_TagType = 17 // ingore # Indicates the direction of the image when it was captured.
+	GPSIFD_TagType_GPSMapDatum          GPSIFD_TagType = 18 // ingore # Indicates the geodetic survey data used by the GPS receiver.
+	GPSIFD_TagType_GPSDestLatitudeRef   GPSIFD_TagType = 19 // ingore # Indicates whether the latitude of the destination point is north or south latitude.
+	GPSIFD_TagType_GPSDestLatitude      GPSIFD_TagType = 20 // ingore # Indicates the latitude of the destination point.
+	GPSIFD_TagType_GPSDestLongitudeRef  GPSIFD_TagType = 21 // ingore # Indicates whether the longitude of the destination point is east or west longitude.
+	GPSIFD_TagType_GPSDestLongitude     GPSIFD_TagType = 22 // ingore # Indicates the longitude of the destination point.
+	GPSIFD_TagType_GPSDestBearingRef    GPSIFD_TagType = 23 // ingore # Indicates the reference used for giving the bearing to the destination point.
+	GPSIFD_TagType_GPSDestBearing       GPSIFD_TagType = 24 // ingore # Indicates the bearing to the destination point.
+	GPSIFD_TagType_GPSDestDistanceRef   GPSIFD_TagType = 25 // ingore # Indicates the unit used to express the distance to the destination point.
+	GPSIFD_TagType_GPSDestDistance      GPSIFD_TagType = 26 // ingore # Indicates the distance to the destination point.
+	GPSIFD_TagType_GPSProcessingMethod  GPSIFD_TagType = 27 // ingore # A character string recording the name of the method used for location finding.
+	GPSIFD_TagType_GPSAreaInformation   GPSIFD_TagType = 28 // ingore # A character string recording the name of the GPS area.
+	GPSIFD_TagType_GPSDateStamp         GPSIFD_TagType = 29 // ingore # A character string recording date and time information relative to UTC (Coordinated Universal Time).
+	GPSIFD_TagType_GPSDifferential      GPSIFD_TagType = 30 // ingore # Indicates whether differential correction is applied to the GPS receiver.
+	GPSIFD_TagType_GPSHPositioningError GPSIFD_TagType = 31 // ingore # Indicates the horizontal positioning error in meters.
 )
 
 // Interoperability Tags
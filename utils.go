@@ -34,3 +34,11 @@ func minInt(a, b int) int {
 	}
 	return b
 }
+
+// maxInt returns the larger of x or y.
+func maxInt(a, b int) int {
+	if a >= b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,584 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Namespaces of the common XMP schemas.
+const (
+	XMPNamespace_RDF       = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	XMPNamespace_DC        = "http://purl.org/dc/elements/1.1/"
+	XMPNamespace_XMP       = "http://ns.adobe.com/xap/1.0/"
+	XMPNamespace_XMPRights = "http://ns.adobe.com/xap/1.0/rights/"
+	XMPNamespace_Photoshop = "http://ns.adobe.com/photoshop/1.0/"
+	XMPNamespace_TIFF      = "http://ns.adobe.com/tiff/1.0/"
+	XMPNamespace_EXIF      = "http://ns.adobe.com/exif/1.0/"
+
+	xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+)
+
+// xmpPrefixes are the prefixes written for the common namespaces.
+var xmpPrefixes = map[string]string{
+	XMPNamespace_DC:        "dc",
+	XMPNamespace_XMP:       "xmp",
+	XMPNamespace_XMPRights: "xmpRights",
+	XMPNamespace_Photoshop: "photoshop",
+	XMPNamespace_TIFF:      "tiff",
+	XMPNamespace_EXIF:      "exif",
+}
+
+// An XMPKind is the form of the value of an XMP property.
+type XMPKind int
+
+const (
+	XMPKind_Simple XMPKind = iota // A single value.
+	XMPKind_Bag                   // An unordered array, rdf:Bag.
+	XMPKind_Seq                   // An ordered array, rdf:Seq.
+	XMPKind_Alt                   // Alternatives, such as translations, rdf:Alt.
+)
+
+var _XMPKindTable = map[XMPKind]string{
+	XMPKind_Simple: `XMPKind_Simple`,
+	XMPKind_Bag:    `XMPKind_Bag`,
+	XMPKind_Seq:    `XMPKind_Seq`,
+	XMPKind_Alt:    `XMPKind_Alt`,
+}
+
+func (p XMPKind) String() string {
+	if name, ok := _XMPKindTable[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("XMPKind_Unknown(%d)", int(p))
+}
+
+// An XMPValue is a value of an XMP property, with its xml:lang qualifier.
+type XMPValue struct {
+	Value string
+	Lang  string
+}
+
+// An XMPProperty is a property of an XMP packet. Simple properties have
+// exactly one value, arrays have one value per item.
+type XMPProperty struct {
+	Namespace string
+	Name      string
+	Kind      XMPKind
+	Values    []XMPValue
+}
+
+// XMP is the metadata of an XMP packet.
+//
+// It is a light model of the RDF/XML of the packet that holds the simple
+// and array properties of the top level of the packet, such as those of
+// the Dublin Core, XMP basic and Photoshop schemas. Structured properties
+// and arrays of structures are skipped, so they do not survive a round
+// trip through ParseXMP and Bytes; write the raw packet to keep them.
+type XMP struct {
+	Properties []XMPProperty
+}
+
+// ParseXMP parses an XMP packet, with or without its xpacket wrapper.
+func ParseXMP(packet []byte) (*XMP, error) {
+	x := &XMP{}
+	d := xml.NewDecoder(bytes.NewReader(bytes.TrimRight(packet, "\x00")))
+	found := false
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("tiff: ParseXMP, %v", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Space != XMPNamespace_RDF || start.Name.Local != "Description" {
+			continue
+		}
+		found = true
+		if err = x.parseDescription(d, start); err != nil {
+			return nil, fmt.Errorf("tiff: ParseXMP, %v", err)
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("tiff: ParseXMP, no rdf:Description")
+	}
+	return x, nil
+}
+
+// parseDescription reads the properties of an rdf:Description element.
+func (x *XMP) parseDescription(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		if isXMPProperty(attr.Name) {
+			x.add(XMPProperty{
+				Namespace: attr.Name.Space,
+				Name:      attr.Name.Local,
+				Values:    []XMPValue{{Value: attr.Value}},
+			})
+		}
+	}
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			p, ok, err := parseXMPProperty(d, tok)
+			if err != nil {
+				return err
+			}
+			if ok {
+				x.add(p)
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// isXMPProperty reports whether an attribute of an rdf:Description is a
+// property rather than RDF syntax or a namespace declaration.
+func isXMPProperty(name xml.Name) bool {
+	switch name.Space {
+	case "", "xmlns", XMPNamespace_RDF, xmlNamespace:
+		return false
+	}
+	return true
+}
+
+// parseXMPProperty reads a property element. ok is false for structured
+// values, which are skipped.
+func parseXMPProperty(d *xml.Decoder, start xml.StartElement) (p XMPProperty, ok bool, err error) {
+	p = XMPProperty{Namespace: start.Name.Space, Name: start.Name.Local}
+	var lang string
+	for _, attr := range start.Attr {
+		switch {
+		case attr.Name.Space == XMPNamespace_RDF && attr.Name.Local == "resource":
+			p.Values = []XMPValue{{Value: attr.Value}}
+			return p, true, d.Skip()
+		case attr.Name.Space == XMPNamespace_RDF && attr.Name.Local == "parseType":
+			return p, false, d.Skip()
+		case attr.Name.Space == xmlNamespace && attr.Name.Local == "lang":
+			lang = attr.Value
+		case isXMPProperty(attr.Name):
+			// A structure with its fields as attributes.
+			return p, false, d.Skip()
+		}
+	}
+	var text bytes.Buffer
+	ok = true
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return p, false, err
+		}
+		switch tok := tok.(type) {
+		case xml.CharData:
+			text.Write(tok)
+		case xml.StartElement:
+			kind, isArray := xmpArrayKinds[tok.Name.Local]
+			if tok.Name.Space != XMPNamespace_RDF || !isArray {
+				ok = false
+				if err = d.Skip(); err != nil {
+					return p, false, err
+				}
+				continue
+			}
+			p.Kind = kind
+			if p.Values, err = parseXMPArray(d); err != nil {
+				return p, false, err
+			}
+			if p.Values == nil {
+				ok = false
+			}
+		case xml.EndElement:
+			if ok && p.Kind == XMPKind_Simple {
+				p.Values = []XMPValue{{Value: text.String(), Lang: lang}}
+			}
+			return p, ok, nil
+		}
+	}
+}
+
+var xmpArrayKinds = map[string]XMPKind{
+	"Bag": XMPKind_Bag,
+	"Seq": XMPKind_Seq,
+	"Alt": XMPKind_Alt,
+}
+
+// parseXMPArray reads the rdf:li items of an array. It returns nil if an
+// item is a structure.
+func parseXMPArray(d *xml.Decoder) (values []XMPValue, err error) {
+	values = []XMPValue{}
+	structured := false
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			if tok.Name.Space != XMPNamespace_RDF || tok.Name.Local != "li" {
+				structured = true
+				if err = d.Skip(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			v := XMPValue{}
+			for _, attr := range tok.Attr {
+				if attr.Name.Space == xmlNamespace && attr.Name.Local == "lang" {
+					v.Lang = attr.Value
+				} else if isXMPProperty(attr.Name) || attr.Name.Local == "parseType" {
+					structured = true
+				}
+			}
+			var text bytes.Buffer
+		item:
+			for {
+				tok, err := d.Token()
+				if err != nil {
+					return nil, err
+				}
+				switch tok := tok.(type) {
+				case xml.CharData:
+					text.Write(tok)
+				case xml.StartElement:
+					structured = true
+					if err = d.Skip(); err != nil {
+						return nil, err
+					}
+				case xml.EndElement:
+					break item
+				}
+			}
+			v.Value = text.String()
+			values = append(values, v)
+		case xml.EndElement:
+			if structured {
+				return nil, nil
+			}
+			return values, nil
+		}
+	}
+}
+
+func (x *XMP) add(p XMPProperty) {
+	if i := x.find(p.Namespace, p.Name); i >= 0 {
+		x.Properties[i] = p
+		return
+	}
+	x.Properties = append(x.Properties, p)
+}
+
+func (x *XMP) find(ns, name string) int {
+	for i, p := range x.Properties {
+		if p.Namespace == ns && p.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Property returns the property ns:name.
+func (x *XMP) Property(ns, name string) (p XMPProperty, ok bool) {
+	if i := x.find(ns, name); i >= 0 {
+		return x.Properties[i], true
+	}
+	return
+}
+
+// Get returns the value of the property ns:name: the value of a simple
+// property, the x-default item of an Alt array, or the first item of
+// other arrays.
+func (x *XMP) Get(ns, name string) (string, bool) {
+	p, ok := x.Property(ns, name)
+	if !ok || len(p.Values) == 0 {
+		return "", false
+	}
+	if p.Kind == XMPKind_Alt {
+		for _, v := range p.Values {
+			if v.Lang == "x-default" {
+				return v.Value, true
+			}
+		}
+	}
+	return p.Values[0].Value, true
+}
+
+// Values returns the values of the items of the property ns:name.
+func (x *XMP) Values(ns, name string) []string {
+	p, _ := x.Property(ns, name)
+	var values []string
+	for _, v := range p.Values {
+		values = append(values, v.Value)
+	}
+	return values
+}
+
+// Set sets the simple property ns:name.
+func (x *XMP) Set(ns, name, value string) {
+	x.add(XMPProperty{Namespace: ns, Name: name, Values: []XMPValue{{Value: value}}})
+}
+
+// SetArray sets the property ns:name to a Bag or Seq array.
+func (x *XMP) SetArray(ns, name string, kind XMPKind, values []string) {
+	p := XMPProperty{Namespace: ns, Name: name, Kind: kind}
+	for _, v := range values {
+		p.Values = append(p.Values, XMPValue{Value: v})
+	}
+	x.add(p)
+}
+
+// SetLangAlt sets the x-default item of the Alt array ns:name, keeping
+// its other languages.
+func (x *XMP) SetLangAlt(ns, name, value string) {
+	p, ok := x.Property(ns, name)
+	if !ok || p.Kind != XMPKind_Alt {
+		p = XMPProperty{Namespace: ns, Name: name, Kind: XMPKind_Alt}
+	}
+	values := []XMPValue{{Value: value, Lang: "x-default"}}
+	for _, v := range p.Values {
+		if v.Lang != "x-default" {
+			values = append(values, v)
+		}
+	}
+	p.Values = values
+	x.add(p)
+}
+
+// Delete removes the property ns:name.
+func (x *XMP) Delete(ns, name string) {
+	if i := x.find(ns, name); i >= 0 {
+		x.Properties = append(x.Properties[:i], x.Properties[i+1:]...)
+	}
+}
+
+// Title returns dc:title.
+func (x *XMP) Title() (string, bool) {
+	return x.Get(XMPNamespace_DC, "title")
+}
+
+// Description returns dc:description.
+func (x *XMP) Description() (string, bool) {
+	return x.Get(XMPNamespace_DC, "description")
+}
+
+// Rights returns dc:rights.
+func (x *XMP) Rights() (string, bool) {
+	return x.Get(XMPNamespace_DC, "rights")
+}
+
+// Creator returns the authors of dc:creator.
+func (x *XMP) Creator() []string {
+	return x.Values(XMPNamespace_DC, "creator")
+}
+
+// Subject returns the keywords of dc:subject.
+func (x *XMP) Subject() []string {
+	return x.Values(XMPNamespace_DC, "subject")
+}
+
+func (x *XMP) SetTitle(s string) {
+	x.SetLangAlt(XMPNamespace_DC, "title", s)
+}
+
+func (x *XMP) SetDescription(s string) {
+	x.SetLangAlt(XMPNamespace_DC, "description", s)
+}
+
+func (x *XMP) SetRights(s string) {
+	x.SetLangAlt(XMPNamespace_DC, "rights", s)
+}
+
+func (x *XMP) SetCreator(names ...string) {
+	x.SetArray(XMPNamespace_DC, "creator", XMPKind_Seq, names)
+}
+
+func (x *XMP) SetSubject(keywords ...string) {
+	x.SetArray(XMPNamespace_DC, "subject", XMPKind_Bag, keywords)
+}
+
+// CreatorTool returns xmp:CreatorTool, the application that created the
+// resource.
+func (x *XMP) CreatorTool() (string, bool) {
+	return x.Get(XMPNamespace_XMP, "CreatorTool")
+}
+
+// Rating returns xmp:Rating, from -1 (rejected) to 5.
+func (x *XMP) Rating() (float64, bool) {
+	s, ok := x.Get(XMPNamespace_XMP, "Rating")
+	if !ok {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return v, err == nil
+}
+
+// Label returns xmp:Label.
+func (x *XMP) Label() (string, bool) {
+	return x.Get(XMPNamespace_XMP, "Label")
+}
+
+// CreateDate returns xmp:CreateDate.
+func (x *XMP) CreateDate() (time.Time, bool) {
+	return x.Date(XMPNamespace_XMP, "CreateDate")
+}
+
+// ModifyDate returns xmp:ModifyDate.
+func (x *XMP) ModifyDate() (time.Time, bool) {
+	return x.Date(XMPNamespace_XMP, "ModifyDate")
+}
+
+// Headline returns photoshop:Headline.
+func (x *XMP) Headline() (string, bool) {
+	return x.Get(XMPNamespace_Photoshop, "Headline")
+}
+
+// City returns photoshop:City.
+func (x *XMP) City() (string, bool) {
+	return x.Get(XMPNamespace_Photoshop, "City")
+}
+
+// Country returns photoshop:Country.
+func (x *XMP) Country() (string, bool) {
+	return x.Get(XMPNamespace_Photoshop, "Country")
+}
+
+// Credit returns photoshop:Credit.
+func (x *XMP) Credit() (string, bool) {
+	return x.Get(XMPNamespace_Photoshop, "Credit")
+}
+
+// DateCreated returns photoshop:DateCreated, when the content was created.
+func (x *XMP) DateCreated() (time.Time, bool) {
+	return x.Date(XMPNamespace_Photoshop, "DateCreated")
+}
+
+// xmpDateLayouts are the forms of the XMP date type, from ISO 8601.
+var xmpDateLayouts = []string{
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// Date returns the value of the date property ns:name. Dates without a
+// time zone are returned in UTC.
+func (x *XMP) Date(ns, name string) (t time.Time, ok bool) {
+	s, ok := x.Get(ns, name)
+	if !ok {
+		return
+	}
+	s = strings.TrimSpace(s)
+	for _, layout := range xmpDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return t, false
+}
+
+// SetDate sets the date property ns:name.
+func (x *XMP) SetDate(ns, name string, t time.Time) {
+	x.Set(ns, name, t.Format(time.RFC3339Nano))
+}
+
+// Bytes returns x as an XMP packet. The packet is padded with whitespace,
+// as the XMP specification recommends, so that it can be edited in place.
+func (x *XMP) Bytes() []byte {
+	prefixes := make(map[string]string)
+	var spaces []string
+	for _, p := range x.Properties {
+		if _, ok := prefixes[p.Namespace]; ok {
+			continue
+		}
+		prefix, ok := xmpPrefixes[p.Namespace]
+		if !ok {
+			prefix = fmt.Sprintf("ns%d", len(spaces)+1)
+		}
+		prefixes[p.Namespace] = prefix
+		spaces = append(spaces, p.Namespace)
+	}
+	sort.Slice(spaces, func(i, j int) bool { return prefixes[spaces[i]] < prefixes[spaces[j]] })
+
+	var buf bytes.Buffer
+	buf.WriteString("<?xpacket begin=\"\ufeff\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	buf.WriteString("<x:xmpmeta xmlns:x=\"adobe:ns:meta/\">\n")
+	buf.WriteString(" <rdf:RDF xmlns:rdf=\"" + XMPNamespace_RDF + "\">\n")
+	buf.WriteString("  <rdf:Description rdf:about=\"\"")
+	for _, ns := range spaces {
+		fmt.Fprintf(&buf, "\n    xmlns:%s=\"%s\"", prefixes[ns], xmlEscape(ns))
+	}
+	buf.WriteString(">\n")
+	for _, p := range x.Properties {
+		name := prefixes[p.Namespace] + ":" + p.Name
+		if p.Kind == XMPKind_Simple {
+			if len(p.Values) > 0 {
+				fmt.Fprintf(&buf, "   <%s%s>%s</%s>\n", name, xmpLang(p.Values[0].Lang), xmlEscape(p.Values[0].Value), name)
+			}
+			continue
+		}
+		array := strings.TrimPrefix(p.Kind.String(), "XMPKind_")
+		fmt.Fprintf(&buf, "   <%s>\n    <rdf:%s>\n", name, array)
+		for _, v := range p.Values {
+			fmt.Fprintf(&buf, "     <rdf:li%s>%s</rdf:li>\n", xmpLang(v.Lang), xmlEscape(v.Value))
+		}
+		fmt.Fprintf(&buf, "    </rdf:%s>\n   </%s>\n", array, name)
+	}
+	buf.WriteString("  </rdf:Description>\n </rdf:RDF>\n</x:xmpmeta>\n")
+	for i := 0; i < 20; i++ {
+		buf.WriteString(strings.Repeat(" ", 99) + "\n")
+	}
+	buf.WriteString("<?xpacket end=\"w\"?>")
+	return buf.Bytes()
+}
+
+func xmpLang(lang string) string {
+	if lang == "" {
+		return ""
+	}
+	return " xml:lang=\"" + xmlEscape(lang) + "\""
+}
+
+// XMPPacket returns the raw XMP packet of the XMP tag.
+func (p *IFD) XMPPacket() ([]byte, bool) {
+	return p.TagGetter().GetXMP()
+}
+
+// XMP returns the parsed XMP packet. It returns nil and no error if the
+// image has no XMP tag.
+func (p *IFD) XMP() (*XMP, error) {
+	packet, ok := p.XMPPacket()
+	if !ok {
+		return nil, nil
+	}
+	return ParseXMP(packet)
+}
+
+// UpdateXMP replaces the XMP packet of the i-th image of the file f, or
+// adds one if it has none. A nil packet removes the XMP tag.
+//
+// The edited IFD is appended to f, with a copy of its tag values, and
+// replaces the original in the chain of IFDs; only the image data and the
+// other images are left in place.
+func UpdateXMP(f io.ReadWriteSeeker, i int, packet []byte) error {
+	if packet == nil {
+		return editIFD(f, i, nil, TagType_XMP)
+	}
+	return editIFD(f, i, []ifdEntry{{TagType_XMP, DataType_Byte, bytesData(packet)}})
+}
@@ -0,0 +1,230 @@
+// Copyright 2015 <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"image"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestXMP_read(t *testing.T) {
+	p := openGeoTiff(t, "./testdata/gdal_autotest/gdrivers/data/byte_with_xmp.tif")
+	defer p.Close()
+	x, err := p.ImageXMP(0, 0)
+	if err != nil || x == nil {
+		t.Fatalf("XMP = %v, %v", x, err)
+	}
+	if v, ok := x.Title(); !ok || v != "Title" {
+		t.Errorf("Title = %q, %v", v, ok)
+	}
+	if v, ok := x.Description(); !ok || v != "Description" {
+		t.Errorf("Description = %q, %v", v, ok)
+	}
+	if v := x.Subject(); !reflect.DeepEqual(v, []string{"XMP", "Test"}) {
+		t.Errorf("Subject = %q", v)
+	}
+	if packet, ok := p.Ifd[0][0].XMPPacket(); !ok || !bytes.HasPrefix(packet, []byte("<?xpacket begin=")) {
+		t.Errorf("XMPPacket = %.20q, %v", packet, ok)
+	}
+}
+
+const testXMPPacket = `<x:xmpmeta xmlns:x="adobe:ns:meta/">
+ <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+  <rdf:Description rdf:about=""
+    xmlns:xmp="http://ns.adobe.com/xap/1.0/"
+    xmlns:photoshop="http://ns.adobe.com/photoshop/1.0/"
+    xmp:CreatorTool="Scanner 2.0"
+    xmp:Rating="4"
+    photoshop:City="Lyon">
+   <xmp:CreateDate>2015-03-04T05:06:07+01:00</xmp:CreateDate>
+   <photoshop:DateCreated>2015-03</photoshop:DateCreated>
+  </rdf:Description>
+  <rdf:Description rdf:about=""
+    xmlns:dc="http://purl.org/dc/elements/1.1/"
+    xmlns:xmpRights="http://ns.adobe.com/xap/1.0/rights/"
+    xmlns:iptc="http://iptc.org/std/Iptc4xmpCore/1.0/xmlns/">
+   <dc:creator><rdf:Seq><rdf:li>A &amp; B</rdf:li><rdf:li>C</rdf:li></rdf:Seq></dc:creator>
+   <dc:rights>
+    <rdf:Alt>
+     <rdf:li xml:lang="fr-FR">Tous droits</rdf:li>
+     <rdf:li xml:lang="x-default">All rights</rdf:li>
+    </rdf:Alt>
+   </dc:rights>
+   <xmpRights:WebStatement rdf:resource="http://example.com/license"/>
+   <iptc:CreatorContactInfo rdf:parseType="Resource">
+    <iptc:CiAdrCity>Lyon</iptc:CiAdrCity>
+   </iptc:CreatorContactInfo>
+   <iptc:Location><rdf:Description iptc:City="Paris"/></iptc:Location>
+  </rdf:Description>
+ </rdf:RDF>
+</x:xmpmeta>`
+
+func TestParseXMP(t *testing.T) {
+	x, err := ParseXMP([]byte(testXMPPacket))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := x.CreatorTool(); !ok || v != "Scanner 2.0" {
+		t.Errorf("CreatorTool = %q, %v", v, ok)
+	}
+	if v, ok := x.Rating(); !ok || v != 4 {
+		t.Errorf("Rating = %v, %v", v, ok)
+	}
+	if v, ok := x.City(); !ok || v != "Lyon" {
+		t.Errorf("City = %q, %v", v, ok)
+	}
+	want := time.Date(2015, 3, 4, 5, 6, 7, 0, time.FixedZone("", 3600))
+	if v, ok := x.CreateDate(); !ok || !v.Equal(want) {
+		t.Errorf("CreateDate = %v, %v", v, ok)
+	}
+	if v, ok := x.DateCreated(); !ok || !v.Equal(time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("DateCreated = %v, %v", v, ok)
+	}
+	if v := x.Creator(); !reflect.DeepEqual(v, []string{"A & B", "C"}) {
+		t.Errorf("Creator = %q", v)
+	}
+	if v, ok := x.Rights(); !ok || v != "All rights" {
+		t.Errorf("Rights = %q, %v", v, ok)
+	}
+	if v, ok := x.Get(XMPNamespace_XMPRights, "WebStatement"); !ok || v != "http://example.com/license" {
+		t.Errorf("WebStatement = %q, %v", v, ok)
+	}
+	const iptc = "http://iptc.org/std/Iptc4xmpCore/1.0/xmlns/"
+	for _, name := range []string{"CreatorContactInfo", "Location"} {
+		if _, ok := x.Property(iptc, name); ok {
+			t.Errorf("structure %s was not skipped", name)
+		}
+	}
+	if len(x.Properties) != 8 {
+		t.Errorf("%d properties, want 8", len(x.Properties))
+	}
+
+	x2, err := ParseXMP(x.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(x, x2) {
+		t.Errorf("Bytes roundtrip:\n%v\n%v", x, x2)
+	}
+
+	for _, s := range []string{"", "<x:xmpmeta/>", "<rdf:RDF xmlns:rdf=\"" + XMPNamespace_RDF + "\"><rdf:Description>"} {
+		if _, err := ParseXMP([]byte(s)); err == nil {
+			t.Errorf("%q: no error", s)
+		}
+	}
+}
+
+func TestXMP_set(t *testing.T) {
+	x := &XMP{}
+	x.SetTitle("First")
+	x.SetCreator("Me")
+	x.SetSubject("a", "b")
+	x.Set(XMPNamespace_Photoshop, "Headline", "News")
+	x.SetDate(XMPNamespace_XMP, "ModifyDate", time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+	x.SetLangAlt(XMPNamespace_DC, "title", "Second")
+	if v, ok := x.Title(); !ok || v != "Second" {
+		t.Errorf("Title = %q, %v", v, ok)
+	}
+	if p, _ := x.Property(XMPNamespace_DC, "title"); len(p.Values) != 1 {
+		t.Errorf("title values = %v", p.Values)
+	}
+	x.Delete(XMPNamespace_DC, "creator")
+	if v := x.Creator(); v != nil {
+		t.Errorf("Creator = %q after Delete", v)
+	}
+
+	x2, err := ParseXMP(x.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(x, x2) {
+		t.Errorf("Bytes roundtrip:\n%v\n%v", x, x2)
+	}
+	if v, ok := x2.ModifyDate(); !ok || !v.Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("ModifyDate = %v, %v", v, ok)
+	}
+	if v, ok := x2.Headline(); !ok || v != "News" {
+		t.Errorf("Headline = %q, %v", v, ok)
+	}
+}
+
+func TestXMP_encode(t *testing.T) {
+	x := &XMP{}
+	x.SetTitle("Encoded")
+	var out bytes.Buffer
+	m := image.NewGray(image.Rect(0, 0, 8, 8))
+	if err := Encode(&out, m, &Options{XMP: x.Bytes()}); err != nil {
+		t.Fatal(err)
+	}
+	p, err := OpenReader(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	got, err := p.ImageXMP(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := got.Title(); !ok || v != "Encoded" {
+		t.Errorf("Title = %q, %v", v, ok)
+	}
+}
+
+func TestUpdateXMP(t *testing.T) {
+	// A COG has a chain of IFDs, so that editing the second one has to
+	// relink the first.
+	m := image.NewRGBA(image.Rect(0, 0, 700, 600))
+	for i := range m.Pix {
+		m.Pix[i] = uint8(i * 7)
+	}
+	var out bytes.Buffer
+	if err := EncodeCOG(&out, m, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	f := &memFile{data: out.Bytes()}
+
+	x := &XMP{}
+	x.SetTitle("Overview")
+	if err := UpdateXMP(f, 1, x.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateXMP(f, 0, []byte("<bad")); err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateXMP(f, 0, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateXMP(f, 5, nil); err == nil {
+		t.Errorf("missing image: no error")
+	}
+
+	p := f.open(t)
+	defer p.Close()
+	if p.ImageNum() != 2 {
+		t.Fatalf("%d images after editing, want 2", p.ImageNum())
+	}
+	if _, ok := p.Ifd[0][0].XMPPacket(); ok {
+		t.Errorf("image 0 has an XMP packet")
+	}
+	got, err := p.ImageXMP(1, 0)
+	if err != nil || got == nil {
+		t.Fatalf("XMP of image 1 = %v, %v", got, err)
+	}
+	if v, ok := got.Title(); !ok || v != "Overview" {
+		t.Errorf("Title = %q, %v", v, ok)
+	}
+	for i := 0; i < p.ImageNum(); i++ {
+		img, err := p.DecodeImage(i, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 && !reflect.DeepEqual(img.(*image.RGBA).Pix, m.Pix) {
+			t.Errorf("image 0 changed")
+		}
+	}
+}
@@ -162,9 +162,11 @@ var _TagTypeTable = map[TagType]string{
 	TagType_YCbCrPositioning:             `TagType_YCbCrPositioning`,             // SHORT, 1, # Default=1
 	TagType_ReferenceBlackWhite:          `TagType_ReferenceBlackWhite`,          // LONG , *, # 2*SamplesPerPixel
 	TagType_StripRowCounts:               `TagType_StripRowCounts`,               // ingore # Defined in the Mixed Raster Content part of RFC 2301, used to replace RowsPerStrip for IFDs with variable-sized strips.
-	TagType_XMP:                          `TagType_XMP`,                          // ingore # XML packet containing XMP metadata
+	TagType_XMP:                          `TagType_XMP`,                          // BYTE/UNDEFINED # XML packet containing XMP metadata
 	TagType_ImageID:                      `TagType_ImageID`,                      // ingore # OPI-related.
 	TagType_ImageLayer:                   `TagType_ImageLayer`,                   // ingore # Defined in the Mixed Raster Content part of RFC 2301, used to denote the particular function of this Image in the mixed raster scheme.
+	TagType_CFARepeatPatternDim:          `TagType_CFARepeatPatternDim`,          // SHORT, 2, # Used in Raw IFD of DNG files.
+	TagType_CFAPattern:                   `TagType_CFAPattern`,                   // BYTE # Used in Raw IFD of DNG files.
 	TagType_Copyright:                    `TagType_Copyright`,                    // ASCII
 	TagType_WangAnnotation:               `TagType_WangAnnotation`,               // ingore # Annotation data, as used in 'Imaging for Windows'.
 	TagType_MDFileTag:                    `TagType_MDFileTag`,                    // ingore # Specifies the pixel data format encoding in the Molecular Dynamics GEL file format.
@@ -176,15 +178,15 @@ var _TagTypeTable = map[TagType]string{
 	TagType_MDPrepTime:                   `TagType_MDPrepTime`,                   // ingore # Time the sample was prepared, as used in the Molecular Dynamics GEL file format.
 	TagType_MDFileUnits:                  `TagType_MDFileUnits`,                  // ingore # Units for data in this file, as used in the Molecular Dynamics GEL file format.
 	TagType_ModelPixelScaleTag:           `TagType_ModelPixelScaleTag`,           // DOUBLE # Used in interchangeable GeoTIFF files.
-	TagType_IPTC:                         `TagType_IPTC`,                         // ingore # IPTC (International Press Telecommunications Council) metadata.
+	TagType_IPTC:                         `TagType_IPTC`,                         // UNDEFINED/LONG # IPTC (International Press Telecommunications Council) metadata.
 	TagType_INGRPacketDataTag:            `TagType_INGRPacketDataTag`,            // ingore # Intergraph Application specific storage.
 	TagType_INGRFlagRegisters:            `TagType_INGRFlagRegisters`,            // ingore # Intergraph Application specific flags.
 	TagType_IrasBTransformationMatrix:    `TagType_IrasBTransformationMatrix`,    // DOUBLE, 17 # Originally part of Intergraph's GeoTIFF tags, but likely understood by IrasB only.
 	TagType_ModelTiepointTag:             `TagType_ModelTiepointTag`,             // DOUBLE # Originally part of Intergraph's GeoTIFF tags, but now used in interchangeable GeoTIFF files.
 	TagType_ModelTransformationTag:       `TagType_ModelTransformationTag`,       // DOUBLE, 16 # Used in interchangeable GeoTIFF files.
-	TagType_Photoshop:                    `TagType_Photoshop`,                    // ingore # Collection of Photoshop 'Image Resource Blocks'.
+	TagType_Photoshop:                    `TagType_Photoshop`,                    // BYTE/UNDEFINED # Collection of Photoshop 'Image Resource Blocks'.
 	TagType_ExifIFD:                      `TagType_ExifIFD`,                      // IFD    # A pointer to the Exif IFD.
-	TagType_ICCProfile:                   `TagType_ICCProfile`,                   // ingore # ICC profile data.
+	TagType_ICCProfile:                   `TagType_ICCProfile`,                   // UNDEFINED # ICC profile data.
 	TagType_GeoKeyDirectoryTag:           `TagType_GeoKeyDirectoryTag`,           // SHORT, *, # >= 4
 	TagType_GeoDoubleParamsTag:           `TagType_GeoDoubleParamsTag`,           // DOUBLE
 	TagType_GeoAsciiParamsTag:            `TagType_GeoAsciiParamsTag`,            // ASCII
@@ -194,51 +196,54 @@ var _TagTypeTable = map[TagType]string{
 	TagType_HylaFAXFaxRecvTime:           `TagType_HylaFAXFaxRecvTime`,           // ingore # Used by HylaFAX.
 	TagType_ImageSourceData:              `TagType_ImageSourceData`,              // ingore # Used by Adobe Photoshop.
 	TagType_InteroperabilityIFD:          `TagType_InteroperabilityIFD`,          // IFD    # A pointer to the Exif-related Interoperability IFD.
-	TagType_GDAL_METADATA:                `TagType_GDAL_METADATA`,                // ingore # Used by the GDAL library, holds an XML list of name=value 'metadata' values about the image as a whole, and about specific samples.
-	TagType_GDAL_NODATA:                  `TagType_GDAL_NODATA`,                  // ingore # Used by the GDAL library, contains an ASCII encoded nodata or background pixel value.
+	TagType_GDAL_METADATA:                `TagType_GDAL_METADATA`,                // ASCII  # Used by the GDAL library, holds an XML list of name=value 'metadata' values about the image as a whole, and about specific samples.
+	TagType_GDAL_NODATA:                  `TagType_GDAL_NODATA`,                  // ASCII  # Used by the GDAL library, contains an ASCII encoded nodata or background pixel value.
 	TagType_OceScanjobDescription:        `TagType_OceScanjobDescription`,        // ingore # Used in the Oce scanning process.
 	TagType_OceApplicationSelector:       `TagType_OceApplicationSelector`,       // ingore # Used in the Oce scanning process.
 	TagType_OceIdentificationNumber:      `TagType_OceIdentificationNumber`,      // ingore # Used in the Oce scanning process.
 	TagType_OceImageLogicCharacteristics: `TagType_OceImageLogicCharacteristics`, // ingore # Used in the Oce scanning process.
-	TagType_DNGVersion:                   `TagType_DNGVersion`,                   // ingore # Used in IFD 0 of DNG files.
-	TagType_DNGBackwardVersion:           `TagType_DNGBackwardVersion`,           // ingore # Used in IFD 0 of DNG files.
-	TagType_UniqueCameraModel:            `TagType_UniqueCameraModel`,            // ingore # Used in IFD 0 of DNG files.
+	TagType_DNGVersion:                   `TagType_DNGVersion`,                   // BYTE, 4, # Used in IFD 0 of DNG files.
+	TagType_DNGBackwardVersion:           `TagType_DNGBackwardVersion`,           // BYTE, 4, # Used in IFD 0 of DNG files.
+	TagType_UniqueCameraModel:            `TagType_UniqueCameraModel`,            // ASCII # Used in IFD 0 of DNG files.
 	TagType_LocalizedCameraModel:         `TagType_LocalizedCameraModel`,         // ingore # Used in IFD 0 of DNG files.
-	TagType_CFAPlaneColor:                `TagType_CFAPlaneColor`,                // ingore # Used in Raw IFD of DNG files.
-	TagType_CFALayout:                    `TagType_CFALayout`,                    // ingore # Used in Raw IFD of DNG files.
-	TagType_LinearizationTable:           `TagType_LinearizationTable`,           // ingore # Used in Raw IFD of DNG files.
-	TagType_BlackLevelRepeatDim:          `TagType_BlackLevelRepeatDim`,          // ingore # Used in Raw IFD of DNG files.
-	TagType_BlackLevel:                   `TagType_BlackLevel`,                   // ingore # Used in Raw IFD of DNG files.
-	TagType_BlackLevelDeltaH:             `TagType_BlackLevelDeltaH`,             // ingore # Used in Raw IFD of DNG files.
-	TagType_BlackLevelDeltaV:             `TagType_BlackLevelDeltaV`,             // ingore # Used in Raw IFD of DNG files.
-	TagType_WhiteLevel:                   `TagType_WhiteLevel`,                   // ingore # Used in Raw IFD of DNG files.
-	TagType_DefaultScale:                 `TagType_DefaultScale`,                 // ingore # Used in Raw IFD of DNG files.
-	TagType_DefaultCropOrigin:            `TagType_DefaultCropOrigin`,            // ingore # Used in Raw IFD of DNG files.
-	TagType_DefaultCropSize:              `TagType_DefaultCropSize`,              // ingore # Used in Raw IFD of DNG files.
-	TagType_ColorMatrix1:                 `TagType_ColorMatrix1`,                 // ingore # Used in IFD 0 of DNG files.
-	TagType_ColorMatrix2:                 `TagType_ColorMatrix2`,                 // ingore # Used in IFD 0 of DNG files.
-	TagType_CameraCalibration1:           `TagType_CameraCalibration1`,           // ingore # Used in IFD 0 of DNG files.
-	TagType_CameraCalibration2:           `TagType_CameraCalibration2`,           // ingore # Used in IFD 0 of DNG files.
-	TagType_ReductionMatrix1:             `TagType_ReductionMatrix1`,             // ingore # Used in IFD 0 of DNG files.
-	TagType_ReductionMatrix2:             `TagType_ReductionMatrix2`,             // ingore # Used in IFD 0 of DNG files.
-	TagType_AnalogBalance:                `TagType_AnalogBalance`,                // ingore # Used in IFD 0 of DNG files.
-	TagType_AsShotNeutral:                `TagType_AsShotNeutral`,                // ingore # Used in IFD 0 of DNG files.
-	TagType_AsShotWhiteXY:                `TagType_AsShotWhiteXY`,                // ingore # Used in IFD 0 of DNG files.
-	TagType_BaselineExposure:             `TagType_BaselineExposure`,             // ingore # Used in IFD 0 of DNG files.
-	TagType_BaselineNoise:                `TagType_BaselineNoise`,                // ingore # Used in IFD 0 of DNG files.
-	TagType_BaselineSharpness:            `TagType_BaselineSharpness`,            // ingore # Used in IFD 0 of DNG files.
-	TagType_BayerGreenSplit:              `TagType_BayerGreenSplit`,              // ingore # Used in Raw IFD of DNG files.
-	TagType_LinearResponseLimit:          `TagType_LinearResponseLimit`,          // ingore # Used in IFD 0 of DNG files.
-	TagType_CameraSerialNumber:           `TagType_CameraSerialNumber`,           // ingore # Used in IFD 0 of DNG files.
-	TagType_LensInfo:                     `TagType_LensInfo`,                     // ingore # Used in IFD 0 of DNG files.
-	TagType_ChromaBlurRadius:             `TagType_ChromaBlurRadius`,             // ingore # Used in Raw IFD of DNG files.
-	TagType_AntiAliasStrength:            `TagType_AntiAliasStrength`,            // ingore # Used in Raw IFD of DNG files.
+	TagType_CFAPlaneColor:                `TagType_CFAPlaneColor`,                // BYTE # Used in Raw IFD of DNG files.
+	TagType_CFALayout:                    `TagType_CFALayout`,                    // SHORT, 1, # Default=1. Used in Raw IFD of DNG files.
+	TagType_LinearizationTable:           `TagType_LinearizationTable`,           // SHORT # Used in Raw IFD of DNG files.
+	TagType_BlackLevelRepeatDim:          `TagType_BlackLevelRepeatDim`,          // SHORT, 2, # Used in Raw IFD of DNG files.
+	TagType_BlackLevel:                   `TagType_BlackLevel`,                   // SHORT/LONG/RATIONAL # Used in Raw IFD of DNG files.
+	TagType_BlackLevelDeltaH:             `TagType_BlackLevelDeltaH`,             // SRATIONAL # Used in Raw IFD of DNG files.
+	TagType_BlackLevelDeltaV:             `TagType_BlackLevelDeltaV`,             // SRATIONAL # Used in Raw IFD of DNG files.
+	TagType_WhiteLevel:                   `TagType_WhiteLevel`,                   // SHORT/LONG # Used in Raw IFD of DNG files.
+	TagType_DefaultScale:                 `TagType_DefaultScale`,                 // RATIONAL, 2, # Used in Raw IFD of DNG files.
+	TagType_DefaultCropOrigin:            `TagType_DefaultCropOrigin`,            // SHORT/LONG/RATIONAL, 2, # Used in Raw IFD of DNG files.
+	TagType_DefaultCropSize:              `TagType_DefaultCropSize`,              // SHORT/LONG/RATIONAL, 2, # Used in Raw IFD of DNG files.
+	TagType_ColorMatrix1:                 `TagType_ColorMatrix1`,                 // SRATIONAL # Used in IFD 0 of DNG files.
+	TagType_ColorMatrix2:                 `TagType_ColorMatrix2`,                 // SRATIONAL # Used in IFD 0 of DNG files.
+	TagType_CameraCalibration1:           `TagType_CameraCalibration1`,           // SRATIONAL # Used in IFD 0 of DNG files.
+	TagType_CameraCalibration2:           `TagType_CameraCalibration2`,           // SRATIONAL # Used in IFD 0 of DNG files.
+	TagType_ReductionMatrix1:             `TagType_ReductionMatrix1`,             // SRATIONAL # Used in IFD 0 of DNG files.
+	TagType_ReductionMatrix2:             `TagType_ReductionMatrix2`,             // SRATIONAL # Used in IFD 0 of DNG files.
+	TagType_AnalogBalance:                `TagType_AnalogBalance`,                // RATIONAL # Used in IFD 0 of DNG files.
+	TagType_AsShotNeutral:                `TagType_AsShotNeutral`,                // SHORT/RATIONAL # Used in IFD 0 of DNG files.
+	TagType_AsShotWhiteXY:                `TagType_AsShotWhiteXY`,                // RATIONAL, 2, # Used in IFD 0 of DNG files.
+	TagType_BaselineExposure:             `TagType_BaselineExposure`,             // SRATIONAL, 1, # Used in IFD 0 of DNG files.
+	TagType_BaselineNoise:                `TagType_BaselineNoise`,                // RATIONAL, 1, # Used in IFD 0 of DNG files.
+	TagType_BaselineSharpness:            `TagType_BaselineSharpness`,            // RATIONAL, 1, # Used in IFD 0 of DNG files.
+	TagType_BayerGreenSplit:              `TagType_BayerGreenSplit`,              // LONG, 1, # Used in Raw IFD of DNG files.
+	TagType_LinearResponseLimit:          `TagType_LinearResponseLimit`,          // RATIONAL, 1, # Used in IFD 0 of DNG files.
+	TagType_CameraSerialNumber:           `TagType_CameraSerialNumber`,           // ASCII # Used in IFD 0 of DNG files.
+	TagType_LensInfo:                     `TagType_LensInfo`,                     // RATIONAL, 4, # Used in IFD 0 of DNG files.
+	TagType_ChromaBlurRadius:             `TagType_ChromaBlurRadius`,             // RATIONAL, 1, # Used in Raw IFD of DNG files.
+	TagType_AntiAliasStrength:            `TagType_AntiAliasStrength`,            // RATIONAL, 1, # Used in Raw IFD of DNG files.
 	TagType_DNGPrivateData:               `TagType_DNGPrivateData`,               // ingore # Used in IFD 0 of DNG files.
-	TagType_MakerNoteSafety:              `TagType_MakerNoteSafety`,              // ingore # Used in IFD 0 of DNG files.
-	TagType_CalibrationIlluminant1:       `TagType_CalibrationIlluminant1`,       // ingore # Used in IFD 0 of DNG files.
-	TagType_CalibrationIlluminant2:       `TagType_CalibrationIlluminant2`,       // ingore # Used in IFD 0 of DNG files.
-	TagType_BestQualityScale:             `TagType_BestQualityScale`,             // ingore # Used in Raw IFD of DNG files.
+	TagType_MakerNoteSafety:              `TagType_MakerNoteSafety`,              // SHORT, 1, # Used in IFD 0 of DNG files.
+	TagType_CalibrationIlluminant1:       `TagType_CalibrationIlluminant1`,       // SHORT, 1, # Used in IFD 0 of DNG files.
+	TagType_CalibrationIlluminant2:       `TagType_CalibrationIlluminant2`,       // SHORT, 1, # Used in IFD 0 of DNG files.
+	TagType_BestQualityScale:             `TagType_BestQualityScale`,             // RATIONAL, 1, # Used in Raw IFD of DNG files.
 	TagType_AliasLayerMetadata:           `TagType_AliasLayerMetadata`,           // ingore # Alias Sketchbook Pro layer usage description.
+	TagType_ActiveArea:                   `TagType_ActiveArea`,                   // SHORT/LONG, 4, # Used in Raw IFD of DNG files.
+	TagType_ForwardMatrix1:               `TagType_ForwardMatrix1`,               // SRATIONAL # Used in IFD 0 of DNG files.
+	TagType_ForwardMatrix2:               `TagType_ForwardMatrix2`,               // SRATIONAL # Used in IFD 0 of DNG files.
 }
 
 var _TagType_TypesTable = map[TagType][]DataType{
@@ -314,17 +319,64 @@ var _TagType_TypesTable = map[TagType][]DataType{
 	TagType_YCbCrSubSampling:            []DataType{DataType_Short},
 	TagType_YCbCrPositioning:            []DataType{DataType_Short},
 	TagType_ReferenceBlackWhite:         []DataType{DataType_Long},
+	TagType_XMP:                         []DataType{DataType_Byte, DataType_Undefined},
+	TagType_CFARepeatPatternDim:         []DataType{DataType_Short},
+	TagType_CFAPattern:                  []DataType{DataType_Byte},
 	TagType_Copyright:                   []DataType{DataType_ASCII},
 	TagType_ModelPixelScaleTag:          []DataType{DataType_Double},
+	TagType_IPTC:                        []DataType{DataType_Undefined, DataType_Long},
 	TagType_IrasBTransformationMatrix:   []DataType{DataType_Double},
 	TagType_ModelTiepointTag:            []DataType{DataType_Double},
 	TagType_ModelTransformationTag:      []DataType{DataType_Double},
+	TagType_Photoshop:                   []DataType{DataType_Byte, DataType_Undefined},
 	TagType_ExifIFD:                     []DataType{DataType_IFD},
+	TagType_ICCProfile:                  []DataType{DataType_Undefined},
 	TagType_GeoKeyDirectoryTag:          []DataType{DataType_Short},
 	TagType_GeoDoubleParamsTag:          []DataType{DataType_Double},
 	TagType_GeoAsciiParamsTag:           []DataType{DataType_ASCII},
 	TagType_GPSIFD:                      []DataType{DataType_IFD},
 	TagType_InteroperabilityIFD:         []DataType{DataType_IFD},
+	TagType_GDAL_METADATA:               []DataType{DataType_ASCII},
+	TagType_GDAL_NODATA:                 []DataType{DataType_ASCII},
+	TagType_DNGVersion:                  []DataType{DataType_Byte},
+	TagType_DNGBackwardVersion:          []DataType{DataType_Byte},
+	TagType_UniqueCameraModel:           []DataType{DataType_ASCII},
+	TagType_CFAPlaneColor:               []DataType{DataType_Byte},
+	TagType_CFALayout:                   []DataType{DataType_Short},
+	TagType_LinearizationTable:          []DataType{DataType_Short},
+	TagType_BlackLevelRepeatDim:         []DataType{DataType_Short},
+	TagType_BlackLevel:                  []DataType{DataType_Short, DataType_Long, DataType_Rational},
+	TagType_BlackLevelDeltaH:            []DataType{DataType_SRational},
+	TagType_BlackLevelDeltaV:            []DataType{DataType_SRational},
+	TagType_WhiteLevel:                  []DataType{DataType_Short, DataType_Long},
+	TagType_DefaultScale:                []DataType{DataType_Rational},
+	TagType_DefaultCropOrigin:           []DataType{DataType_Short, DataType_Long, DataType_Rational},
+	TagType_DefaultCropSize:             []DataType{DataType_Short, DataType_Long, DataType_Rational},
+	TagType_ColorMatrix1:                []DataType{DataType_SRational},
+	TagType_ColorMatrix2:                []DataType{DataType_SRational},
+	TagType_CameraCalibration1:          []DataType{DataType_SRational},
+	TagType_CameraCalibration2:          []DataType{DataType_SRational},
+	TagType_ReductionMatrix1:            []DataType{DataType_SRational},
+	TagType_ReductionMatrix2:            []DataType{DataType_SRational},
+	TagType_AnalogBalance:               []DataType{DataType_Rational},
+	TagType_AsShotNeutral:               []DataType{DataType_Short, DataType_Rational},
+	TagType_AsShotWhiteXY:               []DataType{DataType_Rational},
+	TagType_BaselineExposure:            []DataType{DataType_SRational},
+	TagType_BaselineNoise:               []DataType{DataType_Rational},
+	TagType_BaselineSharpness:           []DataType{DataType_Rational},
+	TagType_BayerGreenSplit:             []DataType{DataType_Long},
+	TagType_LinearResponseLimit:         []DataType{DataType_Rational},
+	TagType_CameraSerialNumber:          []DataType{DataType_ASCII},
+	TagType_LensInfo:                    []DataType{DataType_Rational},
+	TagType_ChromaBlurRadius:            []DataType{DataType_Rational},
+	TagType_AntiAliasStrength:           []DataType{DataType_Rational},
+	TagType_MakerNoteSafety:             []DataType{DataType_Short},
+	TagType_CalibrationIlluminant1:      []DataType{DataType_Short},
+	TagType_CalibrationIlluminant2:      []DataType{DataType_Short},
+	TagType_BestQualityScale:            []DataType{DataType_Rational},
+	TagType_ActiveArea:                  []DataType{DataType_Short, DataType_Long},
+	TagType_ForwardMatrix1:              []DataType{DataType_SRational},
+	TagType_ForwardMatrix2:              []DataType{DataType_SRational},
 }
 
 var _TagType_NumsTable = map[TagType][]int{
@@ -369,8 +421,30 @@ var _TagType_NumsTable = map[TagType][]int{
 	TagType_YCbCrCoefficients:           []int{3},
 	TagType_YCbCrSubSampling:            []int{2},
 	TagType_YCbCrPositioning:            []int{1},
+	TagType_CFARepeatPatternDim:         []int{2},
 	TagType_IrasBTransformationMatrix:   []int{17},
 	TagType_ModelTransformationTag:      []int{16},
+	TagType_DNGVersion:                  []int{4},
+	TagType_DNGBackwardVersion:          []int{4},
+	TagType_CFALayout:                   []int{1},
+	TagType_BlackLevelRepeatDim:         []int{2},
+	TagType_DefaultScale:                []int{2},
+	TagType_DefaultCropOrigin:           []int{2},
+	TagType_DefaultCropSize:             []int{2},
+	TagType_AsShotWhiteXY:               []int{2},
+	TagType_BaselineExposure:            []int{1},
+	TagType_BaselineNoise:               []int{1},
+	TagType_BaselineSharpness:           []int{1},
+	TagType_BayerGreenSplit:             []int{1},
+	TagType_LinearResponseLimit:         []int{1},
+	TagType_LensInfo:                    []int{4},
+	TagType_ChromaBlurRadius:            []int{1},
+	TagType_AntiAliasStrength:           []int{1},
+	TagType_MakerNoteSafety:             []int{1},
+	TagType_CalibrationIlluminant1:      []int{1},
+	TagType_CalibrationIlluminant2:      []int{1},
+	TagType_BestQualityScale:            []int{1},
+	TagType_ActiveArea:                  []int{4},
 }
 
 type TagGetter interface {
@@ -448,17 +522,64 @@ type TagGetter interface {
 	GetYCbCrSubSampling() (value []int64, ok bool)
 	GetYCbCrPositioning() (value int64, ok bool)
 	GetReferenceBlackWhite() (value []int64, ok bool)
+	GetXMP() (value []byte, ok bool)
+	GetCFARepeatPatternDim() (value []int64, ok bool)
+	GetCFAPattern() (value []int64, ok bool)
 	GetCopyright() (value string, ok bool)
 	GetModelPixelScaleTag() (value []float64, ok bool)
+	GetIPTC() (value []byte, ok bool)
 	GetIrasBTransformationMatrix() (value []float64, ok bool)
 	GetModelTiepointTag() (value []float64, ok bool)
 	GetModelTransformationTag() (value []float64, ok bool)
+	GetPhotoshop() (value []byte, ok bool)
 	GetExifIFD() (value []int64, ok bool)
+	GetICCProfile() (value []byte, ok bool)
 	GetGeoKeyDirectoryTag() (value []int64, ok bool)
 	GetGeoDoubleParamsTag() (value []float64, ok bool)
 	GetGeoAsciiParamsTag() (value string, ok bool)
 	GetGPSIFD() (value []int64, ok bool)
 	GetInteroperabilityIFD() (value []int64, ok bool)
+	GetGDAL_METADATA() (value string, ok bool)
+	GetGDAL_NODATA() (value string, ok bool)
+	GetDNGVersion() (value []int64, ok bool)
+	GetDNGBackwardVersion() (value []int64, ok bool)
+	GetUniqueCameraModel() (value string, ok bool)
+	GetCFAPlaneColor() (value []int64, ok bool)
+	GetCFALayout() (value int64, ok bool)
+	GetLinearizationTable() (value []int64, ok bool)
+	GetBlackLevelRepeatDim() (value []int64, ok bool)
+	GetBlackLevel() (value []float64, ok bool)
+	GetBlackLevelDeltaH() (value [][2]int64, ok bool)
+	GetBlackLevelDeltaV() (value [][2]int64, ok bool)
+	GetWhiteLevel() (value []int64, ok bool)
+	GetDefaultScale() (value [][2]int64, ok bool)
+	GetDefaultCropOrigin() (value []float64, ok bool)
+	GetDefaultCropSize() (value []float64, ok bool)
+	GetColorMatrix1() (value [][2]int64, ok bool)
+	GetColorMatrix2() (value [][2]int64, ok bool)
+	GetCameraCalibration1() (value [][2]int64, ok bool)
+	GetCameraCalibration2() (value [][2]int64, ok bool)
+	GetReductionMatrix1() (value [][2]int64, ok bool)
+	GetReductionMatrix2() (value [][2]int64, ok bool)
+	GetAnalogBalance() (value [][2]int64, ok bool)
+	GetAsShotNeutral() (value []float64, ok bool)
+	GetAsShotWhiteXY() (value [][2]int64, ok bool)
+	GetBaselineExposure() (value [2]int64, ok bool)
+	GetBaselineNoise() (value [2]int64, ok bool)
+	GetBaselineSharpness() (value [2]int64, ok bool)
+	GetBayerGreenSplit() (value int64, ok bool)
+	GetLinearResponseLimit() (value [2]int64, ok bool)
+	GetCameraSerialNumber() (value string, ok bool)
+	GetLensInfo() (value [][2]int64, ok bool)
+	GetChromaBlurRadius() (value [2]int64, ok bool)
+	GetAntiAliasStrength() (value [2]int64, ok bool)
+	GetMakerNoteSafety() (value int64, ok bool)
+	GetCalibrationIlluminant1() (value int64, ok bool)
+	GetCalibrationIlluminant2() (value int64, ok bool)
+	GetBestQualityScale() (value [2]int64, ok bool)
+	GetActiveArea() (value []int64, ok bool)
+	GetForwardMatrix1() (value [][2]int64, ok bool)
+	GetForwardMatrix2() (value [][2]int64, ok bool)
 
 	GetUnknown(tag TagType) (value []byte, ok bool)
 
@@ -540,17 +661,64 @@ type TagSetter interface {
 	SetYCbCrSubSampling(value []int64) (ok bool)
 	SetYCbCrPositioning(value int64) (ok bool)
 	SetReferenceBlackWhite(value []int64) (ok bool)
+	SetXMP(value []byte) (ok bool)
+	SetCFARepeatPatternDim(value []int64) (ok bool)
+	SetCFAPattern(value []int64) (ok bool)
 	SetCopyright(value string) (ok bool)
 	SetModelPixelScaleTag(value []float64) (ok bool)
+	SetIPTC(value []byte) (ok bool)
 	SetIrasBTransformationMatrix(value []float64) (ok bool)
 	SetModelTiepointTag(value []float64) (ok bool)
 	SetModelTransformationTag(value []float64) (ok bool)
+	SetPhotoshop(value []byte) (ok bool)
 	SetExifIFD(value []int64) (ok bool)
+	SetICCProfile(value []byte) (ok bool)
 	SetGeoKeyDirectoryTag(value []int64) (ok bool)
 	SetGeoDoubleParamsTag(value []float64) (ok bool)
 	SetGeoAsciiParamsTag(value string) (ok bool)
 	SetGPSIFD(value []int64) (ok bool)
 	SetInteroperabilityIFD(value []int64) (ok bool)
+	SetGDAL_METADATA(value string) (ok bool)
+	SetGDAL_NODATA(value string) (ok bool)
+	SetDNGVersion(value []int64) (ok bool)
+	SetDNGBackwardVersion(value []int64) (ok bool)
+	SetUniqueCameraModel(value string) (ok bool)
+	SetCFAPlaneColor(value []int64) (ok bool)
+	SetCFALayout(value int64) (ok bool)
+	SetLinearizationTable(value []int64) (ok bool)
+	SetBlackLevelRepeatDim(value []int64) (ok bool)
+	SetBlackLevel(value []float64) (ok bool)
+	SetBlackLevelDeltaH(value [][2]int64) (ok bool)
+	SetBlackLevelDeltaV(value [][2]int64) (ok bool)
+	SetWhiteLevel(value []int64) (ok bool)
+	SetDefaultScale(value [][2]int64) (ok bool)
+	SetDefaultCropOrigin(value []float64) (ok bool)
+	SetDefaultCropSize(value []float64) (ok bool)
+	SetColorMatrix1(value [][2]int64) (ok bool)
+	SetColorMatrix2(value [][2]int64) (ok bool)
+	SetCameraCalibration1(value [][2]int64) (ok bool)
+	SetCameraCalibration2(value [][2]int64) (ok bool)
+	SetReductionMatrix1(value [][2]int64) (ok bool)
+	SetReductionMatrix2(value [][2]int64) (ok bool)
+	SetAnalogBalance(value [][2]int64) (ok bool)
+	SetAsShotNeutral(value []float64) (ok bool)
+	SetAsShotWhiteXY(value [][2]int64) (ok bool)
+	SetBaselineExposure(value [2]int64) (ok bool)
+	SetBaselineNoise(value [2]int64) (ok bool)
+	SetBaselineSharpness(value [2]int64) (ok bool)
+	SetBayerGreenSplit(value int64) (ok bool)
+	SetLinearResponseLimit(value [2]int64) (ok bool)
+	SetCameraSerialNumber(value string) (ok bool)
+	SetLensInfo(value [][2]int64) (ok bool)
+	SetChromaBlurRadius(value [2]int64) (ok bool)
+	SetAntiAliasStrength(value [2]int64) (ok bool)
+	SetMakerNoteSafety(value int64) (ok bool)
+	SetCalibrationIlluminant1(value int64) (ok bool)
+	SetCalibrationIlluminant2(value int64) (ok bool)
+	SetBestQualityScale(value [2]int64) (ok bool)
+	SetActiveArea(value []int64) (ok bool)
+	SetForwardMatrix1(value [][2]int64) (ok bool)
+	SetForwardMatrix2(value [][2]int64) (ok bool)
 
 	SetUnknown(tag TagType, value interface{}) (ok bool)
 
@@ -625,6 +793,8 @@ var _TagValue_PhotometricTypeTable = map[TagValue_PhotometricType]string{
 	TagValue_PhotometricType_CMYK:        `TagValue_PhotometricType_CMYK`,        //
 	TagValue_PhotometricType_YCbCr:       `TagValue_PhotometricType_YCbCr`,       //
 	TagValue_PhotometricType_CIELab:      `TagValue_PhotometricType_CIELab`,      //
+	TagValue_PhotometricType_CFA:         `TagValue_PhotometricType_CFA`,         // # Color filter array, used by DNG.
+	TagValue_PhotometricType_LinearRaw:   `TagValue_PhotometricType_LinearRaw`,   // # Used by DNG.
 }
 
 func (p TagValue_PhotometricType) String() string {
@@ -674,62 +844,76 @@ func (p TagValue_SampleFormatType) String() string {
 }
 
 var _ExifIFD_TagTypeTable = map[ExifIFD_TagType]string{
-	ExifIFD_TagType_ExposureTime:             `ExifIFD_TagType_ExposureTime`,             // ingore # Exposure time, given in seconds.
-	ExifIFD_TagType_FNumber:                  `ExifIFD_TagType_FNumber`,                  // ingore # The F number.
-	ExifIFD_TagType_ExposureProgram:          `ExifIFD_TagType_ExposureProgram`,          // ingore # The class of the program used by the camera to set exposure when the picture is taken.
-	ExifIFD_TagType_SpectralSensitivity:      `ExifIFD_TagType_SpectralSensitivity`,      // ingore # Indicates the spectral sensitivity of each channel of the camera used.
-	ExifIFD_TagType_ISOSpeedRatings:          `ExifIFD_TagType_ISOSpeedRatings`,          // ingore # Indicates the ISO Speed and ISO Latitude of the camera or input device as specified in ISO 12232.
-	ExifIFD_TagType_OECF:                     `ExifIFD_TagType_OECF`,                     // ingore # Indicates the Opto-Electric Conversion Function (OECF) specified in ISO 14524.
-	ExifIFD_TagType_ExifVersion:              `ExifIFD_TagType_ExifVersion`,              // ingore # The version of the supported Exif standard.
-	ExifIFD_TagType_DateTimeOriginal:         `ExifIFD_TagType_DateTimeOriginal`,         // ingore # The date and time when the original image data was generated.
-	ExifIFD_TagType_DateTimeDigitized:        `ExifIFD_TagType_DateTimeDigitized`,        // ingore # The date and time when the image was stored as digital data.
-	ExifIFD_TagType_ComponentsConfiguration:  `ExifIFD_TagType_ComponentsConfiguration`,  // ingore # Specific to compressed data; specifies the channels and complements PhotometricInterpretation
-	ExifIFD_TagType_CompressedBitsPerPixel:   `ExifIFD_TagType_CompressedBitsPerPixel`,   // ingore # Specific to compressed data; states the compressed bits per pixel.
-	ExifIFD_TagType_ShutterSpeedValue:        `ExifIFD_TagType_ShutterSpeedValue`,        // ingore # Shutter speed.
-	ExifIFD_TagType_ApertureValue:            `ExifIFD_TagType_ApertureValue`,            // ingore # The lens aperture.
-	ExifIFD_TagType_BrightnessValue:          `ExifIFD_TagType_BrightnessValue`,          // ingore # The value of brightness.
-	ExifIFD_TagType_ExposureBiasValue:        `ExifIFD_TagType_ExposureBiasValue`,        // ingore # The exposure bias.
-	ExifIFD_TagType_MaxApertureValue:         `ExifIFD_TagType_MaxApertureValue`,         // ingore # The smallest F number of the lens.
-	ExifIFD_TagType_SubjectDistance:          `ExifIFD_TagType_SubjectDistance`,          // ingore # The distance to the subject, given in meters.
-	ExifIFD_TagType_MeteringMode:             `ExifIFD_TagType_MeteringMode`,             // ingore # The metering mode.
-	ExifIFD_TagType_LightSource:              `ExifIFD_TagType_LightSource`,              // ingore # The kind of light source.
-	ExifIFD_TagType_Flash:                    `ExifIFD_TagType_Flash`,                    // ingore # Indicates the status of flash when the image was shot.
-	ExifIFD_TagType_FocalLength:              `ExifIFD_TagType_FocalLength`,              // ingore # The actual focal length of the lens, in mm.
-	ExifIFD_TagType_SubjectArea:              `ExifIFD_TagType_SubjectArea`,              // ingore # Indicates the location and area of the main subject in the overall scene.
-	ExifIFD_TagType_MakerNote:                `ExifIFD_TagType_MakerNote`,                // ingore # Manufacturer specific information.
-	ExifIFD_TagType_UserComment:              `ExifIFD_TagType_UserComment`,              // ingore # Keywords or comments on the image; complements ImageDescription.
-	ExifIFD_TagType_SubsecTime:               `ExifIFD_TagType_SubsecTime`,               // ingore # A tag used to record fractions of seconds for the DateTime tag.
-	ExifIFD_TagType_SubsecTimeOriginal:       `ExifIFD_TagType_SubsecTimeOriginal`,       // ingore # A tag used to record fractions of seconds for the DateTimeOriginal tag.
-	ExifIFD_TagType_SubsecTimeDigitized:      `ExifIFD_TagType_SubsecTimeDigitized`,      // ingore # A tag used to record fractions of seconds for the DateTimeDigitized tag.
-	ExifIFD_TagType_FlashpixVersion:          `ExifIFD_TagType_FlashpixVersion`,          // ingore # The Flashpix format version supported by a FPXR file.
-	ExifIFD_TagType_ColorSpace:               `ExifIFD_TagType_ColorSpace`,               // ingore # The color space information tag is always recorded as the color space specifier.
-	ExifIFD_TagType_PixelXDimension:          `ExifIFD_TagType_PixelXDimension`,          // ingore # Specific to compressed data; the valid width of the meaningful image.
-	ExifIFD_TagType_PixelYDimension:          `ExifIFD_TagType_PixelYDimension`,          // ingore # Specific to compressed data; the valid height of the meaningful image.
-	ExifIFD_TagType_RelatedSoundFile:         `ExifIFD_TagType_RelatedSoundFile`,         // ingore # Used to record the name of an audio file related to the image data.
-	ExifIFD_TagType_FlashEnergy:              `ExifIFD_TagType_FlashEnergy`,              // ingore # Indicates the strobe energy at the time the image is captured, as measured in Beam Candle Power Seconds
-	ExifIFD_TagType_SpatialFrequencyResponse: `ExifIFD_TagType_SpatialFrequencyResponse`, // ingore # Records the camera or input device spatial frequency table and SFR values in the direction of image width, image height, and diagonal direction, as specified in ISO 12233.
-	ExifIFD_TagType_FocalPlaneXResolution:    `ExifIFD_TagType_FocalPlaneXResolution`,    // ingore # Indicates the number of pixels in the image width (X) direction per FocalPlaneResolutionUnit on the camera focal plane.
-	ExifIFD_TagType_FocalPlaneYResolution:    `ExifIFD_TagType_FocalPlaneYResolution`,    // ingore # Indicates the number of pixels in the image height (Y) direction per FocalPlaneResolutionUnit on the camera focal plane.
-	ExifIFD_TagType_FocalPlaneResolutionUnit: `ExifIFD_TagType_FocalPlaneResolutionUnit`, // ingore # Indicates the unit for measuring FocalPlaneXResolution and FocalPlaneYResolution.
-	ExifIFD_TagType_SubjectLocation:          `ExifIFD_TagType_SubjectLocation`,          // ingore # Indicates the location of the main subject in the scene.
-	ExifIFD_TagType_ExposureIndex:            `ExifIFD_TagType_ExposureIndex`,            // ingore # Indicates the exposure index selected on the camera or input device at the time the image is captured.
-	ExifIFD_TagType_SensingMethod:            `ExifIFD_TagType_SensingMethod`,            // ingore # Indicates the image sensor type on the camera or input device.
-	ExifIFD_TagType_FileSource:               `ExifIFD_TagType_FileSource`,               // ingore # Indicates the image source.
-	ExifIFD_TagType_SceneType:                `ExifIFD_TagType_SceneType`,                // ingore # Indicates the type of scene.
-	ExifIFD_TagType_CFAPattern:               `ExifIFD_TagType_CFAPattern`,               // ingore # Indicates the color filter array (CFA) geometric pattern of the image sensor when a one-chip color area sensor is used.
-	ExifIFD_TagType_CustomRendered:           `ExifIFD_TagType_CustomRendered`,           // ingore # Indicates the use of special processing on image data, such as rendering geared to output.
-	ExifIFD_TagType_ExposureMode:             `ExifIFD_TagType_ExposureMode`,             // ingore # Indicates the exposure mode set when the image was shot.
-	ExifIFD_TagType_WhiteBalance:             `ExifIFD_TagType_WhiteBalance`,             // ingore # Indicates the white balance mode set when the image was shot.
-	ExifIFD_TagType_DigitalZoomRatio:         `ExifIFD_TagType_DigitalZoomRatio`,         // ingore # Indicates the digital zoom ratio when the image was shot.
-	ExifIFD_TagType_FocalLengthIn35mmFilm:    `ExifIFD_TagType_FocalLengthIn35mmFilm`,    // ingore # Indicates the equivalent focal length assuming a 35mm film camera, in mm.
-	ExifIFD_TagType_SceneCaptureType:         `ExifIFD_TagType_SceneCaptureType`,         // ingore # Indicates the type of scene that was shot.
-	ExifIFD_TagType_GainControl:              `ExifIFD_TagType_GainControl`,              // ingore # Indicates the degree of overall image gain adjustment.
-	ExifIFD_TagType_Contrast:                 `ExifIFD_TagType_Contrast`,                 // ingore # Indicates the direction of contrast processing applied by the camera when the image was shot.
-	ExifIFD_TagType_Saturation:               `ExifIFD_TagType_Saturation`,               // ingore # Indicates the direction of saturation processing applied by the camera when the image was shot.
-	ExifIFD_TagType_Sharpness:                `ExifIFD_TagType_Sharpness`,                // ingore # Indicates the direction of sharpness processing applied by the camera when the image was shot.
-	ExifIFD_TagType_DeviceSettingDescription: `ExifIFD_TagType_DeviceSettingDescription`, // ingore # This tag indicates information on the picture-taking conditions of a particular camera model.
-	ExifIFD_TagType_SubjectDistanceRange:     `ExifIFD_TagType_SubjectDistanceRange`,     // ingore # Indicates the distance to the subject.
-	ExifIFD_TagType_ImageUniqueID:            `ExifIFD_TagType_ImageUniqueID`,            // ingore # Indicates an identifier assigned uniquely to each image.
+	ExifIFD_TagType_ExposureTime:              `ExifIFD_TagType_ExposureTime`,              // ingore # Exposure time, given in seconds.
+	ExifIFD_TagType_FNumber:                   `ExifIFD_TagType_FNumber`,                   // ingore # The F number.
+	ExifIFD_TagType_ExposureProgram:           `ExifIFD_TagType_ExposureProgram`,           // ingore # The class of the program used by the camera to set exposure when the picture is taken.
+	ExifIFD_TagType_SpectralSensitivity:       `ExifIFD_TagType_SpectralSensitivity`,       // ingore # Indicates the spectral sensitivity of each channel of the camera used.
+	ExifIFD_TagType_ISOSpeedRatings:           `ExifIFD_TagType_ISOSpeedRatings`,           // ingore # Indicates the ISO Speed and ISO Latitude of the camera or input device as specified in ISO 12232.
+	ExifIFD_TagType_OECF:                      `ExifIFD_TagType_OECF`,                      // ingore # Indicates the Opto-Electric Conversion Function (OECF) specified in ISO 14524.
+	ExifIFD_TagType_SensitivityType:           `ExifIFD_TagType_SensitivityType`,           // ingore # Indicates which of the sensitivity parameters is recorded in ISOSpeedRatings.
+	ExifIFD_TagType_StandardOutputSensitivity: `ExifIFD_TagType_StandardOutputSensitivity`, // ingore # The standard output sensitivity (SOS) value of ISO 12232.
+	ExifIFD_TagType_RecommendedExposureIndex:  `ExifIFD_TagType_RecommendedExposureIndex`,  // ingore # The recommended exposure index (REI) value of ISO 12232.
+	ExifIFD_TagType_ISOSpeed:                  `ExifIFD_TagType_ISOSpeed`,                  // ingore # The ISO speed value of ISO 12232.
+	ExifIFD_TagType_ISOSpeedLatitudeyyy:       `ExifIFD_TagType_ISOSpeedLatitudeyyy`,       // ingore # The ISO speed latitude yyy value of ISO 12232.
+	ExifIFD_TagType_ISOSpeedLatitudezzz:       `ExifIFD_TagType_ISOSpeedLatitudezzz`,       // ingore # The ISO speed latitude zzz value of ISO 12232.
+	ExifIFD_TagType_ExifVersion:               `ExifIFD_TagType_ExifVersion`,               // ingore # The version of the supported Exif standard.
+	ExifIFD_TagType_DateTimeOriginal:          `ExifIFD_TagType_DateTimeOriginal`,          // ingore # The date and time when the original image data was generated.
+	ExifIFD_TagType_DateTimeDigitized:         `ExifIFD_TagType_DateTimeDigitized`,         // ingore # The date and time when the image was stored as digital data.
+	ExifIFD_TagType_ComponentsConfiguration:   `ExifIFD_TagType_ComponentsConfiguration`,   // ingore # Specific to compressed data; specifies the channels and complements PhotometricInterpretation
+	ExifIFD_TagType_CompressedBitsPerPixel:    `ExifIFD_TagType_CompressedBitsPerPixel`,    // ingore # Specific to compressed data; states the compressed bits per pixel.
+	ExifIFD_TagType_ShutterSpeedValue:         `ExifIFD_TagType_ShutterSpeedValue`,         // ingore # Shutter speed.
+	ExifIFD_TagType_ApertureValue:             `ExifIFD_TagType_ApertureValue`,             // ingore # The lens aperture.
+	ExifIFD_TagType_BrightnessValue:           `ExifIFD_TagType_BrightnessValue`,           // ingore # The value of brightness.
+	ExifIFD_TagType_ExposureBiasValue:         `ExifIFD_TagType_ExposureBiasValue`,         // ingore # The exposure bias.
+	ExifIFD_TagType_MaxApertureValue:          `ExifIFD_TagType_MaxApertureValue`,          // ingore # The smallest F number of the lens.
+	ExifIFD_TagType_SubjectDistance:           `ExifIFD_TagType_SubjectDistance`,           // ingore # The distance to the subject, given in meters.
+	ExifIFD_TagType_MeteringMode:              `ExifIFD_TagType_MeteringMode`,              // ingore # The metering mode.
+	ExifIFD_TagType_LightSource:               `ExifIFD_TagType_LightSource`,               // ingore # The kind of light source.
+	ExifIFD_TagType_Flash:                     `ExifIFD_TagType_Flash`,                     // ingore # Indicates the status of flash when the image was shot.
+	ExifIFD_TagType_FocalLength:               `ExifIFD_TagType_FocalLength`,               // ingore # The actual focal length of the lens, in mm.
+	ExifIFD_TagType_SubjectArea:               `ExifIFD_TagType_SubjectArea`,               // ingore # Indicates the location and area of the main subject in the overall scene.
+	ExifIFD_TagType_MakerNote:                 `ExifIFD_TagType_MakerNote`,                 // ingore # Manufacturer specific information.
+	ExifIFD_TagType_UserComment:               `ExifIFD_TagType_UserComment`,               // ingore # Keywords or comments on the image; complements ImageDescription.
+	ExifIFD_TagType_SubsecTime:                `ExifIFD_TagType_SubsecTime`,                // ingore # A tag used to record fractions of seconds for the DateTime tag.
+	ExifIFD_TagType_SubsecTimeOriginal:        `ExifIFD_TagType_SubsecTimeOriginal`,        // ingore # A tag used to record fractions of seconds for the DateTimeOriginal tag.
+	ExifIFD_TagType_SubsecTimeDigitized:       `ExifIFD_TagType_SubsecTimeDigitized`,       // ingore # A tag used to record fractions of seconds for the DateTimeDigitized tag.
+	ExifIFD_TagType_FlashpixVersion:           `ExifIFD_TagType_FlashpixVersion`,           // ingore # The Flashpix format version supported by a FPXR file.
+	ExifIFD_TagType_ColorSpace:                `ExifIFD_TagType_ColorSpace`,                // ingore # The color space information tag is always recorded as the color space specifier.
+	ExifIFD_TagType_PixelXDimension:           `ExifIFD_TagType_PixelXDimension`,           // ingore # Specific to compressed data; the valid width of the meaningful image.
+	ExifIFD_TagType_PixelYDimension:           `ExifIFD_TagType_PixelYDimension`,           // ingore # Specific to compressed data; the valid height of the meaningful image.
+	ExifIFD_TagType_RelatedSoundFile:          `ExifIFD_TagType_RelatedSoundFile`,          // ingore # Used to record the name of an audio file related to the image data.
+	ExifIFD_TagType_InteroperabilityIFD:       `ExifIFD_TagType_InteroperabilityIFD`,       // ingore # A pointer to the Exif-related Interoperability IFD.
+	ExifIFD_TagType_FlashEnergy:               `ExifIFD_TagType_FlashEnergy`,               // ingore # Indicates the strobe energy at the time the image is captured, as measured in Beam Candle Power Seconds
+	ExifIFD_TagType_SpatialFrequencyResponse:  `ExifIFD_TagType_SpatialFrequencyResponse`,  // ingore # Records the camera or input device spatial frequency table and SFR values in the direction of image width, image height, and diagonal direction, as specified in ISO 12233.
+	ExifIFD_TagType_FocalPlaneXResolution:     `ExifIFD_TagType_FocalPlaneXResolution`,     // ingore # Indicates the number of pixels in the image width (X) direction per FocalPlaneResolutionUnit on the camera focal plane.
+	ExifIFD_TagType_FocalPlaneYResolution:     `ExifIFD_TagType_FocalPlaneYResolution`,     // ingore # Indicates the number of pixels in the image height (Y) direction per FocalPlaneResolutionUnit on the camera focal plane.
+	ExifIFD_TagType_FocalPlaneResolutionUnit:  `ExifIFD_TagType_FocalPlaneResolutionUnit`,  // ingore # Indicates the unit for measuring FocalPlaneXResolution and FocalPlaneYResolution.
+	ExifIFD_TagType_SubjectLocation:           `ExifIFD_TagType_SubjectLocation`,           // ingore # Indicates the location of the main subject in the scene.
+	ExifIFD_TagType_ExposureIndex:             `ExifIFD_TagType_ExposureIndex`,             // ingore # Indicates the exposure index selected on the camera or input device at the time the image is captured.
+	ExifIFD_TagType_SensingMethod:             `ExifIFD_TagType_SensingMethod`,             // ingore # Indicates the image sensor type on the camera or input device.
+	ExifIFD_TagType_FileSource:                `ExifIFD_TagType_FileSource`,                // ingore # Indicates the image source.
+	ExifIFD_TagType_SceneType:                 `ExifIFD_TagType_SceneType`,                 // ingore # Indicates the type of scene.
+	ExifIFD_TagType_CFAPattern:                `ExifIFD_TagType_CFAPattern`,                // ingore # Indicates the color filter array (CFA) geometric pattern of the image sensor when a one-chip color area sensor is used.
+	ExifIFD_TagType_CustomRendered:            `ExifIFD_TagType_CustomRendered`,            // ingore # Indicates the use of special processing on image data, such as rendering geared to output.
+	ExifIFD_TagType_ExposureMode:              `ExifIFD_TagType_ExposureMode`,              // ingore # Indicates the exposure mode set when the image was shot.
+	ExifIFD_TagType_WhiteBalance:              `ExifIFD_TagType_WhiteBalance`,              // ingore # Indicates the white balance mode set when the image was shot.
+	ExifIFD_TagType_DigitalZoomRatio:          `ExifIFD_TagType_DigitalZoomRatio`,          // ingore # Indicates the digital zoom ratio when the image was shot.
+	ExifIFD_TagType_FocalLengthIn35mmFilm:     `ExifIFD_TagType_FocalLengthIn35mmFilm`,     // ingore # Indicates the equivalent focal length assuming a 35mm film camera, in mm.
+	ExifIFD_TagType_SceneCaptureType:          `ExifIFD_TagType_SceneCaptureType`,          // ingore # Indicates the type of scene that was shot.
+	ExifIFD_TagType_GainControl:               `ExifIFD_TagType_GainControl`,               // ingore # Indicates the degree of overall image gain adjustment.
+	ExifIFD_TagType_Contrast:                  `ExifIFD_TagType_Contrast`,                  // ingore # Indicates the direction of contrast processing applied by the camera when the image was shot.
+	ExifIFD_TagType_Saturation:                `ExifIFD_TagType_Saturation`,                // ingore # Indicates the direction of saturation processing applied by the camera when the image was shot.
+	ExifIFD_TagType_Sharpness:                 `ExifIFD_TagType_Sharpness`,                 // ingore # Indicates the direction of sharpness processing applied by the camera when the image was shot.
+	ExifIFD_TagType_DeviceSettingDescription:  `ExifIFD_TagType_DeviceSettingDescription`,  // ingore # This tag indicates information on the picture-taking conditions of a particular camera model.
+	ExifIFD_TagType_SubjectDistanceRange:      `ExifIFD_TagType_SubjectDistanceRange`,      // ingore # Indicates the distance to the subject.
+	ExifIFD_TagType_ImageUniqueID:             `ExifIFD_TagType_ImageUniqueID`,             // ingore # Indicates an identifier assigned uniquely to each image.
+	ExifIFD_TagType_CameraOwnerName:           `ExifIFD_TagType_CameraOwnerName`,           // ingore # The name of the camera owner.
+	ExifIFD_TagType_BodySerialNumber:          `ExifIFD_TagType_BodySerialNumber`,          // ingore # The serial number of the body of the camera.
+	ExifIFD_TagType_LensSpecification:         `ExifIFD_TagType_LensSpecification`,         // ingore # The minimum and maximum focal lengths in mm and F numbers of the lens.
+	ExifIFD_TagType_LensMake:                  `ExifIFD_TagType_LensMake`,                  // ingore # The lens manufacturer.
+	ExifIFD_TagType_LensModel:                 `ExifIFD_TagType_LensModel`,                 // ingore # The model name or model number of the lens.
+	ExifIFD_TagType_LensSerialNumber:          `ExifIFD_TagType_LensSerialNumber`,          // ingore # The serial number of the interchangeable lens.
+	ExifIFD_TagType_Gamma:                     `ExifIFD_TagType_Gamma`,                     // ingore # Indicates the value of coefficient gamma.
 }
 
 func (p ExifIFD_TagType) String() string {
@@ -740,37 +924,38 @@ func (p ExifIFD_TagType) String() string {
 }
 
 var _GPSIFD_TagTypeTable = map[GPSIFD_TagType]string{
-	GPSIFD_TagType_GPSVersionID:        `GPSIFD_TagType_GPSVersionID`,        // ingore # Indicates the version of GPSInfoIFD.
-	GPSIFD_TagType_GPSLatitudeRef:      `GPSIFD_TagType_GPSLatitudeRef`,      // ingore # Indicates whether the latitude is north or south latitude.
-	GPSIFD_TagType_GPSLatitude:         `GPSIFD_TagType_GPSLatitude`,         // ingore # Indicates the latitude.
-	GPSIFD_TagType_GPSLongitudeRef:     `GPSIFD_TagType_GPSLongitudeRef`,     // ingore # Indicates whether the longitude is east or west longitude.
-	GPSIFD_TagType_GPSLongitude:        `GPSIFD_TagType_GPSLongitude`,        // ingore # Indicates the longitude.
-	GPSIFD_TagType_GPSAltitudeRef:      `GPSIFD_TagType_GPSAltitudeRef`,      // ingore # Indicates the altitude used as the reference altitude.
-	GPSIFD_TagType_GPSAltitude:         `GPSIFD_TagType_GPSAltitude`,         // ingore # Indicates the altitude based on the reference in GPSAltitudeRef.
-	GPSIFD_TagType_GPSTimeStamp:        `GPSIFD_TagType_GPSTimeStamp`,        // ingore # Indicates the time as UTC (Coordinated Universal Time).
-	GPSIFD_TagType_GPSSatellites:       `GPSIFD_TagType_GPSSatellites`,       // ingore # Indicates the GPS satellites used for measurements.
-	GPSIFD_TagType_GPSStatus:           `GPSIFD_TagType_GPSStatus`,           // ingore # Indicates the status of the GPS receiver when the image is recorded.
-	GPSIFD_TagType_GPSMeasureMode:      `GPSIFD_TagType_GPSMeasureMode`,      // ingore # Indicates the GPS measurement mode.
-	GPSIFD_TagType_GPSDOP:              `GPSIFD_TagType_GPSDOP`,              // ingore # Indicates the GPS DOP (data degree of precision).
-	GPSIFD_TagType_GPSSpeedRef:         `GPSIFD_TagType_GPSSpeedRef`,         // ingore # Indicates the unit used to express the GPS receiver speed of movement.
-	GPSIFD_TagType_GPSSpeed:            `GPSIFD_TagType_GPSSpeed`,            // ingore # Indicates the speed of GPS receiver movement.
-	GPSIFD_TagType_GPSTrackRef:         `GPSIFD_TagType_GPSTrackRef`,         // ingore # Indicates the reference for giving the direction of GPS receiver movement.
-	GPSIFD_TagType_GPSTrack:            `GPSIFD_TagType_GPSTrack`,            // ingore # Indicates the direction of GPS receiver movement.
-	GPSIFD_TagType_GPSImgDirectionRef:  `GPSIFD_TagType_GPSImgDirectionRef`,  // ingore # Indicates the reference for giving the direction of the image when it is captured.
-	GPSIFD_TagType_GPSImgDirection:     `GPSIFD_TagType_GPSImgDirection`,     // ingore # Indicates the direction of the image when it was captured.
-	GPSIFD_TagType_GPSMapDatum:         `GPSIFD_TagType_GPSMapDatum`,         // ingore # Indicates the geodetic survey data used by the GPS receiver.
-	GPSIFD_TagType_GPSDestLatitudeRef:  `GPSIFD_TagType_GPSDestLatitudeRef`,  // ingore # Indicates whether the latitude of the destination point is north or south latitude.
-	GPSIFD_TagType_GPSDestLatitude:     `GPSIFD_TagType_GPSDestLatitude`,     // ingore # Indicates the latitude of the destination point.
-	GPSIFD_TagType_GPSDestLongitudeRef: `GPSIFD_TagType_GPSDestLongitudeRef`, // ingore # Indicates whether the longitude of the destination point is east or west longitude.
-	GPSIFD_TagType_GPSDestLongitude:    `GPSIFD_TagType_GPSDestLongitude`,    // ingore # Indicates the longitude of the destination point.
-	GPSIFD_TagType_GPSDestBearingRef:   `GPSIFD_TagType_GPSDestBearingRef`,   // ingore # Indicates the reference used for giving the bearing to the destination point.
-	GPSIFD_TagType_GPSDestBearing:      `GPSIFD_TagType_GPSDestBearing`,      // ingore # Indicates the bearing to the destination point.
-	GPSIFD_TagType_GPSDestDistanceRef:  `GPSIFD_TagType_GPSDestDistanceRef`,  // ingore # Indicates the unit used to express the distance to the destination point.
-	GPSIFD_TagType_GPSDestDistance:     `GPSIFD_TagType_GPSDestDistance`,     // ingore # Indicates the distance to the destination point.
-	GPSIFD_TagType_GPSProcessingMethod: `GPSIFD_TagType_GPSProcessingMethod`, // ingore # A character string recording the name of the method used for location finding.
-	GPSIFD_TagType_GPSAreaInformation:  `GPSIFD_TagType_GPSAreaInformation`,  // ingore # A character string recording the name of the GPS area.
-	GPSIFD_TagType_GPSDateStamp:        `GPSIFD_TagType_GPSDateStamp`,        // ingore # A character string recording date and time information relative to UTC (Coordinated Universal Time).
-	GPSIFD_TagType_GPSDifferential:     `GPSIFD_TagType_GPSDifferential`,     // ingore # Indicates whether differential correction is applied to the GPS receiver.
+	GPSIFD_TagType_GPSVersionID:         `GPSIFD_TagType_GPSVersionID`,         // ingore # Indicates the version of GPSInfoIFD.
+	GPSIFD_TagType_GPSLatitudeRef:       `GPSIFD_TagType_GPSLatitudeRef`,       // ingore # Indicates whether the latitude is north or south latitude.
+	GPSIFD_TagType_GPSLatitude:          `GPSIFD_TagType_GPSLatitude`,          // ingore # Indicates the latitude.
+	GPSIFD_TagType_GPSLongitudeRef:      `GPSIFD_TagType_GPSLongitudeRef`,      // ingore # Indicates whether the longitude is east or west longitude.
+	GPSIFD_TagType_GPSLongitude:         `GPSIFD_TagType_GPSLongitude`,         // ingore # Indicates the longitude.
+	GPSIFD_TagType_GPSAltitudeRef:       `GPSIFD_TagType_GPSAltitudeRef`,       // ingore # Indicates the altitude used as the reference altitude.
+	GPSIFD_TagType_GPSAltitude:          `GPSIFD_TagType_GPSAltitude`,          // ingore # Indicates the altitude based on the reference in GPSAltitudeRef.
+	GPSIFD_TagType_GPSTimeStamp:         `GPSIFD_TagType_GPSTimeStamp`,         // ingore # Indicates the time as UTC (Coordinated Universal Time).
+	GPSIFD_TagType_GPSSatellites:        `GPSIFD_TagType_GPSSatellites`,        // ingore # Indicates the GPS satellites used for measurements.
+	GPSIFD_TagType_GPSStatus:            `GPSIFD_TagType_GPSStatus`,            // ingore # Indicates the status of the GPS receiver when the image is recorded.
+	GPSIFD_TagType_GPSMeasureMode:       `GPSIFD_TagType_GPSMeasureMode`,       // ingore # Indicates the GPS measurement mode.
+	GPSIFD_TagType_GPSDOP:               `GPSIFD_TagType_GPSDOP`,               // ingore # Indicates the GPS DOP (data degree of precision).
+	GPSIFD_TagType_GPSSpeedRef:          `GPSIFD_TagType_GPSSpeedRef`,          // ingore # Indicates the unit used to express the GPS receiver speed of movement.
+	GPSIFD_TagType_GPSSpeed:             `GPSIFD_TagType_GPSSpeed`,             // ingore # Indicates the speed of GPS receiver movement.
+	GPSIFD_TagType_GPSTrackRef:          `GPSIFD_TagType_GPSTrackRef`,          // ingore # Indicates the reference for giving the direction of GPS receiver movement.
+	GPSIFD_TagType_GPSTrack:             `GPSIFD_TagType_GPSTrack`,             // ingore # Indicates the direction of GPS receiver movement.
+	GPSIFD_TagType_GPSImgDirectionRef:   `GPSIFD_TagType_GPSImgDirectionRef`,   // ingore # Indicates the reference for giving the direction of the image when it is captured.
+	GPSIFD_TagType_GPSImgDirection:      `GPSIFD_TagType_GPSImgDirection`,      // ingore # Indicates the direction of the image when it was captured.
+	GPSIFD_TagType_GPSMapDatum:          `GPSIFD_TagType_GPSMapDatum`,          // ingore # Indicates the geodetic survey data used by the GPS receiver.
+	GPSIFD_TagType_GPSDestLatitudeRef:   `GPSIFD_TagType_GPSDestLatitudeRef`,   // ingore # Indicates whether the latitude of the destination point is north or south latitude.
+	GPSIFD_TagType_GPSDestLatitude:      `GPSIFD_TagType_GPSDestLatitude`,      // ingore # Indicates the latitude of the destination point.
+	GPSIFD_TagType_GPSDestLongitudeRef:  `GPSIFD_TagType_GPSDestLongitudeRef`,  // ingore # Indicates whether the longitude of the destination point is east or west longitude.
+	GPSIFD_TagType_GPSDestLongitude:     `GPSIFD_TagType_GPSDestLongitude`,     // ingore # Indicates the longitude of the destination point.
+	GPSIFD_TagType_GPSDestBearingRef:    `GPSIFD_TagType_GPSDestBearingRef`,    // ingore # Indicates the reference used for giving the bearing to the destination point.
+	GPSIFD_TagType_GPSDestBearing:       `GPSIFD_TagType_GPSDestBearing`,       // ingore # Indicates the bearing to the destination point.
+	GPSIFD_TagType_GPSDestDistanceRef:   `GPSIFD_TagType_GPSDestDistanceRef`,   // ingore # Indicates the unit used to express the distance to the destination point.
+	GPSIFD_TagType_GPSDestDistance:      `GPSIFD_TagType_GPSDestDistance`,      // ingore # Indicates the distance to the destination point.
+	GPSIFD_TagType_GPSProcessingMethod:  `GPSIFD_TagType_GPSProcessingMethod`,  // ingore # A character string recording the name of the method used for location finding.
+	GPSIFD_TagType_GPSAreaInformation:   `GPSIFD_TagType_GPSAreaInformation`,   // ingore # A character string recording the name of the GPS area.
+	GPSIFD_TagType_GPSDateStamp:         `GPSIFD_TagType_GPSDateStamp`,         // ingore # A character string recording date and time information relative to UTC (Coordinated Universal Time).
+	GPSIFD_TagType_GPSDifferential:      `GPSIFD_TagType_GPSDifferential`,      // ingore # Indicates whether differential correction is applied to the GPS receiver.
+	GPSIFD_TagType_GPSHPositioningError: `GPSIFD_TagType_GPSHPositioningError`, // ingore # Indicates the horizontal positioning error in meters.
 }
 
 func (p GPSIFD_TagType) String() string {